@@ -0,0 +1,141 @@
+// Package ambient picks a handful of ambient-style contacts to suggest each
+// day or week. The pick is weighted towards contacts not heard from in a
+// while and closer relationships, and is the same all period.
+package ambient
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// defaultCount is how many contacts are picked when the config doesn't say
+const defaultCount = 3
+
+// neverContactedDays is the weight given to contacts never contacted
+const neverContactedDays = 180
+
+// maxDays caps the days-since-contact weight so a few long-lost contacts
+// don't crowd out everyone else
+const maxDays = 365
+
+// typeWeights favours closer relationships
+var typeWeights = map[model.RelationshipType]float64{
+	model.RelationshipClose:   3,
+	model.RelationshipFamily:  3,
+	model.RelationshipWork:    2,
+	model.RelationshipNetwork: 2,
+}
+
+// Pick returns the ambient contacts suggested for the current day or week,
+// most strongly suggested first. Snoozed contacts are left out.
+func Pick(contacts []model.Contact, settings config.Ambient) []model.Contact {
+	count := settings.Count
+	if count <= 0 {
+		count = defaultCount
+	}
+	now := clock.Now()
+	seed := PeriodKey(settings.Period, now)
+	start := PeriodStart(settings.Period, now)
+
+	// Weighted sampling without replacement: each contact draws a key from
+	// its own seeded random number and the smallest keys win. A contact's key
+	// depends only on its weight and identity, so the pick doesn't change
+	// with load order or with edits to other contacts.
+	type candidate struct {
+		contact model.Contact
+		key     float64
+	}
+	var candidates []candidate
+	for _, c := range contacts {
+		if c.ContactStyle != model.StyleAmbient || c.IsSnoozed() || c.IsArchived() {
+			continue
+		}
+		u := unitHash(seed, identity(c))
+		candidates = append(candidates, candidate{contact: c, key: -math.Log(u) / Weight(c, start)})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].key < candidates[j].key
+	})
+
+	var picked []model.Contact
+	for i := 0; i < len(candidates) && i < count; i++ {
+		picked = append(picked, candidates[i].contact)
+	}
+	return picked
+}
+
+// Weight returns how strongly a contact is suggested as of at: days since the
+// last contact times the relationship type's weight. Pick weighs contacts as of
+// the start of the period so the pick holds all period.
+func Weight(c model.Contact, at time.Time) float64 {
+	days := neverContactedDays
+	if c.LastContacted != nil {
+		days = model.DaysBetween(*c.LastContacted, at)
+	}
+	if days > maxDays {
+		days = maxDays
+	}
+	if days < 1 {
+		days = 1
+	}
+
+	typeWeight, ok := typeWeights[c.RelationshipType]
+	if !ok {
+		typeWeight = 1
+	}
+	return float64(days) * typeWeight
+}
+
+// PeriodKey names the day ("2025-03-15") or ISO week ("2025-W11") containing t
+func PeriodKey(period string, t time.Time) string {
+	if period == "week" {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return t.Format("2006-01-02")
+}
+
+// PeriodStart returns the start of the day, or the Monday of the week,
+// containing t
+func PeriodStart(period string, t time.Time) time.Time {
+	start := model.StartOfDay(t)
+	if period == "week" {
+		offset := (int(start.Weekday()) + 6) % 7 // Days since Monday
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
+
+// identity returns a stable name for a contact to seed its draw
+func identity(c model.Contact) string {
+	if c.Identifier != "" {
+		return c.Identifier
+	}
+	return c.Title
+}
+
+// unitHash maps the seed and name to a number in (0, 1)
+func unitHash(seed, name string) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	h.Write([]byte{0})
+	h.Write([]byte(name))
+
+	// FNV's high bits barely change between similar inputs, so mix them
+	// (the splitmix64 finalizer) before taking the top 53 bits
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return (float64(x>>11) + 0.5) / (1 << 53)
+}
@@ -0,0 +1,133 @@
+package ambient
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// ambientContacts returns n ambient contacts last contacted 1..n days before now
+func ambientContacts(now time.Time, n int) []model.Contact {
+	var contacts []model.Contact
+	for i := 1; i <= n; i++ {
+		last := now.AddDate(0, 0, -i)
+		contacts = append(contacts, model.Contact{
+			Title:         fmt.Sprintf("Contact %d", i),
+			Identifier:    fmt.Sprintf("id-%d", i),
+			ContactStyle:  model.StyleAmbient,
+			LastContacted: &last,
+		})
+	}
+	return contacts
+}
+
+// titles lists the contacts' titles in order
+func titles(contacts []model.Contact) []string {
+	var out []string
+	for _, c := range contacts {
+		out = append(out, c.Title)
+	}
+	return out
+}
+
+func TestPickIsStableForThePeriod(t *testing.T) {
+	monday := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.Local)
+	contacts := ambientContacts(monday, 20)
+
+	tests := []struct {
+		name   string
+		period string
+		later  time.Time
+		same   bool
+	}{
+		{"same day later on", "day", monday.Add(8 * time.Hour), true},
+		{"next day", "day", monday.AddDate(0, 0, 1), false},
+		{"same week", "week", monday.AddDate(0, 0, 4), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := config.Ambient{Count: 5, Period: tt.period}
+
+			previous := clock.Set(clock.Fixed(monday))
+			first := titles(Pick(contacts, settings))
+			clock.Set(clock.Fixed(tt.later))
+			second := titles(Pick(contacts, settings))
+			clock.Set(previous)
+
+			// Reversing the input must not change the pick
+			reversed := make([]model.Contact, len(contacts))
+			for i, c := range contacts {
+				reversed[len(contacts)-1-i] = c
+			}
+			clock.Set(clock.Fixed(monday))
+			again := titles(Pick(reversed, settings))
+			clock.Set(previous)
+
+			if len(first) != 5 {
+				t.Fatalf("picked %d contacts, want 5", len(first))
+			}
+			if fmt.Sprint(first) != fmt.Sprint(again) {
+				t.Errorf("pick depends on order: %v vs %v", first, again)
+			}
+			if got := fmt.Sprint(first) == fmt.Sprint(second); got != tt.same {
+				t.Errorf("same pick = %v, want %v (%v vs %v)", got, tt.same, first, second)
+			}
+		})
+	}
+}
+
+func TestPickSkipsOtherStylesSnoozedAndArchived(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.Local)
+	previous := clock.Set(clock.Fixed(now))
+	defer clock.Set(previous)
+
+	until := now.AddDate(0, 0, 5)
+	contacts := []model.Contact{
+		{Title: "Ambient", Identifier: "a", ContactStyle: model.StyleAmbient},
+		{Title: "Periodic", Identifier: "p", ContactStyle: model.StylePeriodic},
+		{Title: "Snoozed", Identifier: "s", ContactStyle: model.StyleAmbient, SnoozedUntil: &until},
+		{Title: "Archived", Identifier: "x", ContactStyle: model.StyleAmbient, Archived: true},
+	}
+
+	got := titles(Pick(contacts, config.Ambient{Count: 3}))
+	if fmt.Sprint(got) != "[Ambient]" {
+		t.Errorf("Pick() = %v, want [Ambient]", got)
+	}
+}
+
+func TestWeight(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.Local)
+	previous := clock.Set(clock.Fixed(now))
+	defer clock.Set(previous)
+
+	daysAgo := func(n int) *time.Time {
+		d := now.AddDate(0, 0, -n)
+		return &d
+	}
+
+	tests := []struct {
+		name    string
+		contact model.Contact
+		want    float64
+	}{
+		{"social 10 days", model.Contact{RelationshipType: model.RelationshipSocial, LastContacted: daysAgo(10)}, 10},
+		{"close 10 days", model.Contact{RelationshipType: model.RelationshipClose, LastContacted: daysAgo(10)}, 30},
+		{"work 10 days", model.Contact{RelationshipType: model.RelationshipWork, LastContacted: daysAgo(10)}, 20},
+		{"contacted today", model.Contact{LastContacted: daysAgo(0)}, 1},
+		{"never contacted", model.Contact{}, neverContactedDays},
+		{"capped", model.Contact{LastContacted: daysAgo(1000)}, maxDays},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Weight(tt.contact, now); got != tt.want {
+				t.Errorf("Weight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
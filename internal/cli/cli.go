@@ -0,0 +1,90 @@
+// Package cli implements the non-interactive subcommands, such as digest.
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/gitsync"
+	"github.com/mph-llm-experiments/denote-contacts/internal/journal"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+	"github.com/mph-llm-experiments/denote-contacts/internal/trigger"
+)
+
+// Env is what every command runs against
+type Env struct {
+	ContactsDir string
+	Config      *config.Config
+	Journal     *journal.Journal // Records changes for undo; may be nil
+	Git         *gitsync.Syncer  // Commits changes to git; may be nil
+	Out         io.Writer
+}
+
+// command is a subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(env Env, args []string) error
+}
+
+// commands lists the subcommands in the order shown in usage
+var commands = []command{
+	{"digest", "print what's due today, upcoming dates and ambient suggestions", runDigest},
+	{"dedupe", "list likely duplicate contacts, or merge two with --merge", runDedupe},
+	{"export", "write contacts in another format: dot, vcard, csv, ics", runExport},
+	{"import", "create contacts from another format: vcard, csv", runImport},
+	{"restore", "bring contacts back from the trash or the archive", runRestore},
+	{"history", "list recent changes that can be undone", runHistory},
+	{"undo", "undo the most recent change", runUndo},
+	{"redo", "redo the most recently undone change", runRedo},
+}
+
+// Run runs the subcommand named by args[0]
+func Run(env Env, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given\n%s", Usage())
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(env, args[1:])
+		}
+	}
+	return fmt.Errorf("unknown command %q\n%s", args[0], Usage())
+}
+
+// IsCommand reports whether name is a subcommand
+func IsCommand(name string) bool {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage lists the subcommands
+func Usage() string {
+	var b strings.Builder
+	b.WriteString("Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	return b.String()
+}
+
+// loadContacts loads every contact with trigger rules applied, sorted by name
+func loadContacts(env Env) ([]model.Contact, error) {
+	contacts, err := parser.LoadContacts(env.ContactsDir)
+	if err != nil {
+		return nil, err
+	}
+	trigger.Apply(contacts)
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return strings.ToLower(contacts[i].Title) < strings.ToLower(contacts[j].Title)
+	})
+	return contacts, nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/dedupe"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// runDedupe lists likely duplicates with their fields side by side, or
+// merges two contacts given by identifier
+func runDedupe(env Env, args []string) error {
+	fs := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	fs.SetOutput(env.Out)
+	merge := fs.Bool("merge", false, "merge the two contacts whose identifiers follow into the older one")
+	take := fs.String("take", "", "with --merge, comma-separated fields to take from the newer contact, e.g. email,phone")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	contacts, err := loadContacts(env)
+	if err != nil {
+		return err
+	}
+
+	if *merge {
+		if fs.NArg() != 2 {
+			return fmt.Errorf("--merge takes two contact identifiers, after any other flags")
+		}
+		return journaled(env, func() (string, error) {
+			return mergeContacts(env, contacts, fs.Arg(0), fs.Arg(1), *take)
+		})
+	}
+
+	candidates := dedupe.Find(contacts)
+	if len(candidates) == 0 {
+		fmt.Fprintln(env.Out, "No likely duplicates.")
+		return nil
+	}
+	for i, c := range candidates {
+		keep, lose := contacts[c.A], contacts[c.B]
+		fmt.Fprintf(env.Out, "%d. %s  ⇐  %s   %.0f%%: %s\n", i+1, keep.Title, lose.Title, c.Score*100, strings.Join(c.Reasons, ", "))
+		fmt.Fprintf(env.Out, "   %-10s %-32s %s\n", "", keep.Identifier+" (kept)", lose.Identifier)
+		for _, d := range dedupe.Compare(keep, lose) {
+			mark := " "
+			if d.Conflict {
+				mark = "≠"
+			}
+			fmt.Fprintf(env.Out, "   %-10s %-32s %s %s\n", d.Field, truncate(d.Keep, 32), mark, d.Lose)
+		}
+		fmt.Fprintf(env.Out, "   merge: denote-contacts dedupe --merge %s %s\n\n", keep.Identifier, lose.Identifier)
+	}
+	return nil
+}
+
+// mergeContacts merges two contacts by identifier into the older one and
+// returns a summary of the merge
+func mergeContacts(env Env, contacts []model.Contact, idA, idB, take string) (string, error) {
+	a, okA := findIdentifier(contacts, idA)
+	b, okB := findIdentifier(contacts, idB)
+	switch {
+	case !okA:
+		return "", fmt.Errorf("no contact with identifier %s", idA)
+	case !okB:
+		return "", fmt.Errorf("no contact with identifier %s", idB)
+	case a.FilePath == b.FilePath:
+		return "", fmt.Errorf("can't merge a contact with itself")
+	}
+	keep, lose := a, b
+	if dedupe.Older(b, a) {
+		keep, lose = b, a
+	}
+
+	fields := make(map[string]bool)
+	for _, name := range strings.Split(take, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, f := range dedupe.Fields {
+			known = known || f.Name == name
+		}
+		if !known {
+			return "", fmt.Errorf("unknown field %q for --take", name)
+		}
+		fields[name] = true
+	}
+
+	result, err := dedupe.Apply(contacts, keep, lose, fields, env.ContactsDir, parser.TasksDir())
+	action := fmt.Sprintf("Merged %s into %s", lose.Title, keep.Title)
+	if err != nil {
+		return action, err
+	}
+	fmt.Fprintf(env.Out, "Merged %s into %s (%s)\n", lose.Title, result.Merged.Title, keep.Identifier)
+	fmt.Fprintf(env.Out, "Moved %s to the trash\n", result.Trashed)
+	if len(result.Others) > 0 {
+		fmt.Fprintf(env.Out, "Updated links in %d contacts\n", len(result.Others))
+	}
+	if result.Tasks > 0 {
+		fmt.Fprintf(env.Out, "Pointed %d tasks at %s\n", result.Tasks, keep.Identifier)
+	}
+	return action, nil
+}
+
+// findIdentifier returns the contact with the Denote identifier
+func findIdentifier(contacts []model.Contact, id string) (model.Contact, bool) {
+	for _, c := range contacts {
+		if c.Identifier == id {
+			return c, true
+		}
+	}
+	return model.Contact{}, false
+}
+
+// truncate shortens s to n runes with an ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/ambient"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// digestItem is a contact with the note shown beside it
+type digestItem struct {
+	contact model.Contact
+	note    string
+	date    time.Time // Date of an upcoming event, for sorting
+}
+
+// runDigest prints overdue and due contacts, upcoming dates and the ambient
+// pick, suitable for a daily email or terminal greeting
+func runDigest(env Env, args []string) error {
+	fs := flag.NewFlagSet("digest", flag.ContinueOnError)
+	fs.SetOutput(env.Out)
+	days := fs.Int("days", 7, "list birthdays and other dates this many days ahead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	contacts, err := loadContacts(env)
+	if err != nil {
+		return err
+	}
+
+	now := clock.Now()
+	var overdue, today []digestItem
+	for _, c := range contacts {
+		if c.IsSnoozed() && c.DueSource() != "deadline" {
+			continue
+		}
+		due, ok := c.DaysUntilDue()
+		if !ok || due > 0 {
+			continue
+		}
+		note := dueNote(c)
+		if due < 0 {
+			note = fmt.Sprintf("%d days overdue%s", -due, note)
+			overdue = append(overdue, digestItem{contact: c, note: note})
+		} else {
+			today = append(today, digestItem{contact: c, note: "due today" + note})
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		a, _ := overdue[i].contact.DaysUntilDue()
+		b, _ := overdue[j].contact.DaysUntilDue()
+		return a < b
+	})
+
+	var upcoming []digestItem
+	for _, c := range contacts {
+		for _, o := range c.UpcomingEvents(*days) {
+			note := o.Date.Format("Mon Jan 2") + "  " + o.Label() + o.YearsNote()
+			upcoming = append(upcoming, digestItem{contact: c, note: note, date: o.Date})
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].date.Before(upcoming[j].date)
+	})
+
+	var maybe []digestItem
+	for _, c := range ambient.Pick(contacts, env.Config.Ambient) {
+		note := "never contacted"
+		if d := c.DaysSinceContact(); d >= 0 {
+			note = fmt.Sprintf("last contacted %d days ago", d)
+		}
+		maybe = append(maybe, digestItem{contact: c, note: note})
+	}
+
+	fmt.Fprintf(env.Out, "Contacts digest for %s\n", now.Format("Monday, January 2, 2006"))
+	printSection(env, "Overdue", overdue)
+	printSection(env, "Due today", today)
+	printSection(env, fmt.Sprintf("Upcoming dates, next %d days", *days), upcoming)
+	printSection(env, "Maybe reach out", maybe)
+	return nil
+}
+
+// dueNote says why a contact is due when it isn't the regular rhythm
+func dueNote(c model.Contact) string {
+	if t, ok := c.DueTrigger(); ok {
+		return " (" + t.Reason + ")"
+	}
+	switch source := c.DueSource(); source {
+	case "follow-up", "deadline":
+		return " (" + source + ")"
+	}
+	return ""
+}
+
+// printSection prints a titled list, skipping empty sections
+func printSection(env Env, title string, items []digestItem) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(env.Out, "\n%s (%d)\n", title, len(items))
+	for _, item := range items {
+		fmt.Fprintf(env.Out, "  %-30s  %s\n", item.contact.Title, item.note)
+	}
+}
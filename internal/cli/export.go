@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/csvmap"
+	"github.com/mph-llm-experiments/denote-contacts/internal/graph"
+	"github.com/mph-llm-experiments/denote-contacts/internal/ical"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/vcard"
+)
+
+// exporter writes contacts in one format
+type exporter struct {
+	format  string
+	summary string
+	run     func(env Env, fs *flag.FlagSet, args []string) error
+}
+
+// exporters lists the export formats in the order shown in usage
+var exporters = []exporter{
+	{"dot", "related contacts graph for Graphviz", exportDOT},
+	{"vcard", "contacts as vCards for phones and mail clients", exportVCard},
+	{"csv", "contacts as spreadsheet rows, with a column mapping or preset", exportCSV},
+	{"ics", "due dates, follow-ups, meetings and birthdays as a calendar", exportICS},
+}
+
+// runExport writes contacts in the format named by args[0]
+func runExport(env Env, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no export format given\n%s", exportUsage())
+	}
+	for _, e := range exporters {
+		if e.format == args[0] {
+			fs := flag.NewFlagSet("export "+e.format, flag.ContinueOnError)
+			fs.SetOutput(env.Out)
+			return e.run(env, fs, args[1:])
+		}
+	}
+	return fmt.Errorf("unknown export format %q\n%s", args[0], exportUsage())
+}
+
+// exportUsage lists the export formats
+func exportUsage() string {
+	var b strings.Builder
+	b.WriteString("Formats:\n")
+	for _, e := range exporters {
+		fmt.Fprintf(&b, "  %-10s %s\n", e.format, e.summary)
+	}
+	return b.String()
+}
+
+// outputFlag adds the -o flag shared by every format
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", "", "write to this file instead of standard output")
+}
+
+// withOutput calls write with the file named by path, or with env.Out when
+// path is empty
+func withOutput(env Env, path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(env.Out)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportDOT writes the graph of related contacts. Pipe it through Graphviz,
+// e.g. `denote-contacts export dot | dot -Tsvg > contacts.svg`
+func exportDOT(env Env, fs *flag.FlagSet, args []string) error {
+	output := outputFlag(fs)
+	all := fs.Bool("all", false, "include contacts with no related contacts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	contacts, err := loadContacts(env)
+	if err != nil {
+		return err
+	}
+	return withOutput(env, *output, func(w io.Writer) error {
+		return graph.WriteDOT(w, contacts, *all)
+	})
+}
+
+// exportVCard writes contacts as vCards: all but the archived, or those
+// named by identifier. They go into one bundle, or with --dir one file each.
+func exportVCard(env Env, fs *flag.FlagSet, args []string) error {
+	output := outputFlag(fs)
+	dir := fs.String("dir", "", "write one .vcf file per contact into this directory")
+	archived := fs.Bool("archived", false, "include archived contacts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	contacts, err := selectContacts(env, fs.Args(), *archived)
+	if err != nil {
+		return err
+	}
+
+	if *dir == "" {
+		return withOutput(env, *output, func(w io.Writer) error {
+			return vcard.Write(w, contacts)
+		})
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return err
+	}
+	for _, c := range contacts {
+		if err := vcard.Save(filepath.Join(*dir, vcard.FileName(c)), []model.Contact{c}); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(env.Out, "Wrote %d vCards to %s\n", len(contacts), *dir)
+	return nil
+}
+
+// exportCSV writes contacts as CSV rows: all but the archived, or those
+// named by identifier. Columns follow a preset or mapping file, or are
+// named after the fields.
+func exportCSV(env Env, fs *flag.FlagSet, args []string) error {
+	output := outputFlag(fs)
+	mapping := addMappingFlags(fs)
+	archived := fs.Bool("archived", false, "include archived contacts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	m, err := mapping.load()
+	if err != nil {
+		return err
+	}
+
+	contacts, err := selectContacts(env, fs.Args(), *archived)
+	if err != nil {
+		return err
+	}
+	return withOutput(env, *output, func(w io.Writer) error {
+		return csvmap.Write(w, contacts, m)
+	})
+}
+
+// exportICS writes a calendar of contact dates: all but the archived, or
+// those named by identifier. Write it to a file a calendar app subscribes
+// to, and export again to update it.
+func exportICS(env Env, fs *flag.FlagSet, args []string) error {
+	output := outputFlag(fs)
+	archived := fs.Bool("archived", false, "include archived contacts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	contacts, err := selectContacts(env, fs.Args(), *archived)
+	if err != nil {
+		return err
+	}
+	return withOutput(env, *output, func(w io.Writer) error {
+		return ical.Write(w, contacts)
+	})
+}
+
+// selectContacts returns the contacts with the given identifiers, or with
+// none given all of them, leaving out the archived unless asked
+func selectContacts(env Env, ids []string, archived bool) ([]model.Contact, error) {
+	all, err := loadContacts(env)
+	if err != nil {
+		return nil, err
+	}
+	var contacts []model.Contact
+	if len(ids) > 0 {
+		for _, id := range ids {
+			c, ok := findIdentifier(all, id)
+			if !ok {
+				return nil, fmt.Errorf("no contact with identifier %s", id)
+			}
+			contacts = append(contacts, c)
+		}
+		return contacts, nil
+	}
+	for _, c := range all {
+		if archived || !c.IsArchived() {
+			contacts = append(contacts, c)
+		}
+	}
+	return contacts, nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/journal"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// journaled runs fn as one change that can be undone, named by the action
+// fn returns, and commits it to git when that's on
+func journaled(env Env, fn func() (string, error)) error {
+	env.Journal.Begin()
+	action, err := fn()
+	if action == "" && err != nil {
+		action = "Failed: " + err.Error()
+	}
+	op, jerr := env.Journal.Commit(action)
+	if jerr != nil && err == nil {
+		return fmt.Errorf("saved, but the change can't be undone: %v", jerr)
+	}
+	if op != nil {
+		if gerr := env.Git.Record(op.Action, op.Paths()); gerr != nil && err == nil {
+			return fmt.Errorf("saved, but git commit failed: %v", gerr)
+		}
+	}
+	return err
+}
+
+// runHistory lists the most recent changes, newest first, and the changes
+// that can be redone
+func runHistory(env Env, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(env.Out)
+	limit := fs.Int("n", 20, "number of changes to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	done, undone := env.Journal.History()
+	if len(done) == 0 && len(undone) == 0 {
+		fmt.Fprintln(env.Out, "No changes recorded.")
+		return nil
+	}
+	if len(undone) > 0 {
+		fmt.Fprintln(env.Out, "Undone (redo applies the first):")
+		for _, op := range undone {
+			printOp(env, op)
+		}
+		fmt.Fprintln(env.Out)
+	}
+	if len(done) > 0 {
+		fmt.Fprintln(env.Out, "Changes (undo reverts the first):")
+		for i := len(done) - 1; i >= 0 && i >= len(done)-*limit; i-- {
+			printOp(env, done[i])
+		}
+	}
+	return nil
+}
+
+// printOp prints one change and the files it touched, relative to the
+// contacts directory when inside it
+func printOp(env Env, op journal.Op) {
+	dir, _ := filepath.Abs(env.ContactsDir)
+	var files []string
+	for _, c := range op.Changes {
+		name := filepath.Base(c.Path)
+		if rel, err := filepath.Rel(dir, c.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+		files = append(files, name)
+	}
+	fmt.Fprintf(env.Out, "  %s  %s\n", op.Time.Format("2006-01-02 15:04"), op.Action)
+	fmt.Fprintf(env.Out, "                    %s\n", truncate(strings.Join(files, ", "), 72))
+}
+
+// runUndo reverts the most recent change
+func runUndo(env Env, args []string) error {
+	if err := parser.Writable(); err != nil {
+		return err
+	}
+	op, err := env.Journal.Undo()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Out, "Undid: %s\n", op.Action)
+	return env.Git.Record("Undo: "+op.Action, op.Paths())
+}
+
+// runRedo applies the most recently undone change again
+func runRedo(env Env, args []string) error {
+	if err := parser.Writable(); err != nil {
+		return err
+	}
+	op, err := env.Journal.Redo()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Out, "Redid: %s\n", op.Action)
+	return env.Git.Record("Redo: "+op.Action, op.Paths())
+}
@@ -0,0 +1,368 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/csvmap"
+	"github.com/mph-llm-experiments/denote-contacts/internal/dedupe"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+	"github.com/mph-llm-experiments/denote-contacts/internal/vcard"
+)
+
+// importer reads contacts from one format
+type importer struct {
+	format  string
+	summary string
+	run     func(env Env, fs *flag.FlagSet, args []string) error
+}
+
+// importers lists the import formats in the order shown in usage
+var importers = []importer{
+	{"vcard", "contacts from a .vcf file (vCard 3.0 or 4.0)", importVCard},
+	{"csv", "contacts from a spreadsheet, with a column mapping or preset", importCSV},
+}
+
+// runImport reads contacts in the format named by args[0]
+func runImport(env Env, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no import format given\n%s", importUsage())
+	}
+	for _, i := range importers {
+		if i.format == args[0] {
+			fs := flag.NewFlagSet("import "+i.format, flag.ContinueOnError)
+			fs.SetOutput(env.Out)
+			return i.run(env, fs, args[1:])
+		}
+	}
+	return fmt.Errorf("unknown import format %q\n%s", args[0], importUsage())
+}
+
+// importUsage lists the import formats
+func importUsage() string {
+	var b strings.Builder
+	b.WriteString("Formats:\n")
+	for _, i := range importers {
+		fmt.Fprintf(&b, "  %-10s %s\n", i.format, i.summary)
+	}
+	return b.String()
+}
+
+// importFlags are the flags shared by every import format
+type importFlags struct {
+	dryRun *bool
+	kind   *string
+}
+
+// addImportFlags adds the shared flags: a dry run, and the relationship type
+// new contacts get
+func addImportFlags(fs *flag.FlagSet) importFlags {
+	return importFlags{
+		dryRun: fs.Bool("dry-run", false, "show what would be imported without writing anything"),
+		kind:   fs.String("type", string(model.RelationshipNetwork), "relationship type for new contacts"),
+	}
+}
+
+// parse reads the shared flags, checking --type before anything is read
+func (f importFlags) parse(fs *flag.FlagSet, args []string) (model.RelationshipType, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	return model.ParseRelationshipType(*f.kind)
+}
+
+// newContactDefaults fills in what the create form would for fields an
+// import left empty
+func newContactDefaults(c *model.Contact, kind model.RelationshipType) {
+	if c.RelationshipType == "" {
+		c.RelationshipType = kind
+	}
+	if c.ContactStyle == "" {
+		c.ContactStyle = model.StylePeriodic
+	}
+	if c.State == "" {
+		c.State = string(model.StateOk)
+	}
+}
+
+// importVCard creates a contact for each card in a .vcf file, skipping cards
+// that match an existing contact, or an earlier card, by identifier, email
+// or phone
+func importVCard(env Env, fs *flag.FlagSet, args []string) error {
+	flags := addImportFlags(fs)
+	kind, err := flags.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: import vcard [--dry-run] [--type TYPE] FILE.vcf")
+	}
+	path := fs.Arg(0)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	cards, err := vcard.Parse(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+
+	var rows []importRow
+	for i, card := range cards {
+		rows = append(rows, importRow{where: fmt.Sprintf("card %d", i+1), contact: card.Contact()})
+	}
+	return importContacts(env, filepath.Base(path), rows, kind, *flags.dryRun, false)
+}
+
+// importCSV creates a contact for each row of a CSV file, or fills in the
+// empty fields of the contact it matches by identifier, email or phone. A
+// row without a name can only update.
+func importCSV(env Env, fs *flag.FlagSet, args []string) error {
+	flags := addImportFlags(fs)
+	mapping := addMappingFlags(fs)
+	kind, err := flags.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: import csv [--dry-run] [--type TYPE] [--preset NAME | --mapping FILE] FILE.csv")
+	}
+	path := fs.Arg(0)
+	m, err := mapping.load()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	read, err := csvmap.Read(f, m)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %v", filepath.Base(path), err)
+	}
+
+	var rows []importRow
+	for _, r := range read {
+		rows = append(rows, importRow{where: fmt.Sprintf("row %d", r.Number), contact: r.Contact, err: r.Err})
+	}
+	return importContacts(env, filepath.Base(path), rows, kind, *flags.dryRun, true)
+}
+
+// mappingFlags choose how CSV columns map to contact fields
+type mappingFlags struct {
+	preset  *string
+	mapping *string
+}
+
+// addMappingFlags adds the --preset and --mapping flags
+func addMappingFlags(fs *flag.FlagSet) mappingFlags {
+	return mappingFlags{
+		preset:  fs.String("preset", "", "column mapping for another service's export: "+strings.Join(csvmap.Presets(), ", ")),
+		mapping: fs.String("mapping", "", "TOML file mapping column headers to contact fields"),
+	}
+}
+
+// load returns the chosen mapping, or columns named after the fields
+func (f mappingFlags) load() (csvmap.Mapping, error) {
+	switch {
+	case *f.preset != "" && *f.mapping != "":
+		return csvmap.Mapping{}, fmt.Errorf("use --preset or --mapping, not both")
+	case *f.preset != "":
+		m, ok := csvmap.Preset(*f.preset)
+		if !ok {
+			return csvmap.Mapping{}, fmt.Errorf("unknown preset %q: use one of %s", *f.preset, strings.Join(csvmap.Presets(), ", "))
+		}
+		return m, nil
+	case *f.mapping != "":
+		return csvmap.LoadMapping(*f.mapping)
+	}
+	return csvmap.Default(), nil
+}
+
+// importRow is one incoming contact, or why it can't be used
+type importRow struct {
+	where   string // e.g. "row 4", for the report
+	contact model.Contact
+	err     error
+}
+
+// importContacts creates the incoming contacts that don't match one already
+// there, as kind unless they have a type, printing what happens to each. A
+// match is skipped, or with update has its empty fields filled in. The whole
+// import is one change that can be undone.
+func importContacts(env Env, source string, rows []importRow, kind model.RelationshipType, dryRun, update bool) error {
+	contacts, err := loadContacts(env)
+	if err != nil {
+		return err
+	}
+
+	prefix := ""
+	if dryRun {
+		prefix = "would "
+	}
+	report := func(verb, name, detail string, row importRow) {
+		line := fmt.Sprintf("%-13s %s", prefix+verb, name)
+		if detail != "" {
+			line += ": " + detail
+		}
+		fmt.Fprintf(env.Out, "%s (%s)\n", line, row.where)
+	}
+
+	created, updated, skipped := 0, 0, 0
+	run := func() (string, error) {
+		// Created contacts share a time a second apart, so each has its
+		// own identifier
+		date := clock.Now()
+		for _, row := range rows {
+			c := row.contact
+			name := c.Title
+			if name == "" {
+				name = "(no name)"
+			}
+			if row.err != nil {
+				report("skip", name, row.err.Error(), row)
+				skipped++
+				continue
+			}
+
+			i, reason := dedupe.Match(contacts, c)
+			if i >= 0 && !update {
+				report("skip", name, fmt.Sprintf("matches %s, %s", contacts[i].Title, reason), row)
+				skipped++
+				continue
+			}
+			if i >= 0 {
+				existing := contacts[i]
+				changed := fillEmpty(&existing, c)
+				if len(changed) == 0 {
+					report("skip", name, fmt.Sprintf("matches %s, %s, nothing new", contacts[i].Title, reason), row)
+					skipped++
+					continue
+				}
+				if err := model.CheckLabel(contacts, existing); err != nil {
+					report("skip", c.Title, err.Error(), row)
+					skipped++
+					continue
+				}
+				if !dryRun {
+					if err := parser.SaveContactFile(existing); err != nil {
+						return importAction(created, updated, source), err
+					}
+				}
+				contacts[i] = existing
+				report("update", existing.Title, strings.Join(changed, ", "), row)
+				updated++
+				continue
+			}
+
+			if c.Title == "" {
+				report("skip", name, "no name and no match", row)
+				skipped++
+				continue
+			}
+			if err := model.CheckLabel(contacts, c); err != nil {
+				report("skip", c.Title, err.Error(), row)
+				skipped++
+				continue
+			}
+			// A dry run names the file too, so it fails where the import would
+			newContactDefaults(&c, kind)
+			c.Date = date
+			c, err := parser.NewContactFile(env.ContactsDir, c)
+			if err != nil {
+				return importAction(created, updated, source), err
+			}
+			if !dryRun {
+				if err := parser.SaveContactFile(c); err != nil {
+					return importAction(created, updated, source), fmt.Errorf("failed to save contact '%s': %v", c.Title, err)
+				}
+			}
+			date = c.Date.Add(time.Second)
+			report("create", c.Title, filepath.Base(c.FilePath), row)
+			contacts = append(contacts, c)
+			created++
+		}
+		return importAction(created, updated, source), nil
+	}
+
+	if dryRun {
+		if _, err := run(); err != nil {
+			return err
+		}
+	} else if err := journaled(env, run); err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("Created %d, updated %d, skipped %d", created, updated, skipped)
+	if dryRun {
+		summary = fmt.Sprintf("Would create %d, update %d, skip %d (dry run, nothing written)", created, updated, skipped)
+	}
+	fmt.Fprintln(env.Out, summary)
+	return nil
+}
+
+// fillEmpty copies the incoming values of fields the contact has empty,
+// adds new tags and keeps the later last contacted date. It returns the
+// names of the fields it changed.
+func fillEmpty(c *model.Contact, incoming model.Contact) []string {
+	var changed []string
+	for _, f := range dedupe.Fields {
+		if f.Name == "name" {
+			continue
+		}
+		if f.Get(*c) == "" && f.Get(incoming) != "" {
+			f.Set(c, f.Get(incoming))
+			changed = append(changed, f.Name)
+		}
+	}
+	if c.Notes == "" && incoming.Notes != "" {
+		c.Notes = incoming.Notes
+		changed = append(changed, "notes")
+	}
+	var added bool
+	for _, tag := range incoming.Tags {
+		if !containsTag(c.Tags, tag) {
+			c.Tags = append(c.Tags, tag)
+			added = true
+		}
+	}
+	if added {
+		changed = append(changed, "tags")
+	}
+	if incoming.LastContacted != nil && (c.LastContacted == nil || incoming.LastContacted.After(*c.LastContacted)) {
+		c.LastContacted = incoming.LastContacted
+		changed = append(changed, "last contacted")
+	}
+	return changed
+}
+
+// containsTag reports whether tags has tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// importAction names an import in the history
+func importAction(created, updated int, source string) string {
+	switch {
+	case created == 0 && updated > 0:
+		return fmt.Sprintf("Updated %d contacts from %s", updated, source)
+	case updated > 0:
+		return fmt.Sprintf("Imported %d and updated %d contacts from %s", created, updated, source)
+	}
+	return fmt.Sprintf("Imported %d contacts from %s", created, source)
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// existing are the contacts already in the directory for every import
+var existing = []model.Contact{
+	{Title: "Sarah Chen", Email: "sarah@acme.example", RelationshipType: model.RelationshipWork},
+	{Title: "Bob Stone", Phone: "+1 555 010 2000", Company: "Initech", RelationshipType: model.RelationshipNetwork},
+}
+
+// contactsByTitle loads the contacts in dir keyed by title
+func contactsByTitle(t *testing.T, dir string) map[string]model.Contact {
+	t.Helper()
+	contacts, err := parser.LoadContacts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byTitle := make(map[string]model.Contact)
+	for _, c := range contacts {
+		byTitle[c.Title] = c
+	}
+	return byTitle
+}
+
+func TestImport(t *testing.T) {
+	now := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+	previous := clock.Set(clock.Fixed(now))
+	t.Cleanup(func() { clock.Set(previous) })
+
+	const csvFile = "title,email,phone,company,role,relationship_type\n" +
+		"Ann Lee,ann@globex.example,,Globex,,\n" +
+		"Sarah C.,Sarah+news@Acme.example,,Acme,Director,\n" +
+		",,555-010-2000,Globex,Engineer,\n" +
+		"Cal Ray,cal@example.com,,,,friend\n" +
+		",nobody@example.com,,,,\n" +
+		"Sarah Chen,sarah@acme.example,,,,\n"
+
+	const vcardFile = "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Ann Lee\r\nEMAIL:ann@globex.example\r\nEND:VCARD\r\n" +
+		"BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Sarah C.\r\nEMAIL:sarah@acme.example\r\nORG:Acme\r\nEND:VCARD\r\n"
+
+	tests := []struct {
+		name   string
+		args   []string // Import format and flags, before the file
+		file   string
+		want   []string                     // Report lines
+		fields map[string]map[string]string // Saved values by title and field; nil for none
+	}{
+		{
+			name: "csv dry run",
+			args: []string{"csv", "--dry-run"},
+			file: csvFile,
+			want: []string{
+				"would create  Ann Lee: 20250610T090000--ann-lee__contact.md (row 2)",
+				"would update  Sarah Chen: company, role (row 3)",
+				"would update  Bob Stone: role (row 4)",
+				`would skip    Cal Ray: relationship_type: unknown type "friend": use one of close, family, network, work, social, providers, recruiters (row 5)`,
+				"would skip    (no name): no name and no match (row 6)",
+				"would skip    Sarah Chen: matches Sarah Chen, same email sarah@acme.example, nothing new (row 7)",
+				"Would create 1, update 2, skip 3 (dry run, nothing written)",
+			},
+			fields: map[string]map[string]string{
+				"Sarah Chen": {"company": "", "role": ""},
+				"Bob Stone":  {"company": "Initech", "role": ""},
+			},
+		},
+		{
+			name: "csv",
+			args: []string{"csv"},
+			file: csvFile,
+			want: []string{
+				"create        Ann Lee: 20250610T090000--ann-lee__contact.md (row 2)",
+				"update        Sarah Chen: company, role (row 3)",
+				"update        Bob Stone: role (row 4)",
+				`skip          Cal Ray: relationship_type: unknown type "friend": use one of close, family, network, work, social, providers, recruiters (row 5)`,
+				"skip          (no name): no name and no match (row 6)",
+				"skip          Sarah Chen: matches Sarah Chen, same email sarah@acme.example, nothing new (row 7)",
+				"Created 1, updated 2, skipped 3",
+			},
+			fields: map[string]map[string]string{
+				"Ann Lee":    {"email": "ann@globex.example", "company": "Globex", "type": "network", "style": "periodic"},
+				"Sarah Chen": {"company": "Acme", "role": "Director", "type": "work", "style": ""}, // Defaults are only for new contacts
+				"Bob Stone":  {"company": "Initech", "role": "Engineer"},
+			},
+		},
+		{
+			name: "csv with a type for new contacts",
+			args: []string{"csv", "--type", "Close"},
+			file: "title,email\nAnn Lee,ann@globex.example\n",
+			want: []string{
+				"create        Ann Lee: 20250610T090000--ann-lee__contact.md (row 2)",
+				"Created 1, updated 0, skipped 0",
+			},
+			fields: map[string]map[string]string{
+				"Ann Lee": {"type": "close"},
+			},
+		},
+		{
+			name: "vcard skips matches",
+			args: []string{"vcard"},
+			file: vcardFile,
+			want: []string{
+				"create        Ann Lee: 20250610T090000--ann-lee__contact.md (card 1)",
+				"skip          Sarah C.: matches Sarah Chen, same email sarah@acme.example (card 2)",
+				"Created 1, updated 0, skipped 1",
+			},
+			fields: map[string]map[string]string{
+				"Sarah Chen": {"company": ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, c := range existing {
+				c.Date = now.Add(-24 * time.Hour)
+				if _, err := parser.CreateContact(dir, c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before := contactsByTitle(t, dir)
+			path := filepath.Join(t.TempDir(), "import")
+			if err := os.WriteFile(path, []byte(tt.file), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out strings.Builder
+			env := Env{ContactsDir: dir, Out: &out}
+			if err := runImport(env, append(tt.args, path)); err != nil {
+				t.Fatalf("import = %v\n%s", err, out.String())
+			}
+
+			got := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+
+			after := contactsByTitle(t, dir)
+			dryRun := len(tt.args) > 1 && tt.args[1] == "--dry-run"
+			if dryRun && len(after) != len(before) {
+				t.Errorf("dry run left %d contacts, want %d", len(after), len(before))
+			}
+			for title, fields := range tt.fields {
+				c, ok := after[title]
+				if !ok {
+					t.Errorf("no contact %s after import", title)
+					continue
+				}
+				values := map[string]string{
+					"email":   c.Email,
+					"company": c.Company,
+					"role":    c.Role,
+					"type":    string(c.RelationshipType),
+					"style":   string(c.ContactStyle),
+				}
+				for field, want := range fields {
+					if values[field] != want {
+						t.Errorf("%s %s = %q, want %q", title, field, values[field], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestImportBadFlags(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"csv", "--type", "friend", "missing.csv"}, `unknown type "friend"`},
+		{[]string{"vcard", "--type", "friend", "missing.vcf"}, `unknown type "friend"`},
+		{[]string{"csv", "--preset", "myspace", "missing.csv"}, `unknown preset "myspace"`},
+		{[]string{"csv", "--preset", "google", "--mapping", "m.toml", "missing.csv"}, "not both"},
+		{[]string{"ldif", "missing.ldif"}, `unknown import format "ldif"`},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		err := runImport(Env{ContactsDir: t.TempDir(), Out: &out}, tt.args)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("import %v = %v, want an error with %q", tt.args, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// runRestore brings contacts back from the trash or the archive by
+// identifier, or lists what can be restored when none are given
+func runRestore(env Env, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.SetOutput(env.Out)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	trashed, err := parser.LoadTrash(env.ContactsDir)
+	if err != nil {
+		return err
+	}
+	contacts, err := loadContacts(env)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return listRestorable(env, trashed, contacts)
+	}
+
+	for _, id := range fs.Args() {
+		err := journaled(env, func() (string, error) {
+			return restoreContact(env, trashed, contacts, id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreContact restores one contact, looking in the trash first. id is a
+// Denote identifier or the name of a file in the trash. It returns what was
+// restored.
+func restoreContact(env Env, trashed, contacts []model.Contact, id string) (string, error) {
+	for _, c := range trashed {
+		if c.Identifier != id && filepath.Base(c.FilePath) != id {
+			continue
+		}
+		path, err := parser.RestoreFromTrash(env.ContactsDir, c.FilePath)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(env.Out, "Restored %s from the trash to %s\n", c.Title, path)
+		return fmt.Sprintf("Restored %s from the trash", c.Title), nil
+	}
+
+	c, ok := findIdentifier(contacts, id)
+	if !ok {
+		return "", fmt.Errorf("no contact with identifier %s in the trash or the archive", id)
+	}
+	if !c.IsArchived() {
+		return "", fmt.Errorf("%s (%s) is not archived", c.Title, id)
+	}
+	c.Archived = false
+	c.ArchivedAt = nil
+	if c.State == string(model.StateArchived) {
+		c.State = string(model.StateActive)
+	}
+	if err := parser.SaveContactFile(c); err != nil {
+		return "", fmt.Errorf("failed to save '%s': %v", c.Title, err)
+	}
+	fmt.Fprintf(env.Out, "Restored %s from the archive\n", c.Title)
+	return fmt.Sprintf("Restored %s from the archive", c.Title), nil
+}
+
+// listRestorable prints the trashed and archived contacts
+func listRestorable(env Env, trashed, contacts []model.Contact) error {
+	var archived []model.Contact
+	for _, c := range contacts {
+		if c.IsArchived() {
+			archived = append(archived, c)
+		}
+	}
+	if len(trashed) == 0 && len(archived) == 0 {
+		fmt.Fprintln(env.Out, "Nothing in the trash or the archive.")
+		return nil
+	}
+
+	if len(trashed) > 0 {
+		fmt.Fprintln(env.Out, "Trash:")
+		for _, c := range trashed {
+			fmt.Fprintf(env.Out, "  %-16s %-28s %s\n", c.Identifier, truncate(c.Title, 28), filepath.Base(c.FilePath))
+		}
+	}
+	if len(archived) > 0 {
+		if len(trashed) > 0 {
+			fmt.Fprintln(env.Out)
+		}
+		fmt.Fprintln(env.Out, "Archived:")
+		for _, c := range archived {
+			since := ""
+			if c.ArchivedAt != nil {
+				since = "since " + c.ArchivedAt.Format("2006-01-02")
+			}
+			fmt.Fprintf(env.Out, "  %-16s %-28s %s\n", c.Identifier, truncate(c.Title, 28), since)
+		}
+	}
+	fmt.Fprintln(env.Out, "\nrestore: denote-contacts restore IDENTIFIER...")
+	return nil
+}
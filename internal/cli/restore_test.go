@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/journal"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+func TestRestore(t *testing.T) {
+	date := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+	archivedAt := date.Add(24 * time.Hour)
+
+	// setup creates Ann in the trash, Bob in the archive and Cal, and
+	// returns the contacts directory and Ann's file name
+	setup := func(t *testing.T) (string, string) {
+		dir := t.TempDir()
+		contacts := []model.Contact{
+			{Title: "Ann Lee", Date: date},
+			{Title: "Bob Stone", Date: date.Add(time.Second), Archived: true, ArchivedAt: &archivedAt, State: string(model.StateArchived)},
+			{Title: "Cal Ray", Date: date.Add(2 * time.Second)},
+		}
+		var ann string
+		for _, c := range contacts {
+			c, err := parser.CreateContact(dir, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Title == "Ann Lee" {
+				ann = filepath.Base(c.FilePath)
+				if _, err := parser.MoveToTrash(dir, c.FilePath); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		return dir, ann
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		clash   bool   // Another contact has taken Ann's file name
+		want    string // Output, or the start of the error
+		wantErr bool
+		active  []string // Contacts in the directory and not archived afterwards
+	}{
+		{
+			name: "list",
+			want: "Trash:\n" +
+				"  20250610T090000  Ann Lee                      20250610T090000--ann-lee__contact.md\n" +
+				"\n" +
+				"Archived:\n" +
+				"  20250610T090001  Bob Stone                    since 2025-06-11\n" +
+				"\n" +
+				"restore: denote-contacts restore IDENTIFIER...\n",
+			active: []string{"Cal Ray"},
+		},
+		{
+			name:   "from the trash by identifier",
+			args:   []string{"20250610T090000"},
+			want:   "Restored Ann Lee from the trash to ",
+			active: []string{"Ann Lee", "Cal Ray"},
+		},
+		{
+			name:   "from the trash by file name, and from the archive",
+			args:   []string{"20250610T090000--ann-lee__contact.md", "20250610T090001"},
+			want:   "Restored Ann Lee from the trash to ",
+			active: []string{"Ann Lee", "Bob Stone", "Cal Ray"},
+		},
+		{
+			name:    "name taken again",
+			args:    []string{"20250610T090000"},
+			clash:   true,
+			want:    "can't restore '20250610T090000--ann-lee__contact.md'",
+			wantErr: true,
+			active:  []string{"Ann Lee (new)", "Cal Ray"},
+		},
+		{
+			name:    "not archived",
+			args:    []string{"20250610T090002"},
+			want:    "Cal Ray (20250610T090002) is not archived",
+			wantErr: true,
+			active:  []string{"Cal Ray"},
+		},
+		{
+			name:    "unknown",
+			args:    []string{"20990101T000000"},
+			want:    "no contact with identifier 20990101T000000",
+			wantErr: true,
+			active:  []string{"Cal Ray"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, ann := setup(t)
+			if tt.clash {
+				c := model.Contact{Title: "Ann Lee (new)", Identifier: "20250610T090000", Date: date, Tags: []string{"contact"}, FilePath: filepath.Join(dir, ann)}
+				if err := parser.SaveContactFile(c); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var out strings.Builder
+			err := runRestore(Env{ContactsDir: dir, Out: &out}, tt.args)
+			switch {
+			case tt.wantErr && (err == nil || !strings.HasPrefix(err.Error(), tt.want)):
+				t.Errorf("restore %v = %v, want an error starting %q", tt.args, err, tt.want)
+			case !tt.wantErr && err != nil:
+				t.Errorf("restore %v = %v", tt.args, err)
+			case !tt.wantErr && !strings.HasPrefix(out.String(), tt.want):
+				t.Errorf("output =\n%s\nwant it to start\n%s", out.String(), tt.want)
+			}
+
+			contacts, err := parser.LoadContacts(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var active []string
+			for _, c := range contacts {
+				if !c.IsArchived() {
+					active = append(active, c.Title)
+				}
+			}
+			if strings.Join(active, ", ") != strings.Join(tt.active, ", ") {
+				t.Errorf("active contacts = %v, want %v", active, tt.active)
+			}
+		})
+	}
+}
+
+func TestRestoreUndo(t *testing.T) {
+	dir := t.TempDir()
+	c, err := parser.CreateContact(dir, model.Contact{Title: "Ann Lee", Date: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashed, err := parser.MoveToTrash(dir, c.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := journal.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser.SetBeforeWrite(j.Touch)
+	t.Cleanup(func() { parser.SetBeforeWrite(nil) })
+	env := Env{ContactsDir: dir, Journal: j, Out: &strings.Builder{}}
+
+	if err := runRestore(env, []string{c.Identifier}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runUndo(env, nil); err != nil {
+		t.Fatalf("undo = %v", err)
+	}
+	if _, err := os.Stat(trashed); err != nil {
+		t.Errorf("undo didn't put the contact back in the trash: %v", err)
+	}
+	if _, err := os.Stat(c.FilePath); !os.IsNotExist(err) {
+		t.Errorf("undo left the restored file: %v", err)
+	}
+}
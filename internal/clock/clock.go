@@ -0,0 +1,70 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock tells the current time. All date logic goes through the package clock
+// so it can be fixed in tests or shifted with --as-of.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the real wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// fixedClock always returns the same instant
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// offsetClock runs at real speed, shifted by a fixed offset
+type offsetClock struct {
+	offset time.Duration
+}
+
+func (c offsetClock) Now() time.Time { return time.Now().Add(c.offset) }
+
+// current is the clock used by Now
+var current Clock = systemClock{}
+
+// System returns the real wall clock
+func System() Clock {
+	return systemClock{}
+}
+
+// Fixed returns a clock stopped at t
+func Fixed(t time.Time) Clock {
+	return fixedClock{t: t}
+}
+
+// AsOf returns a clock that runs at real speed but reads the given date as
+// today, keeping the current time of day
+func AsOf(date time.Time) Clock {
+	now := time.Now()
+	y, m, d := date.Date()
+	shifted := time.Date(y, m, d, now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), time.Local)
+	return offsetClock{offset: shifted.Sub(now)}
+}
+
+// Set replaces the package clock and returns the previous one
+func Set(c Clock) Clock {
+	previous := current
+	current = c
+	return previous
+}
+
+// Now returns the current time from the package clock
+func Now() time.Time {
+	return current.Now()
+}
+
+// Shifted reports whether the package clock is not the real wall clock
+func Shifted() bool {
+	_, ok := current.(systemClock)
+	return !ok
+}
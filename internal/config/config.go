@@ -1,14 +1,100 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
 
 type Config struct {
 	NotesDirectory string `toml:"notes_directory"`
+	DefaultSort    string `toml:"default_sort,omitempty"`    // name, contacted, due, type, company, updated, bumps, created, health
+	SortDescending bool   `toml:"sort_descending,omitempty"` // Reverse the default sort
+	Views          []View `toml:"views,omitempty"`
+	
+	// Bump rules keyed by relationship type, with "default" for the rest
+	Bumps map[string]BumpRule `toml:"bumps,omitempty"`
+	
+	// Days before a birthday or other date that a triggered contact is due
+	EventLeadDays *int `toml:"event_lead_days,omitempty"`
+	
+	// Rules that make contacts due, see the trigger package
+	Triggers []Trigger `toml:"triggers,omitempty"`
+	
+	// The daily or weekly pick of ambient contacts
+	Ambient Ambient `toml:"ambient,omitempty"`
+	
+	// Committing changes when the contacts directory is in a git repository
+	Git Git `toml:"git,omitempty"`
+	
+	// Changes kept in the undo history, default 500
+	HistoryLimit int `toml:"history_limit,omitempty"`
+}
+
+// Git sets whether and how changes are committed to git
+type Git struct {
+	Enabled bool   `toml:"enabled,omitempty"`
+	Commit  string `toml:"commit,omitempty"` // "change" (default) commits after every change, "session" once on exit
+}
+
+// Ambient sets how many ambient contacts to suggest and how often the pick changes
+type Ambient struct {
+	Count  int    `toml:"count,omitempty"`  // Contacts to pick, default 3
+	Period string `toml:"period,omitempty"` // "day" (default) or "week"
+}
+
+// Trigger is a rule that makes contacts due. Kind is "date" (birthdays and
+// named dates of triggered contacts), "campaign" (every contact with a tag)
+// or "logged" (surface contacts when another contact is logged).
+type Trigger struct {
+	Name   string `toml:"name,omitempty"`
+	Kind   string `toml:"kind"`
+	Reason string `toml:"reason,omitempty"` // Replaces the generated reason
+	
+	// Date rules
+	Dates    []string `toml:"dates,omitempty"`     // Date names such as birthday; empty means all
+	LeadDays *int     `toml:"lead_days,omitempty"` // Days before the date that the contact is due
+	
+	// Campaign rules
+	Tag   string `toml:"tag,omitempty"`
+	From  string `toml:"from,omitempty"`  // "YYYY-MM-DD"
+	Until string `toml:"until,omitempty"` // "YYYY-MM-DD", optional
+	
+	// Logged rules
+	When       string   `toml:"when,omitempty"`        // @label, #tag or name
+	Surface    []string `toml:"surface,omitempty"`     // @label, #tag or name
+	WithinDays int      `toml:"within_days,omitempty"` // How long to surface them, default 14
+}
+
+// BumpRule sets how far a bump defers a contact's due date
+type BumpRule struct {
+	DeferDays int `toml:"defer_days"` // Days after the bump before the contact is due again
+	MaxBumps  int `toml:"max_bumps"`  // Consecutive bumps that defer; 0 means no limit
+}
+
+// View is a named search and filter combination, recalled with 1-9 in the list
+type View struct {
+	Name     string   `toml:"name"`
+	Query    string   `toml:"query,omitempty"`
+	Types    []string `toml:"types,omitempty"`
+	States   []string `toml:"states,omitempty"`
+	Statuses []string `toml:"statuses,omitempty"`
+	Tags     []string `toml:"tags,omitempty"`
+	Archived bool     `toml:"archived,omitempty"` // Include archived contacts
+}
+
+// Path returns the config file location
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "denote-contacts", "config.toml"), nil
 }
 
 func Load() (*Config, error) {
@@ -20,7 +106,10 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 	
-	configPath := filepath.Join(homeDir, ".config", "denote-contacts", "config.toml")
+	configPath, err := Path()
+	if err != nil {
+		return nil, err
+	}
 	
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -40,4 +129,147 @@ func Load() (*Config, error) {
 	}
 	
 	return config, nil
-}
\ No newline at end of file
+}
+
+// SaveViews writes the config's views to the config file. Only the
+// [[views]] tables are replaced, so comments and everything else in the
+// file stay as they are. Without a config file the whole config is written.
+func SaveViews(config *Config) error {
+	configPath, err := Path()
+	if err != nil {
+		return err
+	}
+	return saveViews(configPath, config)
+}
+
+// saveViews replaces the [[views]] tables of the file at path
+func saveViews(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return save(path, config)
+	} else if err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+	
+	var buf bytes.Buffer
+	if len(config.Views) > 0 {
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(struct {
+			Views []View `toml:"views"`
+		}{config.Views}); err != nil {
+			return fmt.Errorf("error encoding views: %w", err)
+		}
+	}
+	
+	out := replaceViews(string(data), strings.TrimSpace(buf.String()))
+	
+	// Never write a file that no longer loads
+	if _, err := toml.Decode(out, &Config{}); err != nil {
+		return fmt.Errorf("can't update views in %s: %w", path, err)
+	}
+	return os.WriteFile(path, []byte(out), 0644)
+}
+
+// tableHeader matches a TOML table or array of tables header line
+var tableHeader = regexp.MustCompile(`^\s*\[(\[?)\s*([A-Za-z0-9_.\-]+)\s*\]\]?\s*(#.*)?$`)
+
+// replaceViews swaps the [[views]] tables in a config file for views, at
+// the place of the first one or else at the end. Comments and blank lines
+// at the end of the last view are kept with whatever follows.
+func replaceViews(file, views string) string {
+	var out, pending []string
+	insertAt := -1
+	inViews := false
+	for _, line := range strings.Split(strings.TrimRight(file, "\n"), "\n") {
+		if m := tableHeader.FindStringSubmatch(line); m != nil {
+			isViews := (m[2] == "views" && m[1] == "[") || strings.HasPrefix(m[2], "views.")
+			if isViews {
+				if insertAt < 0 {
+					insertAt = len(out)
+				}
+				inViews, pending = true, nil
+				continue
+			}
+			if inViews {
+				out = appendKept(out, pending)
+			}
+			inViews, pending = false, nil
+		}
+		if !inViews {
+			out = append(out, line)
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			pending = append(pending, line)
+		} else {
+			pending = nil
+		}
+	}
+	out = appendKept(out, pending)
+	
+	if insertAt < 0 {
+		insertAt = len(out)
+	}
+	// Keep a blank line on either side of the views
+	before := strings.TrimRight(strings.Join(out[:insertAt], "\n"), "\n")
+	after := strings.Trim(strings.Join(out[insertAt:], "\n"), "\n")
+	parts := []string{}
+	if before != "" {
+		parts = append(parts, before)
+	}
+	if views != "" {
+		parts = append(parts, views)
+	}
+	if after != "" {
+		parts = append(parts, after)
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// appendKept appends the lines kept from the end of a view, without
+// doubling the blank line left before it
+func appendKept(out, kept []string) []string {
+	for len(kept) > 0 && len(out) > 0 &&
+		strings.TrimSpace(kept[0]) == "" && strings.TrimSpace(out[len(out)-1]) == "" {
+		kept = kept[1:]
+	}
+	return append(out, kept...)
+}
+
+// save writes the whole config to path
+func save(path string, config *Config) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	
+	// Write the notes directory back in ~ form if it lives under home
+	out := *config
+	if rel, err := filepath.Rel(homeDir, out.NotesDirectory); err == nil && !strings.HasPrefix(rel, "..") {
+		out.NotesDirectory = filepath.Join("~", rel)
+	}
+	
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(out); err != nil {
+		return fmt.Errorf("error encoding config: %w", err)
+	}
+	
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// SetView adds a view, replacing any existing view with the same name
+func (c *Config) SetView(view View) {
+	for i, v := range c.Views {
+		if strings.EqualFold(v.Name, view.Name) {
+			c.Views[i] = view
+			return
+		}
+	}
+	c.Views = append(c.Views, view)
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveViews(t *testing.T) {
+	views := []View{
+		{Name: "Weekly", Statuses: []string{"overdue"}},
+		{Name: "Hiring", Query: "#hiring"},
+	}
+	want := `[[views]]
+name = "Weekly"
+statuses = ["overdue"]
+
+[[views]]
+name = "Hiring"
+query = "#hiring"`
+
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{
+			name: "views added at the end",
+			file: "# My contacts\nnotes_directory = \"~/notes\" # synced\n",
+			want: "# My contacts\nnotes_directory = \"~/notes\" # synced\n\n" + want + "\n",
+		},
+		{
+			name: "views replaced in place, comments kept",
+			file: `# My contacts
+notes_directory = "~/notes"
+
+[[views]]
+name = "Old"
+types = [
+  "close",
+]
+
+# Bumps defer a contact for a week
+[bumps.default]
+defer_days = 7
+max_bumps = 2
+`,
+			want: `# My contacts
+notes_directory = "~/notes"
+
+` + want + `
+
+# Bumps defer a contact for a week
+[bumps.default]
+defer_days = 7
+max_bumps = 2
+`,
+		},
+		{
+			name: "views spread through the file gathered at the first",
+			file: `[[views]]
+name = "Old"
+
+[git]
+enabled = true
+
+[[ views ]] # another
+name = "Older"
+`,
+			want: want + "\n\n[git]\nenabled = true\n",
+		},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte(tt.file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := saveViews(path, &Config{NotesDirectory: "/elsewhere", Views: views}); err != nil {
+			t.Fatalf("%s: saveViews = %v", tt.name, err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != tt.want {
+			t.Errorf("%s: file =\n%s\nwant\n%s", tt.name, data, tt.want)
+		}
+	}
+}
+
+func TestSaveViewsNewFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".config", "denote-contacts", "config.toml")
+
+	config := &Config{NotesDirectory: filepath.Join(home, "notes"), Views: []View{{Name: "All"}}}
+	if err := saveViews(path, config); err != nil {
+		t.Fatalf("saveViews = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`notes_directory = "~/notes"`, `name = "All"`} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("new config has no %s:\n%s", s, data)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package conflict finds contact files left in conflict by git or Syncthing
+// and merges the two versions, leaving only the differences that need a
+// person to choose.
+package conflict
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// syncConflict marks the copies Syncthing keeps of a conflicting file, as in
+// "name.sync-conflict-20240101-101010-ABCDEFG.md"
+const syncConflict = ".sync-conflict-"
+
+// ErrMarkers is returned when parsing a file that still has git conflict
+// markers in it
+var ErrMarkers = errors.New("file has unresolved conflict markers")
+
+// File is a contact in conflict: either one file with git conflict markers,
+// or a file and the Syncthing copy that conflicts with it
+type File struct {
+	Path string // The contact file
+	Copy string // The Syncthing conflict copy; empty for conflict markers
+}
+
+// Name returns the file name of the contact in conflict
+func (f File) Name() string {
+	return filepath.Base(f.Path)
+}
+
+// IsSyncConflict reports whether path is a Syncthing conflict copy
+func IsSyncConflict(path string) bool {
+	return strings.Contains(filepath.Base(path), syncConflict)
+}
+
+// OriginalPath returns the file a Syncthing conflict copy was made from
+func OriginalPath(path string) string {
+	dir, name := filepath.Split(path)
+	i := strings.Index(name, syncConflict)
+	if i < 0 {
+		return path
+	}
+	return dir + name[:i] + filepath.Ext(name)
+}
+
+// HasMarkers reports whether content has a git conflict block in it
+func HasMarkers(content []byte) bool {
+	start, end := false, false
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		switch {
+		case bytes.HasPrefix(line, []byte("<<<<<<<")):
+			start = true
+		case start && bytes.HasPrefix(line, []byte(">>>>>>>")):
+			end = true
+		}
+	}
+	return start && end
+}
+
+// SplitMarkers separates content with conflict markers into the two
+// versions. When the markers include the common ancestor (git's diff3
+// style) it's returned as base; otherwise base is nil.
+func SplitMarkers(content []byte) (base, ours, theirs []byte) {
+	const (
+		shared = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	var b, o, t bytes.Buffer
+	hasBase := false
+	state := shared
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		switch {
+		case state == shared && bytes.HasPrefix(line, []byte("<<<<<<<")):
+			state = inOurs
+			continue
+		case state == inOurs && bytes.HasPrefix(line, []byte("|||||||")):
+			state, hasBase = inBase, true
+			continue
+		case (state == inOurs || state == inBase) && bytes.HasPrefix(line, []byte("=======")):
+			state = inTheirs
+			continue
+		case state == inTheirs && bytes.HasPrefix(line, []byte(">>>>>>>")):
+			state = shared
+			continue
+		}
+		switch state {
+		case shared:
+			b.Write(line)
+			o.Write(line)
+			t.Write(line)
+		case inOurs:
+			o.Write(line)
+		case inBase:
+			b.Write(line)
+		case inTheirs:
+			t.Write(line)
+		}
+	}
+	if !hasBase {
+		return nil, o.Bytes(), t.Bytes()
+	}
+	return b.Bytes(), o.Bytes(), t.Bytes()
+}
+
+// Find lists the contacts in conflict under dir, skipping hidden
+// directories such as .trash
+func Find(dir string) ([]File, error) {
+	var files []File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		if !strings.HasSuffix(name, ".md") || !strings.Contains(name, "__contact") {
+			return nil
+		}
+
+		if IsSyncConflict(path) {
+			files = append(files, File{Path: OriginalPath(path), Copy: path})
+			return nil
+		}
+		if strings.HasSuffix(name, "__contact.md") {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			if HasMarkers(content) {
+				files = append(files, File{Path: path})
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// Load reads the versions of a contact in conflict and merges them
+func Load(f File) (*Result, error) {
+	ours, err := os.ReadFile(f.Path)
+	if err != nil && !(f.Copy != "" && os.IsNotExist(err)) {
+		return nil, err
+	}
+	if f.Copy == "" {
+		base, o, t := SplitMarkers(ours)
+		return Merge(base, o, t)
+	}
+
+	theirs, err := os.ReadFile(f.Copy)
+	if err != nil {
+		return nil, err
+	}
+	if ours == nil {
+		// The original is gone, so the copy is all there is
+		ours = theirs
+	}
+	if HasMarkers(ours) {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(f.Path), ErrMarkers)
+	}
+	return Merge(nil, ours, theirs)
+}
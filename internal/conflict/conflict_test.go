@@ -0,0 +1,182 @@
+package conflict
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitMarkers(t *testing.T) {
+	content := "---\ntitle: Ann\n<<<<<<< HEAD\nstate: ping\n||||||| base\nstate: ok\n=======\nstate: followup\n>>>>>>> other\n---\nbody\n"
+	base, ours, theirs := SplitMarkers([]byte(content))
+	if got, want := string(ours), "---\ntitle: Ann\nstate: ping\n---\nbody\n"; got != want {
+		t.Errorf("ours = %q, want %q", got, want)
+	}
+	if got, want := string(theirs), "---\ntitle: Ann\nstate: followup\n---\nbody\n"; got != want {
+		t.Errorf("theirs = %q, want %q", got, want)
+	}
+	if got, want := string(base), "---\ntitle: Ann\nstate: ok\n---\nbody\n"; got != want {
+		t.Errorf("base = %q, want %q", got, want)
+	}
+
+	// Without the diff3 section there's no base
+	base, _, _ = SplitMarkers([]byte("<<<<<<< HEAD\na\n=======\nb\n>>>>>>> x\n"))
+	if base != nil {
+		t.Errorf("base = %q, want nil", base)
+	}
+}
+
+func TestHasMarkers(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"---\ntitle: A\n---\n", false},
+		{"<<<<<<< HEAD\na\n=======\nb\n>>>>>>> x\n", true},
+		{"Heading\n=======\n", false},
+		{"<<<<<<< only a start\n", false},
+	}
+	for _, tt := range tests {
+		if got := HasMarkers([]byte(tt.content)); got != tt.want {
+			t.Errorf("HasMarkers(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestOriginalPath(t *testing.T) {
+	got := OriginalPath("/c/20240101T100000--ann__contact.sync-conflict-20240301-101010-ABCDEFG.md")
+	if want := "/c/20240101T100000--ann__contact.md"; got != want {
+		t.Errorf("OriginalPath() = %q, want %q", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		want      string
+		conflicts []string
+	}{
+		{
+			name:   "one side changed a field",
+			base:   "---\ntitle: Ann\nstate: ok\n---\n",
+			ours:   "---\ntitle: Ann\nstate: ok\n---\n",
+			theirs: "---\ntitle: Ann\nstate: ping\n---\n",
+			want:   "---\ntitle: Ann\nstate: ping\n---\n",
+		},
+		{
+			name:   "both changed times, counts and tags",
+			ours:   "---\ntitle: Ann\ntags: [contact, work]\nbump_count: 2\nupdated_at: 2024-03-01T10:00:00Z\n---\n",
+			theirs: "---\ntitle: Ann\ntags: [contact, friend]\nbump_count: 1\nupdated_at: 2024-03-02T10:00:00Z\n---\n",
+			want:   "---\ntitle: Ann\ntags: [contact, work, friend]\nbump_count: 2\nupdated_at: 2024-03-02T10:00:00Z\n---\n",
+		},
+		{
+			name:   "field only one side has",
+			ours:   "---\ntitle: Ann\n---\n",
+			theirs: "---\ntitle: Ann\nemail: ann@example.com\n---\n",
+			want:   "---\ntitle: Ann\nemail: ann@example.com\n---\n",
+		},
+		{
+			name:   "interactions unioned by date",
+			ours:   "---\ntitle: Ann\n---\n## 2024-03-05 10:00 - call\n\nOurs\n\n## 2024-03-01 10:00 - email\n\nOld\n",
+			theirs: "---\ntitle: Ann\n---\n## 2024-03-03 09:00 - text\n\nTheirs\n\n## 2024-03-01 10:00 - email\n\nOld\n",
+			want:   "---\ntitle: Ann\n---\n## 2024-03-05 10:00 - call\n\nOurs\n\n## 2024-03-03 09:00 - text\n\nTheirs\n\n## 2024-03-01 10:00 - email\n\nOld\n",
+		},
+		{
+			name:      "both changed a field",
+			ours:      "---\ntitle: Ann\nstate: ping\n---\n",
+			theirs:    "---\ntitle: Ann\nstate: followup\n---\n",
+			conflicts: []string{"state"},
+		},
+		{
+			name:      "same interaction edited differently",
+			ours:      "---\ntitle: Ann\n---\nNotes\n\n## 2024-03-01 10:00 - call\n\nOne\n",
+			theirs:    "---\ntitle: Ann\n---\nOther notes\n\n## 2024-03-01 10:00 - call\n\nTwo\n",
+			conflicts: []string{"notes", "## 2024-03-01 10:00 - call"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var base []byte
+			if tt.base != "" {
+				base = []byte(tt.base)
+			}
+			r, err := Merge(base, []byte(tt.ours), []byte(tt.theirs))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields []string
+			for _, c := range r.Conflicts {
+				fields = append(fields, c.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.conflicts, ",") {
+				t.Fatalf("conflicts = %v, want %v", fields, tt.conflicts)
+			}
+			if len(tt.conflicts) > 0 {
+				if _, err := r.Content(); err != ErrUnresolved {
+					t.Errorf("Content() error = %v, want %v", err, ErrUnresolved)
+				}
+				return
+			}
+			got, err := r.Content()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Content() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChoose(t *testing.T) {
+	r, err := Merge(nil,
+		[]byte("---\ntitle: Ann\nstate: ping\n---\n## 2024-03-01 10:00 - call\n\nOne\n"),
+		[]byte("---\ntitle: Ann\nstate: followup\n---\n## 2024-03-01 10:00 - call\n\nTwo\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Choose(0, Theirs)
+	if r.Unresolved() != 1 {
+		t.Fatalf("Unresolved() = %d, want 1", r.Unresolved())
+	}
+	r.Choose(1, Ours)
+	got, err := r.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\ntitle: Ann\nstate: followup\n---\n## 2024-03-01 10:00 - call\n\nOne\n"; string(got) != want {
+		t.Errorf("Content() = %q, want %q", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("20240101T100000--ann__contact.md", "---\ntitle: Ann\n---\n")
+	write("20240101T100000--ann__contact.sync-conflict-20240301-101010-ABCDEFG.md", "---\ntitle: Ann\n---\n")
+	write("20240102T100000--bob__contact.md", "---\n<<<<<<< HEAD\ntitle: Bob\n=======\ntitle: Robert\n>>>>>>> x\n---\n")
+	write("20240103T100000--cy__contact.md", "---\ntitle: Cy\n---\n")
+
+	files, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Find() = %+v, want 2 files", files)
+	}
+	if files[0].Name() != "20240101T100000--ann__contact.md" || files[0].Copy == "" {
+		t.Errorf("files[0] = %+v, want ann with its copy", files[0])
+	}
+	if files[1].Name() != "20240102T100000--bob__contact.md" || files[1].Copy != "" {
+		t.Errorf("files[1] = %+v, want bob with markers", files[1])
+	}
+}
@@ -0,0 +1,452 @@
+package conflict
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnresolved is returned by Content while conflicts are left unchosen
+var ErrUnresolved = errors.New("conflicts left to resolve")
+
+// Side is which version a conflict is resolved with
+type Side int
+
+const (
+	Unresolved Side = iota
+	Ours
+	Theirs
+)
+
+// Fields where the later time is the right one when both versions moved it
+var latestFields = map[string]bool{
+	"updated_at":     true,
+	"last_contacted": true,
+	"last_bump_date": true,
+}
+
+// Conflict is a frontmatter field or body section the two versions changed
+// differently
+type Conflict struct {
+	Field  string // Frontmatter key, "notes" or an interaction heading
+	Ours   string // Empty when the version doesn't have it
+	Theirs string
+	Choice Side
+
+	apply func(Side) // Puts the chosen version into the result
+}
+
+// Result is the merge of two versions of a contact file
+type Result struct {
+	Conflicts []Conflict
+
+	keys     []string              // Frontmatter keys in order
+	values   map[string]*yaml.Node // Merged values; nil drops the key
+	preamble string                // Body text before the first heading
+	sections []section             // Body sections in order
+}
+
+// section is a heading and the text under it
+type section struct {
+	key  string // The heading, numbered if repeated
+	text string // Heading line included
+}
+
+// Merge merges two versions of a contact file. Frontmatter is merged field
+// by field and body sections are unioned. With base, a change on one side
+// wins over no change on the other; without it, or when both sides changed
+// a field, a value is kept over none, times take the later value, counts
+// the larger and lists the union. Anything else is left as a conflict.
+func Merge(base, ours, theirs []byte) (*Result, error) {
+	var bFront, bBody []byte
+	if base != nil {
+		var err error
+		if bFront, bBody, err = splitFile(base); err != nil {
+			bFront, bBody = nil, nil
+		}
+	}
+	oFront, oBody, err := splitFile(ours)
+	if err != nil {
+		return nil, fmt.Errorf("our version: %w", err)
+	}
+	tFront, tBody, err := splitFile(theirs)
+	if err != nil {
+		return nil, fmt.Errorf("their version: %w", err)
+	}
+
+	r := &Result{values: make(map[string]*yaml.Node)}
+	if err := r.mergeFront(bFront, oFront, tFront, base != nil); err != nil {
+		return nil, err
+	}
+	r.mergeBody(string(bBody), string(oBody), string(tBody), base != nil)
+	return r, nil
+}
+
+// splitFile separates the frontmatter from the body
+func splitFile(content []byte) (front, body []byte, err error) {
+	parts := bytes.SplitN(content, []byte("---\n"), 3)
+	if len(parts) < 3 || len(bytes.TrimSpace(parts[0])) > 0 {
+		return nil, nil, fmt.Errorf("no frontmatter found")
+	}
+	return parts[1], parts[2], nil
+}
+
+// mapping decodes frontmatter into its keys, in order, and values
+func mapping(front []byte) ([]string, map[string]*yaml.Node, error) {
+	values := make(map[string]*yaml.Node)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(front, &doc); err != nil {
+		return nil, nil, fmt.Errorf("error parsing frontmatter: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, values, nil
+	}
+	m := doc.Content[0]
+	if m.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("frontmatter is not a mapping")
+	}
+	var keys []string
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		keys = append(keys, m.Content[i].Value)
+		values[m.Content[i].Value] = m.Content[i+1]
+	}
+	return keys, values, nil
+}
+
+// render shows a value as it's written in the frontmatter
+func render(n *yaml.Node) string {
+	if n == nil {
+		return ""
+	}
+	if n.Kind == yaml.ScalarNode {
+		return n.Value
+	}
+	out, err := yaml.Marshal(n)
+	if err != nil {
+		return n.Value
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// same reports whether two values are written the same way
+func same(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return render(a) == render(b)
+}
+
+func (r *Result) mergeFront(bFront, oFront, tFront []byte, hasBase bool) error {
+	var bValues map[string]*yaml.Node
+	if hasBase {
+		if _, values, err := mapping(bFront); err == nil {
+			bValues = values
+		}
+	}
+	oKeys, oValues, err := mapping(oFront)
+	if err != nil {
+		return fmt.Errorf("our version: %w", err)
+	}
+	tKeys, tValues, err := mapping(tFront)
+	if err != nil {
+		return fmt.Errorf("their version: %w", err)
+	}
+
+	r.keys = oKeys
+	for _, k := range tKeys {
+		if _, ok := oValues[k]; !ok {
+			r.keys = append(r.keys, k)
+		}
+	}
+
+	for _, key := range r.keys {
+		o, t := oValues[key], tValues[key]
+		switch {
+		case same(o, t):
+			r.values[key] = o
+			continue
+		case bValues != nil && same(bValues[key], o):
+			r.values[key] = t
+			continue
+		case bValues != nil && same(bValues[key], t):
+			r.values[key] = o
+			continue
+		}
+		if v, ok := combine(key, o, t); ok {
+			r.values[key] = v
+			continue
+		}
+
+		key := key
+		r.values[key] = o
+		r.Conflicts = append(r.Conflicts, Conflict{
+			Field:  key,
+			Ours:   render(o),
+			Theirs: render(t),
+			apply: func(side Side) {
+				if side == Theirs {
+					r.values[key] = t
+				} else {
+					r.values[key] = o
+				}
+			},
+		})
+	}
+	return nil
+}
+
+// combine merges two values both sides changed, when the field has a
+// natural merge: the value over no value, the later time, the larger count
+// or the union of a list
+func combine(key string, o, t *yaml.Node) (*yaml.Node, bool) {
+	switch {
+	case o == nil:
+		return t, true
+	case t == nil:
+		return o, true
+
+	case latestFields[key]:
+		var ot, tt time.Time
+		if o.Decode(&ot) != nil || t.Decode(&tt) != nil {
+			return nil, false
+		}
+		if tt.After(ot) {
+			return t, true
+		}
+		return o, true
+
+	case key == "bump_count":
+		on, err1 := strconv.Atoi(o.Value)
+		tn, err2 := strconv.Atoi(t.Value)
+		if err1 != nil || err2 != nil {
+			return nil, false
+		}
+		if tn > on {
+			return t, true
+		}
+		return o, true
+
+	case o.Kind == yaml.SequenceNode && t.Kind == yaml.SequenceNode:
+		union := *o
+		union.Content = append([]*yaml.Node(nil), o.Content...)
+		for _, item := range t.Content {
+			found := false
+			for _, have := range union.Content {
+				if same(have, item) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				union.Content = append(union.Content, item)
+			}
+		}
+		return &union, true
+	}
+	return nil, false
+}
+
+// splitBody separates the text before the first heading from the sections
+// under each heading. A heading seen again is keyed with its count.
+func splitBody(body string) (string, []section) {
+	var preamble strings.Builder
+	var sections []section
+	seen := make(map[string]int)
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if strings.HasPrefix(line, "#") {
+			key := strings.TrimSpace(line)
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				key = fmt.Sprintf("%s (%d)", key, n)
+			}
+			sections = append(sections, section{key: key, text: line})
+			continue
+		}
+		if len(sections) == 0 {
+			preamble.WriteString(line)
+		} else {
+			sections[len(sections)-1].text += line
+		}
+	}
+	return preamble.String(), sections
+}
+
+// sameText reports whether two pieces of text differ only in surrounding
+// whitespace
+func sameText(a, b string) bool {
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
+func (r *Result) mergeBody(bBody, oBody, tBody string, hasBase bool) {
+	bPre, bSections := splitBody(bBody)
+	oPre, oSections := splitBody(oBody)
+	tPre, tSections := splitBody(tBody)
+	bText := make(map[string]string)
+	for _, s := range bSections {
+		bText[s.key] = s.text
+	}
+
+	// The text before any heading
+	switch {
+	case sameText(oPre, tPre), hasBase && sameText(bPre, tPre):
+		r.preamble = oPre
+	case hasBase && sameText(bPre, oPre):
+		r.preamble = tPre
+	default:
+		r.preamble = oPre
+		r.Conflicts = append(r.Conflicts, Conflict{
+			Field:  "notes",
+			Ours:   strings.TrimSpace(oPre),
+			Theirs: strings.TrimSpace(tPre),
+			apply: func(side Side) {
+				if side == Theirs {
+					r.preamble = tPre
+				} else {
+					r.preamble = oPre
+				}
+			},
+		})
+	}
+
+	// Our sections in order, with theirs placed after the section before
+	// them in their version
+	r.sections = append(r.sections, oSections...)
+	index := make(map[string]int)
+	for i, s := range r.sections {
+		index[s.key] = i
+	}
+	after := -1
+	for _, s := range tSections {
+		if i, ok := index[s.key]; ok {
+			after = i
+			continue
+		}
+		after++
+		r.sections = append(r.sections[:after], append([]section{s}, r.sections[after:]...)...)
+		for i := after; i < len(r.sections); i++ {
+			index[r.sections[i].key] = i
+		}
+	}
+	sortInteractions(r.sections)
+
+	// Sections both have, changed differently
+	tText := make(map[string]string)
+	for _, s := range tSections {
+		tText[s.key] = s.text
+	}
+	for i, s := range r.sections {
+		t, ok := tText[s.key]
+		if !ok || sameText(s.text, t) {
+			continue
+		}
+		o := s.text
+		if b, ok := bText[s.key]; hasBase && ok {
+			if sameText(b, o) {
+				r.sections[i].text = t
+			}
+			if sameText(b, o) || sameText(b, t) {
+				continue
+			}
+		}
+
+		i := i
+		r.Conflicts = append(r.Conflicts, Conflict{
+			Field:  s.key,
+			Ours:   strings.TrimSpace(o),
+			Theirs: strings.TrimSpace(t),
+			apply: func(side Side) {
+				if side == Theirs {
+					r.sections[i].text = t
+				} else {
+					r.sections[i].text = o
+				}
+			},
+		})
+	}
+}
+
+// sortInteractions puts the interaction sections newest first, leaving any
+// other sections where they are
+func sortInteractions(sections []section) {
+	var slots []int
+	var dated []section
+	dates := make(map[string]time.Time)
+	for i, s := range sections {
+		first, _, _ := strings.Cut(s.text, "\n")
+		if in := model.ParseInteractions(first); len(in) == 1 {
+			slots = append(slots, i)
+			dated = append(dated, s)
+			dates[s.key] = in[0].Date
+		}
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		return dates[dated[i].key].After(dates[dated[j].key])
+	})
+	for n, i := range slots {
+		sections[i] = dated[n]
+	}
+}
+
+// Title returns the contact's title as merged, or ours while it's in
+// conflict
+func (r *Result) Title() string {
+	return render(r.values["title"])
+}
+
+// Choose resolves the i'th conflict with one side
+func (r *Result) Choose(i int, side Side) {
+	if i < 0 || i >= len(r.Conflicts) {
+		return
+	}
+	r.Conflicts[i].Choice = side
+	r.Conflicts[i].apply(side)
+}
+
+// Unresolved returns how many conflicts are left to choose
+func (r *Result) Unresolved() int {
+	n := 0
+	for _, c := range r.Conflicts {
+		if c.Choice == Unresolved {
+			n++
+		}
+	}
+	return n
+}
+
+// Content returns the merged file, or ErrUnresolved while conflicts are
+// left to choose
+func (r *Result) Content() ([]byte, error) {
+	if r.Unresolved() > 0 {
+		return nil, ErrUnresolved
+	}
+
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range r.keys {
+		if v := r.values[key]; v != nil {
+			m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+		}
+	}
+	front, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling frontmatter: %w", err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	b.Write(front)
+	b.WriteString("---\n")
+	if pre := strings.TrimRight(r.preamble, "\n"); pre != "" {
+		b.WriteString(pre + "\n\n")
+	}
+	for _, s := range r.sections {
+		b.WriteString(strings.TrimRight(s.text, "\n") + "\n\n")
+	}
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n'), nil
+}
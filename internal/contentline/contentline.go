@@ -0,0 +1,33 @@
+// Package contentline formats the content lines that vCard (RFC 6350) and
+// iCalendar (RFC 5545) files are made of.
+package contentline
+
+import "strings"
+
+// escaper backslash-escapes the characters special in a text value
+var escaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`, "\r", "")
+
+// Escape backslash-escapes a text value
+func Escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// Fold breaks a line longer than 75 octets onto continuation lines that
+// start with a space, without splitting a character
+func Fold(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
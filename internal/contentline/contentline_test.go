@@ -0,0 +1,34 @@
+package contentline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	if got, want := Escape("Acme, Inc.; R&D\nC:\\temp\r"), `Acme\, Inc.\; R&D\nC:\\temp`; got != want {
+		t.Errorf("Escape() = %q, want %q", got, want)
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Lunch"},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{"multibyte", "NOTE:" + strings.Repeat("é", 100)},
+	}
+	for _, tt := range tests {
+		folded := Fold(tt.line)
+		for _, l := range strings.Split(folded, "\r\n") {
+			if len(l) > 75 {
+				t.Errorf("%s: line longer than 75 octets: %q", tt.name, l)
+			}
+		}
+		if got := strings.ReplaceAll(folded, "\r\n ", ""); got != tt.line {
+			t.Errorf("%s: unfolded = %q, want %q", tt.name, got, tt.line)
+		}
+	}
+}
@@ -0,0 +1,433 @@
+// Package csvmap reads and writes contacts as CSV, with a mapping from
+// column headers to contact fields.
+package csvmap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// Column maps a CSV column to a contact field
+type Column struct {
+	Header string `toml:"header"`
+	Field  string `toml:"field"`
+}
+
+// Mapping says which columns hold which contact fields and how to read them
+type Mapping struct {
+	Columns      []Column `toml:"columns"`
+	TagSeparator string   `toml:"tag_separator"` // Between tags in one cell; ";" if empty
+	DateFormat   string   `toml:"date_format"`   // Go layout for last_contacted; common forms if empty
+	SkipTags     []string `toml:"skip_tags"`     // Tags to leave out, such as Google's "myContacts"
+}
+
+// field reads and writes one contact field as text
+type field struct {
+	get func(c *model.Contact, m Mapping) string
+	set func(c *model.Contact, v string, m Mapping) error
+}
+
+// text is a field held as a plain string
+func text(p func(c *model.Contact) *string) field {
+	return field{
+		get: func(c *model.Contact, m Mapping) string { return *p(c) },
+		set: func(c *model.Contact, v string, m Mapping) error {
+			if *p(c) == "" {
+				*p(c) = v
+			}
+			return nil
+		},
+	}
+}
+
+// joined is a field that several columns add to, such as a location
+// split into city, region and country
+func joined(sep string, p func(c *model.Contact) *string) field {
+	return field{
+		get: func(c *model.Contact, m Mapping) string { return *p(c) },
+		set: func(c *model.Contact, v string, m Mapping) error {
+			if *p(c) != "" {
+				v = *p(c) + sep + v
+			}
+			*p(c) = v
+			return nil
+		},
+	}
+}
+
+// firstName and lastName split a title at its last word
+func firstName(title string) string {
+	words := strings.Fields(title)
+	if len(words) < 2 {
+		return title
+	}
+	return strings.Join(words[:len(words)-1], " ")
+}
+
+func lastName(title string) string {
+	words := strings.Fields(title)
+	if len(words) < 2 {
+		return ""
+	}
+	return words[len(words)-1]
+}
+
+// nameParts collects first, middle and last names until a row is done
+type nameParts struct {
+	first, middle, last string
+}
+
+// fields are the contact fields a column can map to
+var fields = map[string]field{
+	"title": text(func(c *model.Contact) *string { return &c.Title }),
+	"first_name": {
+		get: func(c *model.Contact, m Mapping) string { return firstName(c.Title) },
+	},
+	"middle_name": {
+		get: func(c *model.Contact, m Mapping) string { return "" },
+	},
+	"last_name": {
+		get: func(c *model.Contact, m Mapping) string { return lastName(c.Title) },
+	},
+	"email":    text(func(c *model.Contact) *string { return &c.Email }),
+	"phone":    text(func(c *model.Contact) *string { return &c.Phone }),
+	"company":  text(func(c *model.Contact) *string { return &c.Company }),
+	"role":     text(func(c *model.Contact) *string { return &c.Role }),
+	"location": joined(", ", func(c *model.Contact) *string { return &c.Location }),
+	"notes":    joined("\n", func(c *model.Contact) *string { return &c.Notes }),
+	"linkedin": text(func(c *model.Contact) *string { return &c.LinkedIn }),
+	"twitter":  text(func(c *model.Contact) *string { return &c.Twitter }),
+	"website":  text(func(c *model.Contact) *string { return &c.Website }),
+	"label": {
+		get: func(c *model.Contact, m Mapping) string { return c.Label },
+		set: func(c *model.Contact, v string, m Mapping) error {
+			c.Label = model.NormalizeLabel(v)
+			return nil
+		},
+	},
+	"state":      text(func(c *model.Contact) *string { return &c.State }),
+	"identifier": text(func(c *model.Contact) *string { return &c.Identifier }),
+	"relationship_type": {
+		get: func(c *model.Contact, m Mapping) string { return string(c.RelationshipType) },
+		set: func(c *model.Contact, v string, m Mapping) error {
+			t, err := model.ParseRelationshipType(v)
+			c.RelationshipType = t
+			return err
+		},
+	},
+	"contact_style": {
+		get: func(c *model.Contact, m Mapping) string { return string(c.ContactStyle) },
+		set: func(c *model.Contact, v string, m Mapping) error {
+			style, err := model.ParseContactStyle(v)
+			c.ContactStyle = style
+			return err
+		},
+	},
+	"custom_frequency_days": {
+		get: func(c *model.Contact, m Mapping) string {
+			if c.CustomFrequencyDays == 0 {
+				return ""
+			}
+			return strconv.Itoa(c.CustomFrequencyDays)
+		},
+		set: func(c *model.Contact, v string, m Mapping) error {
+			days, err := strconv.Atoi(v)
+			if err != nil || days < 0 {
+				return fmt.Errorf("not a number of days: %q", v)
+			}
+			c.CustomFrequencyDays = days
+			return nil
+		},
+	},
+	"birthday": {
+		get: func(c *model.Contact, m Mapping) string { return c.Birthday },
+		set: func(c *model.Contact, v string, m Mapping) error {
+			// Google writes a birthday without a year as --MM-DD
+			e, err := model.ParseEvent("birthday", strings.TrimPrefix(v, "--"))
+			if err != nil {
+				return err
+			}
+			c.Birthday = e.String()
+			return nil
+		},
+	},
+	"tags": {
+		get: func(c *model.Contact, m Mapping) string {
+			var tags []string
+			for _, tag := range c.Tags {
+				if tag != "contact" {
+					tags = append(tags, tag)
+				}
+			}
+			return strings.Join(tags, m.separator())
+		},
+		set: func(c *model.Contact, v string, m Mapping) error {
+			for _, tag := range strings.Split(v, m.separator()) {
+				tag = strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(tag), "* ")), "-"))
+				if tag == "" || tag == "contact" || m.skipTag(tag) || containsString(c.Tags, tag) {
+					continue
+				}
+				c.Tags = append(c.Tags, tag)
+			}
+			return nil
+		},
+	},
+	"last_contacted": {
+		get: func(c *model.Contact, m Mapping) string {
+			if c.LastContacted == nil {
+				return ""
+			}
+			layout := m.DateFormat
+			if layout == "" {
+				layout = "2006-01-02"
+			}
+			return c.LastContacted.Format(layout)
+		},
+		set: func(c *model.Contact, v string, m Mapping) error {
+			t, err := m.parseDate(v)
+			if err != nil {
+				return err
+			}
+			c.LastContacted = &t
+			return nil
+		},
+	},
+}
+
+// nameFields are put together into the title once a row is read
+var nameFields = map[string]func(n *nameParts, v string){
+	"first_name":  func(n *nameParts, v string) { n.first = v },
+	"middle_name": func(n *nameParts, v string) { n.middle = v },
+	"last_name":   func(n *nameParts, v string) { n.last = v },
+}
+
+// dateLayouts are tried in turn for dates when the mapping names none
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"02 Jan 2006",
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"1/2/2006",
+}
+
+// Fields lists the names a column can map to
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default maps columns named after the fields themselves, as written when
+// no mapping is given
+func Default() Mapping {
+	var m Mapping
+	for _, name := range []string{
+		"identifier", "title", "email", "phone", "company", "role", "location",
+		"birthday", "tags", "relationship_type", "contact_style", "state",
+		"custom_frequency_days", "last_contacted", "label", "linkedin",
+		"twitter", "website", "notes",
+	} {
+		m.Columns = append(m.Columns, Column{Header: name, Field: name})
+	}
+	return m
+}
+
+// LoadMapping reads a mapping file: a TOML file with [[columns]] tables of
+// header and field, and optionally tag_separator, date_format and skip_tags
+func LoadMapping(path string) (Mapping, error) {
+	var m Mapping
+	if _, err := toml.DecodeFile(path, &m); err != nil {
+		return Mapping{}, fmt.Errorf("error reading mapping: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return Mapping{}, err
+	}
+	return m, nil
+}
+
+// Validate checks every column maps to a known field
+func (m Mapping) Validate() error {
+	if len(m.Columns) == 0 {
+		return fmt.Errorf("mapping has no columns")
+	}
+	for _, c := range m.Columns {
+		if _, ok := fields[c.Field]; !ok {
+			return fmt.Errorf("column %q maps to unknown field %q: use one of %s", c.Header, c.Field, strings.Join(Fields(), ", "))
+		}
+	}
+	return nil
+}
+
+func (m Mapping) separator() string {
+	if m.TagSeparator == "" {
+		return ";"
+	}
+	return m.TagSeparator
+}
+
+func (m Mapping) skipTag(tag string) bool {
+	for _, skip := range m.SkipTags {
+		if strings.EqualFold(skip, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDate reads a date in the mapping's format, or any common one
+func (m Mapping) parseDate(v string) (time.Time, error) {
+	layouts := dateLayouts
+	if m.DateFormat != "" {
+		layouts = []string{m.DateFormat}
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("can't read date %q", v)
+}
+
+// Row is a contact read from one row of a CSV file
+type Row struct {
+	Number  int // Line in the file the row starts on
+	Contact model.Contact
+	Err     error // Why the row can't be used; Contact is partial
+}
+
+// Read reads a contact from each row after the header. The header is the
+// first row with a column the mapping knows, so lines before it, such as
+// the notes at the top of a LinkedIn export, are skipped. Columns the
+// mapping doesn't name are ignored.
+func Read(r io.Reader, m Mapping) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	byHeader := make(map[string][]string)
+	for _, c := range m.Columns {
+		key := strings.ToLower(strings.TrimSpace(c.Header))
+		byHeader[key] = append(byHeader[key], c.Field)
+	}
+
+	// Find the header and which field each column fills
+	header := -1
+	var columns [][]string
+	for i, record := range records {
+		columns = make([][]string, len(record))
+		found := false
+		for j, h := range record {
+			h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+			if names, ok := byHeader[h]; ok {
+				columns[j] = names
+				found = true
+			}
+		}
+		if found {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil, fmt.Errorf("no column header matches the mapping")
+	}
+
+	var rows []Row
+	for i := header + 1; i < len(records); i++ {
+		record := records[i]
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		row := Row{Number: lines[i]}
+		var name nameParts
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			if j >= len(columns) || value == "" {
+				continue
+			}
+			for _, f := range columns[j] {
+				if setName, ok := nameFields[f]; ok {
+					setName(&name, value)
+					continue
+				}
+				if err := fields[f].set(&row.Contact, value, m); err != nil && row.Err == nil {
+					row.Err = fmt.Errorf("%s: %v", f, err)
+				}
+			}
+		}
+		if row.Contact.Title == "" {
+			row.Contact.Title = strings.Join(strings.Fields(name.first+" "+name.middle+" "+name.last), " ")
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Write writes a header and a row per contact. A field several columns map
+// to is written to the first of them only.
+func Write(w io.Writer, contacts []model.Contact, m Mapping) error {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(m.Columns))
+	for i, c := range m.Columns {
+		header[i] = c.Header
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, contact := range contacts {
+		record := make([]string, len(m.Columns))
+		written := make(map[string]bool)
+		for i, c := range m.Columns {
+			if written[c.Field] {
+				continue
+			}
+			written[c.Field] = true
+			record[i] = fields[c.Field].get(&contact, m)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// containsString reports whether values has s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
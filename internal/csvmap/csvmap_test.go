@@ -0,0 +1,172 @@
+package csvmap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+const googleSample = "\ufeffFirst Name,Middle Name,Last Name,Organization Name,Organization Title,Birthday,Notes,Labels,E-mail 1 - Value,E-mail 2 - Value,Phone 1 - Value,Address 1 - City,Address 1 - Country\n" +
+	"Sarah,,Chen,Acme,Director,--05-01,Met at the conference.,* myContacts ::: Book Club ::: Work,sarah@acme.example,sarah@home.example,+1 555 010 2000,Portland,USA\n" +
+	",,,,,,,,nobody@example.com,,,,\n"
+
+const linkedinSample = "Notes:\n" +
+	"\"When exporting your connection data, you may notice that some of the email addresses are missing.\"\n" +
+	"\n" +
+	"First Name,Last Name,URL,Email Address,Company,Position,Connected On\n" +
+	"Ann,Lee,https://www.linkedin.com/in/annlee,,Globex,Engineer,12 Mar 2024\n" +
+	"Bob,Stone,https://www.linkedin.com/in/bobstone,,Initech,,31 Feb 2024\n"
+
+func TestReadPresets(t *testing.T) {
+	google, _ := Preset("google")
+	gRows, err := Read(strings.NewReader(googleSample), google)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linkedin, _ := Preset("linkedin")
+	lRows, err := Read(strings.NewReader(linkedinSample), linkedin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gRows) != 2 || len(lRows) != 2 {
+		t.Fatalf("Read() = %d and %d rows, want 2 and 2", len(gRows), len(lRows))
+	}
+	connected := time.Date(2024, 3, 12, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+		{"google title", gRows[0].Contact.Title, "Sarah Chen"},
+		{"google email", gRows[0].Contact.Email, "sarah@acme.example"},
+		{"google phone", gRows[0].Contact.Phone, "+1 555 010 2000"},
+		{"google role", gRows[0].Contact.Role, "Director"},
+		{"google location", gRows[0].Contact.Location, "Portland, USA"},
+		{"google birthday", gRows[0].Contact.Birthday, "05-01"},
+		{"google tags", gRows[0].Contact.Tags, []string{"book-club", "work"}},
+		{"google row number", gRows[0].Number, 2},
+		{"google email only", gRows[1].Contact.Email, "nobody@example.com"},
+		{"linkedin row number", lRows[0].Number, 5},
+		{"linkedin title", lRows[0].Contact.Title, "Ann Lee"},
+		{"linkedin url", lRows[0].Contact.LinkedIn, "https://www.linkedin.com/in/annlee"},
+		{"linkedin company", lRows[0].Contact.Company, "Globex"},
+		{"linkedin connected", lRows[0].Contact.LastContacted.Equal(connected), true},
+		{"linkedin bad date", lRows[1].Err != nil, true},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+func TestReadBadValues(t *testing.T) {
+	const sample = "title,relationship_type,contact_style\n" +
+		"Ann Lee,Work,Periodic\n" +
+		"Bob Stone,Friend,periodic\n" +
+		"Cat Ng,close,weekly\n"
+	rows, err := Read(strings.NewReader(sample), Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		row     int
+		wantErr string
+	}{
+		{0, ""},
+		{1, `relationship_type: unknown type "Friend"`},
+		{2, `contact_style: unknown contact style "weekly"`},
+	}
+	for _, tt := range tests {
+		err := rows[tt.row].Err
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("row %d: Err = %v", tt.row, err)
+		case tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)):
+			t.Errorf("row %d: Err = %v, want %s...", tt.row, err, tt.wantErr)
+		}
+	}
+	if rows[0].Contact.RelationshipType != model.RelationshipWork || rows[0].Contact.ContactStyle != model.StylePeriodic {
+		t.Errorf("row 0 = %q %q, want work periodic", rows[0].Contact.RelationshipType, rows[0].Contact.ContactStyle)
+	}
+}
+
+func TestReadNoHeader(t *testing.T) {
+	if _, err := Read(strings.NewReader("a,b\n1,2\n"), Default()); err == nil {
+		t.Error("Read() without a matching header succeeded")
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	last := time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)
+	contact := model.Contact{
+		Title:               "Sarah Chen",
+		Identifier:          "20240101T100000",
+		Tags:                []string{"work", "book-club"},
+		Email:               "sarah@acme.example",
+		Phone:               "+1 555 010 2000",
+		Company:             "Acme, Inc.",
+		Role:                "Director",
+		Location:            "Portland, OR",
+		Birthday:            "1988-05-01",
+		Label:               "@sarah",
+		State:               "ok",
+		Notes:               "Met at the conference.\nLikes tea.",
+		RelationshipType:    model.RelationshipWork,
+		ContactStyle:        model.StylePeriodic,
+		CustomFrequencyDays: 14,
+		LastContacted:       &last,
+	}
+
+	tests := []struct {
+		name    string
+		mapping Mapping
+		want    model.Contact
+	}{
+		{"default", Default(), contact},
+		{"google", presets["google"], model.Contact{
+			Title:    contact.Title,
+			Tags:     contact.Tags,
+			Email:    contact.Email,
+			Phone:    contact.Phone,
+			Company:  contact.Company,
+			Role:     contact.Role,
+			Location: contact.Location,
+			Birthday: contact.Birthday,
+			Notes:    contact.Notes,
+		}},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := Write(&b, []model.Contact{contact}, tt.mapping); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := Read(strings.NewReader(b.String()), tt.mapping)
+		if err != nil || len(rows) != 1 || rows[0].Err != nil {
+			t.Fatalf("%s: Read() = %+v, %v", tt.name, rows, err)
+		}
+		if !reflect.DeepEqual(rows[0].Contact, tt.want) {
+			t.Errorf("%s: round trip =\n%+v\nwant\n%+v", tt.name, rows[0].Contact, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		mapping Mapping
+		ok      bool
+	}{
+		{Default(), true},
+		{Mapping{}, false},
+		{Mapping{Columns: []Column{{"Nickname", "nickname"}}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.mapping.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%v) = %v", tt.mapping.Columns, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package csvmap
+
+import "sort"
+
+// presets are mappings for the exports of other services
+var presets = map[string]Mapping{
+	// Google Contacts "Google CSV". Both the current headers and the older
+	// Given Name / Family Name ones are read; export writes the current.
+	"google": {
+		Columns: []Column{
+			{"First Name", "first_name"},
+			{"Middle Name", "middle_name"},
+			{"Last Name", "last_name"},
+			{"Organization Name", "company"},
+			{"Organization Title", "role"},
+			{"Birthday", "birthday"},
+			{"Notes", "notes"},
+			{"Labels", "tags"},
+			{"E-mail 1 - Value", "email"},
+			{"E-mail 2 - Value", "email"},
+			{"Phone 1 - Value", "phone"},
+			{"Phone 2 - Value", "phone"},
+			{"Address 1 - City", "location"},
+			{"Address 1 - Region", "location"},
+			{"Address 1 - Country", "location"},
+			{"Website 1 - Value", "website"},
+			{"Name", "title"},
+			{"Given Name", "first_name"},
+			{"Additional Name", "middle_name"},
+			{"Family Name", "last_name"},
+			{"Group Membership", "tags"},
+			{"Organization 1 - Name", "company"},
+			{"Organization 1 - Title", "role"},
+		},
+		TagSeparator: " ::: ",
+		SkipTags:     []string{"mycontacts", "starred"},
+	},
+
+	// LinkedIn "Connections.csv" from the data export. When you connected
+	// is taken as when you were last in touch.
+	"linkedin": {
+		Columns: []Column{
+			{"First Name", "first_name"},
+			{"Last Name", "last_name"},
+			{"URL", "linkedin"},
+			{"Email Address", "email"},
+			{"Company", "company"},
+			{"Position", "role"},
+			{"Connected On", "last_contacted"},
+		},
+		DateFormat: "02 Jan 2006",
+	},
+}
+
+// Preset returns the mapping for another service's export
+func Preset(name string) (Mapping, bool) {
+	m, ok := presets[name]
+	return m, ok
+}
+
+// Presets lists the preset names
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,74 @@
+package dedupe
+
+import (
+	"fmt"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// Result is what a merge changed on disk
+type Result struct {
+	Merged  model.Contact   // The kept contact, as saved
+	Others  []model.Contact // Contacts whose links were pointed at the merged one
+	Trashed string          // Where the duplicate's file went
+	Tasks   int             // Tasks whose contact_id was rewritten
+}
+
+// Apply merges lose into keep and writes the result: the merged contact is
+// saved over keep, contacts linking to either label are pointed at the
+// merged one, lose is moved to the trash in contactsDir, and tasks in
+// tasksDir that referred to lose refer to keep.
+func Apply(contacts []model.Contact, keep, lose model.Contact, take map[string]bool, contactsDir, tasksDir string) (Result, error) {
+	var rest []model.Contact
+	for _, c := range contacts {
+		if c.FilePath != keep.FilePath && c.FilePath != lose.FilePath {
+			rest = append(rest, c)
+		}
+	}
+
+	merged := Merge(keep, lose, take)
+	if err := model.CheckLabel(rest, merged); err != nil {
+		return Result{}, err
+	}
+	if err := parser.SaveContactFile(merged); err != nil {
+		return Result{}, fmt.Errorf("failed to save merged contact '%s': %v", merged.Title, err)
+	}
+	result := Result{Merged: merged}
+
+	// Links to either old contact now lead to the merged one
+	changed := make(map[string]bool)
+	for _, before := range []model.Contact{lose, keep} {
+		after := merged
+		after.FilePath = before.FilePath
+		for _, other := range model.SyncRelationships(rest, before, after) {
+			for i := range rest {
+				if rest[i].FilePath == other.FilePath {
+					rest[i] = other
+					changed[other.FilePath] = true
+				}
+			}
+		}
+	}
+	for _, other := range rest {
+		if !changed[other.FilePath] {
+			continue
+		}
+		if err := parser.SaveContactFile(other); err != nil {
+			return result, fmt.Errorf("merged '%s' but failed to update '%s': %v", merged.Title, other.Title, err)
+		}
+		result.Others = append(result.Others, other)
+	}
+
+	trashed, err := parser.MoveToTrash(contactsDir, lose.FilePath)
+	if err != nil {
+		return result, fmt.Errorf("merged '%s' but %v", merged.Title, err)
+	}
+	result.Trashed = trashed
+
+	result.Tasks, err = parser.RewriteTaskContactID(tasksDir, lose.Identifier, keep.Identifier)
+	if err != nil {
+		return result, fmt.Errorf("merged '%s' but %v", merged.Title, err)
+	}
+	return result, nil
+}
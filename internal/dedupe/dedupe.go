@@ -0,0 +1,451 @@
+// Package dedupe finds contacts that are likely the same person and merges
+// them into the older one.
+package dedupe
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// minNameScore is the name similarity at which two contacts are a candidate
+// on their name alone
+const minNameScore = 0.8
+
+// nicknames maps short first names to the names they stand for
+var nicknames = map[string][]string{
+	"bob":    {"robert"},
+	"rob":    {"robert"},
+	"bill":   {"william"},
+	"will":   {"william"},
+	"liz":    {"elizabeth"},
+	"beth":   {"elizabeth"},
+	"kate":   {"katherine", "catherine", "kathryn"},
+	"katie":  {"katherine", "catherine", "kathryn"},
+	"mike":   {"michael"},
+	"jim":    {"james"},
+	"jimmy":  {"james"},
+	"dick":   {"richard"},
+	"rick":   {"richard"},
+	"peggy":  {"margaret"},
+	"maggie": {"margaret"},
+	"jack":   {"john"},
+	"chuck":  {"charles"},
+	"ted":    {"edward", "theodore"},
+	"ned":    {"edward"},
+	"sasha":  {"alexander", "alexandra"},
+}
+
+// Candidate is a pair of contacts that are likely the same person. A and B
+// are indexes into the contacts passed to Find; A is the older one, which a
+// merge keeps.
+type Candidate struct {
+	A, B    int
+	Score   float64  // 0-1, higher is more likely
+	Reasons []string // e.g. "same email jon@example.com"
+}
+
+// Find returns likely duplicates, most likely first. Contacts match on a
+// normalised email or phone number, or on a similar name.
+func Find(contacts []model.Contact) []Candidate {
+	var candidates []Candidate
+	for i := range contacts {
+		for j := i + 1; j < len(contacts); j++ {
+			c, ok := compare(contacts[i], contacts[j])
+			if !ok {
+				continue
+			}
+			c.A, c.B = i, j
+			if Older(contacts[j], contacts[i]) {
+				c.A, c.B = j, i
+			}
+			candidates = append(candidates, c)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// compare scores how likely two contacts are the same person
+func compare(a, b model.Contact) (Candidate, bool) {
+	var c Candidate
+	miss := 1.0 // Chance that every signal is wrong
+	if e := NormalizeEmail(a.Email); e != "" && e == NormalizeEmail(b.Email) {
+		c.Reasons = append(c.Reasons, "same email "+e)
+		miss *= 0.02
+	}
+	if p := NormalizePhone(a.Phone); p != "" && p == NormalizePhone(b.Phone) {
+		c.Reasons = append(c.Reasons, "same phone "+b.Phone)
+		miss *= 0.1
+	}
+	if s := NameSimilarity(a.Title, b.Title); s >= minNameScore {
+		if s == 1 {
+			c.Reasons = append(c.Reasons, "same name")
+		} else {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("similar names (%.0f%%)", s*100))
+		}
+		miss *= 1 - s*0.8
+	}
+	if len(c.Reasons) == 0 {
+		return Candidate{}, false
+	}
+	c.Score = 1 - miss
+	return c, true
+}
+
+// Match finds a contact with the same identifier, email or phone number as
+// c, for checking a contact before it's added. It returns the index of the
+// first match and why it matched, or -1.
+func Match(contacts []model.Contact, c model.Contact) (int, string) {
+	email, phone := NormalizeEmail(c.Email), NormalizePhone(c.Phone)
+	for i := range contacts {
+		if c.Identifier != "" && c.Identifier == contacts[i].Identifier {
+			return i, "same identifier " + c.Identifier
+		}
+		if email != "" && email == NormalizeEmail(contacts[i].Email) {
+			return i, "same email " + email
+		}
+		if phone != "" && phone == NormalizePhone(contacts[i].Phone) {
+			return i, "same phone " + c.Phone
+		}
+	}
+	return -1, ""
+}
+
+// Older reports whether a was created before b, by Denote identifier and
+// then by date
+func Older(a, b model.Contact) bool {
+	if a.Identifier != "" && b.Identifier != "" && a.Identifier != b.Identifier {
+		return a.Identifier < b.Identifier
+	}
+	return a.Date.Before(b.Date)
+}
+
+// NormalizeEmail lowercases an email and drops a +suffix from the local part
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" || domain == "" {
+		return ""
+	}
+	if i := strings.Index(local, "+"); i > 0 {
+		local = local[:i]
+	}
+	return local + "@" + domain
+}
+
+// NormalizePhone keeps the digits of a phone number, and only the last ten
+// so a country code doesn't matter. Numbers under seven digits are ignored.
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	if len(d) < 7 {
+		return ""
+	}
+	if len(d) > 10 {
+		d = d[len(d)-10:]
+	}
+	return d
+}
+
+// NameSimilarity scores two names from 0 to 1. Names with the same last name
+// whose first names are a nickname or short form of one another ("Jon" and
+// "Jonathan") score 0.9; otherwise the score is the edit distance ratio.
+func NameSimilarity(a, b string) float64 {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	if strings.Join(ta, " ") == strings.Join(tb, " ") {
+		return 1
+	}
+	if len(ta) > 1 && len(tb) > 1 && ta[len(ta)-1] == tb[len(tb)-1] && sameFirstName(ta[0], tb[0]) {
+		return 0.9
+	}
+	sa, sb := strings.Join(ta, " "), strings.Join(tb, " ")
+	longest := len([]rune(sa))
+	if n := len([]rune(sb)); n > longest {
+		longest = n
+	}
+	return 1 - float64(levenshtein(sa, sb))/float64(longest)
+}
+
+// nameTokens lowercases a name and splits it into words, dropping punctuation
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sameFirstName reports whether two first names could be the same person
+func sameFirstName(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) >= 3 && len(b) >= 3 && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a)) {
+		return true
+	}
+	for _, full := range nicknames[a] {
+		if full == b {
+			return true
+		}
+	}
+	for _, full := range nicknames[b] {
+		if full == a {
+			return true
+		}
+	}
+	return false
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Field is a contact field shown in the diff and chosen between in a merge
+type Field struct {
+	Name string
+	get  func(c *model.Contact) string
+	set  func(c *model.Contact, v string)
+}
+
+// Fields are the single-valued fields a merge chooses between
+var Fields = []Field{
+	{"name", func(c *model.Contact) string { return c.Title }, func(c *model.Contact, v string) { c.Title = v }},
+	{"email", func(c *model.Contact) string { return c.Email }, func(c *model.Contact, v string) { c.Email = v }},
+	{"phone", func(c *model.Contact) string { return c.Phone }, func(c *model.Contact, v string) { c.Phone = v }},
+	{"company", func(c *model.Contact) string { return c.Company }, func(c *model.Contact, v string) { c.Company = v }},
+	{"role", func(c *model.Contact) string { return c.Role }, func(c *model.Contact, v string) { c.Role = v }},
+	{"location", func(c *model.Contact) string { return c.Location }, func(c *model.Contact, v string) { c.Location = v }},
+	{"label", func(c *model.Contact) string { return c.Label }, func(c *model.Contact, v string) { c.Label = v }},
+	{"type", func(c *model.Contact) string { return string(c.RelationshipType) }, func(c *model.Contact, v string) { c.RelationshipType = model.RelationshipType(v) }},
+	{"style", func(c *model.Contact) string { return string(c.ContactStyle) }, func(c *model.Contact, v string) { c.ContactStyle = model.ContactStyle(v) }},
+	{"state", func(c *model.Contact) string { return c.State }, func(c *model.Contact, v string) { c.State = v }},
+	{"birthday", func(c *model.Contact) string { return c.Birthday }, func(c *model.Contact, v string) { c.Birthday = v }},
+	{"linkedin", func(c *model.Contact) string { return c.LinkedIn }, func(c *model.Contact, v string) { c.LinkedIn = v }},
+	{"twitter", func(c *model.Contact) string { return c.Twitter }, func(c *model.Contact, v string) { c.Twitter = v }},
+	{"website", func(c *model.Contact) string { return c.Website }, func(c *model.Contact, v string) { c.Website = v }},
+	{"frequency", func(c *model.Contact) string {
+		if c.CustomFrequencyDays == 0 {
+			return ""
+		}
+		return strconv.Itoa(c.CustomFrequencyDays)
+	}, func(c *model.Contact, v string) { c.CustomFrequencyDays, _ = strconv.Atoi(v) }},
+}
+
+// Get returns the field's value on the contact
+func (f Field) Get(c model.Contact) string {
+	return f.get(&c)
+}
+
+// Set sets the field's value on the contact
+func (f Field) Set(c *model.Contact, v string) {
+	f.set(c, v)
+}
+
+// Diff is one field of a pair side by side
+type Diff struct {
+	Field    string
+	Keep     string
+	Lose     string
+	Conflict bool // Both have a value and they differ
+}
+
+// Compare lines up the fields of two contacts, leaving out fields neither has
+func Compare(keep, lose model.Contact) []Diff {
+	var diffs []Diff
+	for _, f := range Fields {
+		k, l := f.Get(keep), f.Get(lose)
+		if k == "" && l == "" {
+			continue
+		}
+		diffs = append(diffs, Diff{Field: f.Name, Keep: k, Lose: l, Conflict: k != "" && l != "" && k != l})
+	}
+	return diffs
+}
+
+// Merge combines lose into keep. Keep's identifier, date and file stay. Each
+// field takes keep's value unless take names it or keep has none; values of
+// lose that don't make it are noted in the body. Tags, related labels,
+// relationships, dates and notes are combined, the later last contact
+// wins, the result is archived only if both were, and the interaction
+// history is merged by MergeContent.
+func Merge(keep, lose model.Contact, take map[string]bool) model.Contact {
+	merged := keep
+	var lost []string
+	for _, f := range Fields {
+		k, l := f.Get(keep), f.Get(lose)
+		use := k
+		if (take[f.Name] && l != "") || k == "" {
+			use = l
+		}
+		f.set(&merged, use)
+		other := l
+		if use == l {
+			other = k
+		}
+		if other != "" && other != use {
+			lost = append(lost, fmt.Sprintf("- %s: %s", f.Name, other))
+		}
+	}
+
+	merged.Tags = union(keep.Tags, lose.Tags)
+	merged.RelatedContactLabels = union(keep.RelatedContactLabels, lose.RelatedContactLabels)
+	merged.Relationships = append([]model.Relationship(nil), keep.Relationships...)
+	for _, r := range lose.Relationships {
+		if !containsRelationship(merged.Relationships, r) {
+			merged.Relationships = append(merged.Relationships, r)
+		}
+	}
+	if len(keep.Dates)+len(lose.Dates) > 0 {
+		merged.Dates = make(map[string]string)
+		for name, d := range lose.Dates {
+			merged.Dates[name] = d
+		}
+		for name, d := range keep.Dates {
+			merged.Dates[name] = d
+		}
+	}
+
+	if lose.LastContacted != nil && (keep.LastContacted == nil || lose.LastContacted.After(*keep.LastContacted)) {
+		merged.LastContacted = lose.LastContacted
+		merged.LastInteractionType = lose.LastInteractionType
+	}
+	if lose.LastBumpDate != nil && (keep.LastBumpDate == nil || lose.LastBumpDate.After(*keep.LastBumpDate)) {
+		merged.LastBumpDate = lose.LastBumpDate
+	}
+	if merged.FollowUpDate == nil {
+		merged.FollowUpDate = lose.FollowUpDate
+	}
+	if merged.DeadlineDate == nil {
+		merged.DeadlineDate = lose.DeadlineDate
+	}
+	if merged.SnoozedUntil == nil {
+		merged.SnoozedUntil = lose.SnoozedUntil
+	}
+	if merged.RejectedFrequencyDays == 0 {
+		merged.RejectedFrequencyDays = lose.RejectedFrequencyDays
+	}
+
+	// Both sets of notes are kept, keep's first
+	switch {
+	case keep.Notes == "":
+		merged.Notes = lose.Notes
+	case lose.Notes != "" && lose.Notes != keep.Notes:
+		merged.Notes = keep.Notes + "\n\n" + lose.Notes
+	}
+
+	// The merged contact stays archived only when both were; otherwise
+	// the archiving is noted with the values not kept
+	merged.Archived, merged.ArchivedAt = false, nil
+	switch {
+	case keep.Archived && lose.Archived:
+		merged.Archived, merged.ArchivedAt = true, keep.ArchivedAt
+		if keep.ArchivedAt == nil || (lose.ArchivedAt != nil && lose.ArchivedAt.After(*keep.ArchivedAt)) {
+			merged.ArchivedAt = lose.ArchivedAt
+		}
+	case keep.Archived:
+		lost = append(lost, "- archived: "+archivedNote(keep))
+	case lose.Archived:
+		lost = append(lost, "- archived: "+archivedNote(lose))
+	}
+
+	// The merged contact can't point at either of its old selves
+	for _, label := range []string{keep.Label, lose.Label} {
+		merged.RelatedContactLabels = without(merged.RelatedContactLabels, label)
+		var rels []model.Relationship
+		for _, r := range merged.Relationships {
+			if model.NormalizeLabel(r.Label) != model.NormalizeLabel(label) || label == "" {
+				rels = append(rels, r)
+			}
+		}
+		merged.Relationships = rels
+	}
+
+	var extra []string
+	if len(lost) > 0 {
+		extra = append(extra, "Values not kept from "+lose.Identifier+":\n"+strings.Join(lost, "\n"))
+	}
+	merged.Content = model.MergeContent(keep.Content, lose.Content, lose.Title, extra)
+	merged.Interactions = model.ParseInteractions(merged.Content)
+	return merged
+}
+
+// archivedNote says which contact was archived, and when if known
+func archivedNote(c model.Contact) string {
+	if c.ArchivedAt == nil {
+		return c.Identifier
+	}
+	return fmt.Sprintf("%s on %s", c.Identifier, c.ArchivedAt.Format("2006-01-02"))
+}
+
+// union returns a followed by the values of b it lacks
+func union(a, b []string) []string {
+	out := append([]string(nil), a...)
+	for _, v := range b {
+		found := false
+		for _, o := range out {
+			if strings.EqualFold(o, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// without returns values minus any matching label
+func without(values []string, label string) []string {
+	if label == "" {
+		return values
+	}
+	var out []string
+	for _, v := range values {
+		if model.NormalizeLabel(v) != model.NormalizeLabel(label) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// containsRelationship reports whether rels holds r
+func containsRelationship(rels []model.Relationship, r model.Relationship) bool {
+	for _, o := range rels {
+		if model.NormalizeLabel(o.Label) == model.NormalizeLabel(r.Label) && o.Type == r.Type {
+			return true
+		}
+	}
+	return false
+}
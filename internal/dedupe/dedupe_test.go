@@ -0,0 +1,223 @@
+package dedupe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		min, max float64
+	}{
+		{"Jon Smith", "jon smith", 1, 1},
+		{"Jon Smith", "Jonathan Smith", 0.9, 0.9},
+		{"Bob Jones", "Robert Jones", 0.9, 0.9},
+		{"Jane Doe", "Jane Doh", 0.8, 0.9},
+		{"Jon Smith", "Jon Smythe-Baker", 0, 0.7},
+		{"Al Smith", "Alice Smith", 0, 0.8}, // Too short to be a short form
+	}
+	for _, tt := range tests {
+		if got := NameSimilarity(tt.a, tt.b); got < tt.min || got > tt.max {
+			t.Errorf("NameSimilarity(%q, %q) = %.2f, want %.2f-%.2f", tt.a, tt.b, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	contacts := []model.Contact{
+		{Title: "Jonathan Smith", Identifier: "20230101T000000", Email: "Jon+work@Example.com"},
+		{Title: "Jon Smith", Identifier: "20200101T000000", Email: "jon@example.com"},
+		{Title: "Ann Lee", Identifier: "20210101T000000", Phone: "+1 (555) 010-2000"},
+		{Title: "A. Lee", Identifier: "20220101T000000", Phone: "555.010.2000"},
+		{Title: "Someone Else", Identifier: "20220101T000001"},
+	}
+	got := Find(contacts)
+	if len(got) != 2 {
+		t.Fatalf("Find() = %+v, want 2 pairs", got)
+	}
+	if got[0].A != 1 || got[0].B != 0 {
+		t.Errorf("Find() first pair = %d, %d, want the email match with the older contact first", got[0].A, got[0].B)
+	}
+	if got[1].A != 2 || got[1].B != 3 || !strings.Contains(strings.Join(got[1].Reasons, ","), "same phone") {
+		t.Errorf("Find() second pair = %+v, want the phone match", got[1])
+	}
+}
+
+func TestMerge(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 1, d, 0, 0, 0, 0, time.Local)
+		return &t
+	}
+	keep := model.Contact{
+		Title: "Jon Smith", Identifier: "1", Email: "jon@example.com", Label: "@jon",
+		Tags: []string{"contact", "friends"}, LastContacted: day(1),
+		RelatedContactLabels: []string{"@jonathan", "@ann"},
+		Content:              "## Notes\n\nMet at school.\n\n## 2025-01-01 - meeting\n\nLunch.\n",
+	}
+	lose := model.Contact{
+		Title: "Jonathan Smith", Identifier: "2", Email: "jonathan@work.com", Phone: "555-0100", Label: "@jonathan",
+		Tags: []string{"contact", "work"}, LastContacted: day(9), LastInteractionType: "email",
+		Content: "## 2025-01-09 - email\n\nSent the deck.\n\n## 2025-01-01 - meeting\n\nLunch.\n",
+	}
+
+	got := Merge(keep, lose, map[string]bool{"email": true})
+	if got.Title != "Jon Smith" || got.Email != "jonathan@work.com" || got.Phone != "555-0100" {
+		t.Errorf("Merge() fields = %q %q %q", got.Title, got.Email, got.Phone)
+	}
+	if strings.Join(got.Tags, " ") != "contact friends work" {
+		t.Errorf("Merge() tags = %v", got.Tags)
+	}
+	if strings.Join(got.RelatedContactLabels, " ") != "@ann" {
+		t.Errorf("Merge() related = %v, want the link to the duplicate dropped", got.RelatedContactLabels)
+	}
+	if got.LastContacted != lose.LastContacted || got.LastInteractionType != "email" {
+		t.Errorf("Merge() last contacted = %v %s, want the later one", got.LastContacted, got.LastInteractionType)
+	}
+	if len(got.Interactions) != 2 || got.Interactions[0].Type != "email" {
+		t.Errorf("Merge() interactions = %+v, want the email then one meeting", got.Interactions)
+	}
+	for _, want := range []string{"Met at school.", "## Merged from Jonathan Smith", "- email: jon@example.com", "- name: Jonathan Smith"} {
+		if !strings.Contains(got.Content, want) {
+			t.Errorf("Merge() content lacks %q:\n%s", want, got.Content)
+		}
+	}
+}
+
+func TestMergeNotesAndArchive(t *testing.T) {
+	archived := time.Date(2025, 2, 1, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name         string
+		keep, lose   model.Contact
+		wantNotes    string
+		wantArchived bool
+		wantContent  string
+	}{
+		{
+			name:      "notes only on lose",
+			keep:      model.Contact{Title: "Jon", Identifier: "1"},
+			lose:      model.Contact{Title: "Jon", Identifier: "2", Notes: "met at PyCon, allergic to nuts", RejectedFrequencyDays: 30},
+			wantNotes: "met at PyCon, allergic to nuts",
+		},
+		{
+			name:      "notes on both",
+			keep:      model.Contact{Title: "Jon", Identifier: "1", Notes: "plays chess"},
+			lose:      model.Contact{Title: "Jon", Identifier: "2", Notes: "met at PyCon"},
+			wantNotes: "plays chess\n\nmet at PyCon",
+		},
+		{
+			name:        "only lose archived",
+			keep:        model.Contact{Title: "Jon", Identifier: "1"},
+			lose:        model.Contact{Title: "Jon", Identifier: "2", Archived: true, ArchivedAt: &archived},
+			wantContent: "- archived: 2 on 2025-02-01",
+		},
+		{
+			name:         "both archived",
+			keep:         model.Contact{Title: "Jon", Identifier: "1", Archived: true},
+			lose:         model.Contact{Title: "Jon", Identifier: "2", Archived: true, ArchivedAt: &archived},
+			wantArchived: true,
+		},
+	}
+	for _, tt := range tests {
+		got := Merge(tt.keep, tt.lose, nil)
+		if got.Notes != tt.wantNotes {
+			t.Errorf("%s: notes = %q, want %q", tt.name, got.Notes, tt.wantNotes)
+		}
+		if got.Archived != tt.wantArchived {
+			t.Errorf("%s: archived = %v, want %v", tt.name, got.Archived, tt.wantArchived)
+		}
+		if tt.wantArchived && got.ArchivedAt != &archived {
+			t.Errorf("%s: archived at = %v, want the later date", tt.name, got.ArchivedAt)
+		}
+		if !strings.Contains(got.Content, tt.wantContent) {
+			t.Errorf("%s: content lacks %q:\n%s", tt.name, tt.wantContent, got.Content)
+		}
+		if got.RejectedFrequencyDays != tt.lose.RejectedFrequencyDays {
+			t.Errorf("%s: rejected frequency = %d, want %d", tt.name, got.RejectedFrequencyDays, tt.lose.RejectedFrequencyDays)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	contactsDir, tasksDir := t.TempDir(), t.TempDir()
+	write := func(dir, name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write(contactsDir, "1--jon__contact.md", "---\ntitle: Jon\nidentifier: \"1\"\ntags: [contact]\nlabel: \"@jon\"\n---\n")
+	write(contactsDir, "2--jonathan__contact.md", "---\ntitle: Jonathan\nidentifier: \"2\"\ntags: [contact]\nlabel: \"@jonathan\"\n---\n")
+	write(contactsDir, "3--ann__contact.md", "---\ntitle: Ann\nidentifier: \"3\"\ntags: [contact]\nlabel: \"@ann\"\nrelationships:\n  - {label: \"@jonathan\", type: colleague}\n---\n")
+	task := write(tasksDir, "9--call__task.md", "---\ntitle: Call\ncontact_id: 2\n---\n\ncontact_id: 2\n")
+
+	contacts, err := parser.LoadContacts(contactsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := func(id string) model.Contact {
+		for _, c := range contacts {
+			if c.Identifier == id {
+				return c
+			}
+		}
+		t.Fatalf("no contact %s", id)
+		return model.Contact{}
+	}
+
+	result, err := Apply(contacts, byID("1"), byID("2"), nil, contactsDir, tasksDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tasks != 1 || len(result.Others) != 1 {
+		t.Errorf("Apply() = %+v, want 1 task and 1 linked contact updated", result)
+	}
+
+	after, err := parser.LoadContacts(contactsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != 2 {
+		t.Errorf("LoadContacts() after merge found %d contacts, want the duplicate in the trash", len(after))
+	}
+	if _, err := os.Stat(filepath.Join(parser.TrashDir(contactsDir), "2--jonathan__contact.md")); err != nil {
+		t.Errorf("duplicate not in trash: %v", err)
+	}
+	for _, c := range after {
+		if c.Identifier == "3" && (len(c.Relationships) != 1 || c.Relationships[0].Label != "@jon") {
+			t.Errorf("Ann's relationships = %+v, want them pointed at @jon", c.Relationships)
+		}
+	}
+	data, _ := os.ReadFile(task)
+	if want := "---\ntitle: Call\ncontact_id: 1\n---\n\ncontact_id: 2\n"; string(data) != want {
+		t.Errorf("task = %q, want %q", data, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	contacts := []model.Contact{
+		{Title: "Jon Smith", Email: "jon@example.com"},
+		{Title: "Ann Lee", Phone: "+1 (555) 010-2000"},
+	}
+	tests := []struct {
+		contact model.Contact
+		want    int
+	}{
+		{model.Contact{Title: "Jonathan", Email: "Jon+news@Example.com"}, 0},
+		{model.Contact{Title: "A. Lee", Phone: "555.010.2000"}, 1},
+		{model.Contact{Title: "Jon Smith"}, -1}, // Names alone don't match
+		{model.Contact{Title: "New", Email: "new@example.com", Phone: "555"}, -1},
+	}
+	for _, tt := range tests {
+		if got, reason := Match(contacts, tt.contact); got != tt.want {
+			t.Errorf("Match(%q) = %d (%s), want %d", tt.contact.Title, got, reason, tt.want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Package gitsync commits contact changes to the git repository holding the
+// contacts directory, using the local git binary.
+package gitsync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Commit modes
+const (
+	ModeChange  = "change"  // Commit after every change
+	ModeSession = "session" // Commit everything changed in a session at the end
+)
+
+// ErrNotRepo is returned by Open when the directory is not in a git repository
+var ErrNotRepo = errors.New("not in a git repository")
+
+// Repo is the git working tree holding the contacts directory
+type Repo struct {
+	root string // Top of the working tree
+	dir  string // The contacts directory
+}
+
+// Open finds the repository the contacts directory is in
+func Open(dir string) (*Repo, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not found: %v", err)
+	}
+	root, err := run(abs, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, ErrNotRepo
+	}
+	// The top level comes back with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	return &Repo{root: strings.TrimSpace(root), dir: abs}, nil
+}
+
+// run runs git in dir and returns its output, or its error output as the
+// error
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return stdout.String(), fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return stdout.String(), nil
+}
+
+// git runs a git command at the top of the working tree
+func (r *Repo) git(args ...string) (string, error) {
+	return run(r.root, args...)
+}
+
+// inside returns the paths within the working tree, relative to its top
+func (r *Repo) inside(paths []string) []string {
+	var rel []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		// Resolve the directory, as the file itself may be gone
+		if dir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			abs = filepath.Join(dir, filepath.Base(abs))
+		}
+		p, err := filepath.Rel(r.root, abs)
+		if err != nil || strings.HasPrefix(p, "..") || seen[p] {
+			continue
+		}
+		seen[p] = true
+		rel = append(rel, p)
+	}
+	return rel
+}
+
+// Commit commits the changes to the given paths, and nothing else, with the
+// message. Paths outside the working tree, ignored by git, or neither on
+// disk nor tracked are skipped.
+// It returns false when there was nothing to commit.
+func (r *Repo) Commit(message string, paths ...string) (bool, error) {
+	rel := r.inside(paths)
+	if len(rel) == 0 {
+		return false, nil
+	}
+
+	// Leave out ignored files, such as a trash folder in .gitignore
+	ignored := make(map[string]bool)
+	out, _ := r.git(append([]string{"check-ignore", "--"}, rel...)...)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		ignored[line] = true
+	}
+	// Leave out files that were never tracked and are gone again, such as
+	// a contact created and trashed in one session; git rejects them
+	tracked := make(map[string]bool)
+	out, _ = r.git(append([]string{"ls-files", "-z", "--"}, rel...)...)
+	for _, name := range strings.Split(out, "\x00") {
+		tracked[name] = true
+	}
+	var keep []string
+	for _, p := range rel {
+		if ignored[p] {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(r.root, p)); err != nil && !tracked[p] {
+			continue
+		}
+		keep = append(keep, p)
+	}
+	if len(keep) == 0 {
+		return false, nil
+	}
+
+	// Stage additions, edits and removals of just these paths
+	if _, err := r.git(append([]string{"add", "-A", "--"}, keep...)...); err != nil {
+		return false, err
+	}
+	staged, err := r.git(append([]string{"diff", "--cached", "--name-only", "--"}, keep...)...)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(staged) == "" {
+		return false, nil
+	}
+	if _, err := r.git(append([]string{"commit", "-q", "-m", message, "--"}, keep...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Status is the state of the contacts directory in the repository
+type Status struct {
+	Branch string
+	Dirty  int // Changed or untracked files in the contacts directory
+	Ahead  int // Commits not yet pushed to the upstream
+	Behind int // Upstream commits not yet pulled
+}
+
+// Status reports uncommitted changes in the contacts directory and how far
+// the branch is from its upstream
+func (r *Repo) Status() (Status, error) {
+	out, err := r.git("status", "--porcelain", "--branch", "--", r.dir)
+	if err != nil {
+		return Status{}, err
+	}
+	return parseStatus(out), nil
+}
+
+// parseStatus reads the output of git status --porcelain --branch
+func parseStatus(out string) Status {
+	var s Status
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "## ") {
+			s.Dirty++
+			continue
+		}
+
+		// ## main...origin/main [ahead 2, behind 1]
+		head := strings.TrimPrefix(line, "## ")
+		if i := strings.Index(head, " ["); i >= 0 {
+			for _, part := range strings.Split(strings.Trim(head[i+2:], "]"), ", ") {
+				fields := strings.Fields(part)
+				if len(fields) != 2 {
+					continue
+				}
+				n, _ := strconv.Atoi(fields[1])
+				switch fields[0] {
+				case "ahead":
+					s.Ahead = n
+				case "behind":
+					s.Behind = n
+				}
+			}
+			head = head[:i]
+		}
+		s.Branch, _, _ = strings.Cut(head, "...")
+		s.Branch = strings.TrimPrefix(s.Branch, "No commits yet on ")
+	}
+	return s
+}
+
+// FileCommit is a commit that touched a file
+type FileCommit struct {
+	Hash    string
+	Date    string // YYYY-MM-DD
+	Subject string
+}
+
+// Log lists the commits that touched a file, newest first, following
+// renames
+func (r *Repo) Log(path string) ([]FileCommit, error) {
+	rel := r.inside([]string{path})
+	if len(rel) == 0 {
+		return nil, fmt.Errorf("%s is outside the repository", filepath.Base(path))
+	}
+	out, err := r.git("log", "--follow", "--format=%h%x1f%ad%x1f%s", "--date=short", "--", rel[0])
+	if err != nil {
+		return nil, err
+	}
+	var commits []FileCommit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) == 3 {
+			commits = append(commits, FileCommit{Hash: parts[0], Date: parts[1], Subject: parts[2]})
+		}
+	}
+	return commits, nil
+}
+
+// Diff returns what a commit changed, limited to the file when it's
+// named in the commit
+func (r *Repo) Diff(hash, path string) (string, error) {
+	args := []string{"show", "--format=", "--find-renames", hash}
+	if rel := r.inside([]string{path}); len(rel) > 0 {
+		// Following renames, the file may have had another name
+		names, err := r.git("show", "--format=", "--name-only", hash)
+		if err == nil && containsLine(names, rel[0]) {
+			args = append(args, "--", rel[0])
+		}
+	}
+	return r.git(args...)
+}
+
+// containsLine reports whether text has line as one of its lines
+func containsLine(text, line string) bool {
+	for _, l := range strings.Split(text, "\n") {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+// Syncer commits recorded changes, after each change or once per session
+type Syncer struct {
+	repo *Repo
+	mode string
+
+	mu      sync.Mutex // Guards the pending session changes
+	actions []string
+	paths   []string
+}
+
+// NewSyncer commits changes to repo in the given mode, ModeChange if empty
+func NewSyncer(repo *Repo, mode string) (*Syncer, error) {
+	switch mode {
+	case "":
+		mode = ModeChange
+	case ModeChange, ModeSession:
+	default:
+		return nil, fmt.Errorf("unknown git commit mode %q: use %q or %q", mode, ModeChange, ModeSession)
+	}
+	return &Syncer{repo: repo, mode: mode}, nil
+}
+
+// Repo returns the repository changes are committed to
+func (s *Syncer) Repo() *Repo {
+	return s.repo
+}
+
+// Record commits a change to the paths, or holds it for the end of the
+// session. A nil Syncer does nothing.
+func (s *Syncer) Record(action string, paths []string) error {
+	if s == nil || len(paths) == 0 {
+		return nil
+	}
+	if s.mode == ModeChange {
+		_, err := s.repo.Commit(action, paths...)
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actions = append(s.actions, action)
+	s.paths = append(s.paths, paths...)
+	return nil
+}
+
+// Flush commits the changes held for the session as one commit listing
+// each of them
+func (s *Syncer) Flush() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.actions) == 0 {
+		return nil
+	}
+
+	message := s.actions[0]
+	if len(s.actions) > 1 {
+		message = fmt.Sprintf("Update contacts (%d changes)\n\n- %s", len(s.actions), strings.Join(s.actions, "\n- "))
+	}
+	if _, err := s.repo.Commit(message, s.paths...); err != nil {
+		return err
+	}
+	s.actions, s.paths = nil, nil
+	return nil
+}
@@ -0,0 +1,218 @@
+package gitsync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newRepo creates a git repository with a contacts directory and one
+// committed contact
+func newRepo(t *testing.T) (string, *Repo) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		if _, err := run(root, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir := filepath.Join(root, "contacts")
+	os.MkdirAll(dir, 0755)
+	writeFile(t, filepath.Join(dir, "a__contact.md"), "---\ntitle: A\n---\n")
+	writeFile(t, filepath.Join(root, ".gitignore"), "contacts/.trash/\n")
+	if _, err := run(root, "add", "-A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(root, "commit", "-q", "-m", "Initial"); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, repo
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// subjects returns the commit subjects, newest first
+func subjects(t *testing.T, repo *Repo) []string {
+	t.Helper()
+	out, err := repo.git("log", "--format=%s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(out), "\n")
+}
+
+func TestOpenOutsideRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	if _, err := Open(t.TempDir()); err != ErrNotRepo {
+		t.Errorf("Open() error = %v, want %v", err, ErrNotRepo)
+	}
+}
+
+func TestCommitOnlyGivenPaths(t *testing.T) {
+	dir, repo := newRepo(t)
+	a := filepath.Join(dir, "a__contact.md")
+	b := filepath.Join(dir, "b__contact.md")
+	writeFile(t, a, "---\ntitle: A\nstate: ping\n---\n")
+	writeFile(t, b, "---\ntitle: B\n---\n")
+
+	committed, err := repo.Commit("Log call with A", a)
+	if err != nil || !committed {
+		t.Fatalf("Commit() = %v, %v", committed, err)
+	}
+	if got := subjects(t, repo)[0]; got != "Log call with A" {
+		t.Errorf("subject = %q", got)
+	}
+
+	// b is left uncommitted and shows as dirty
+	status, err := repo.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Dirty != 1 || status.Branch != "main" {
+		t.Errorf("Status() = %+v, want 1 dirty on main", status)
+	}
+
+	// Nothing left to commit for a
+	if committed, err := repo.Commit("Again", a); committed || err != nil {
+		t.Errorf("second Commit() = %v, %v", committed, err)
+	}
+}
+
+func TestCommitMoveToIgnoredTrash(t *testing.T) {
+	dir, repo := newRepo(t)
+	a := filepath.Join(dir, "a__contact.md")
+	trashed := filepath.Join(dir, ".trash", "a__contact.md")
+	os.MkdirAll(filepath.Dir(trashed), 0755)
+	if err := os.Rename(a, trashed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Commit("Delete A", a, trashed); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	files, _ := repo.git("ls-files")
+	if strings.Contains(files, "a__contact.md") {
+		t.Errorf("a__contact.md still tracked: %q", files)
+	}
+
+	log, err := repo.Log(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 2 || log[0].Subject != "Delete A" {
+		t.Errorf("Log() = %+v", log)
+	}
+	diff, err := repo.Diff(log[0].Hash, a)
+	if err != nil || !strings.Contains(diff, "-title: A") {
+		t.Errorf("Diff() = %q, %v", diff, err)
+	}
+}
+
+func TestSessionSyncer(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes func(t *testing.T, dir string, s *Syncer)
+		want    []string // Lines of the session commit message
+		files   string   // Tracked files after the commit
+	}{
+		{
+			name: "changes in one commit",
+			changes: func(t *testing.T, dir string, s *Syncer) {
+				a := filepath.Join(dir, "a__contact.md")
+				b := filepath.Join(dir, "b__contact.md")
+				writeFile(t, a, "---\ntitle: A\nbump_count: 1\n---\n")
+				s.Record("Bump A", []string{a})
+				writeFile(t, b, "---\ntitle: B\n---\n")
+				s.Record("Create B", []string{b})
+			},
+			want:  []string{"Update contacts (2 changes)", "", "- Bump A", "- Create B"},
+			files: ".gitignore\ncontacts/a__contact.md\ncontacts/b__contact.md",
+		},
+		{
+			name: "contact created and trashed",
+			changes: func(t *testing.T, dir string, s *Syncer) {
+				a := filepath.Join(dir, "a__contact.md")
+				b := filepath.Join(dir, "b__contact.md")
+				trashed := filepath.Join(dir, ".trash", "b__contact.md")
+				writeFile(t, b, "---\ntitle: B\n---\n")
+				s.Record("Create B", []string{b})
+				os.MkdirAll(filepath.Dir(trashed), 0755)
+				if err := os.Rename(b, trashed); err != nil {
+					t.Fatal(err)
+				}
+				s.Record("Move B to the trash", []string{b, trashed})
+				writeFile(t, a, "---\ntitle: A\nbump_count: 1\n---\n")
+				s.Record("Bump A", []string{a})
+			},
+			want:  []string{"Update contacts (3 changes)", "", "- Create B", "- Move B to the trash", "- Bump A"},
+			files: ".gitignore\ncontacts/a__contact.md",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, repo := newRepo(t)
+			s, err := NewSyncer(repo, ModeSession)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.changes(t, dir, s)
+
+			if got := len(subjects(t, repo)); got != 1 {
+				t.Errorf("%d commits before Flush, want 1", got)
+			}
+			if err := s.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			out, _ := repo.git("log", "-1", "--format=%B")
+			if got := strings.TrimSpace(out); got != strings.Join(tt.want, "\n") {
+				t.Errorf("session commit message = %q, want %q", got, strings.Join(tt.want, "\n"))
+			}
+			files, _ := repo.git("ls-files")
+			if got := strings.TrimSpace(files); got != tt.files {
+				t.Errorf("tracked files = %q, want %q", got, tt.files)
+			}
+			if status, err := repo.Status(); err != nil || status.Dirty != 0 {
+				t.Errorf("Status() = %+v, %v, want nothing left uncommitted", status, err)
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		out  string
+		want Status
+	}{
+		{"## main\n", Status{Branch: "main"}},
+		{"## main...origin/main [ahead 2]\n M a.md\n?? b.md\n", Status{Branch: "main", Dirty: 2, Ahead: 2}},
+		{"## main...origin/main [ahead 1, behind 3]\n", Status{Branch: "main", Ahead: 1, Behind: 3}},
+		{"## No commits yet on main\n", Status{Branch: "main"}},
+	}
+	for _, tt := range tests {
+		if got := parseStatus(tt.out); got != tt.want {
+			t.Errorf("parseStatus(%q) = %+v, want %+v", tt.out, got, tt.want)
+		}
+	}
+}
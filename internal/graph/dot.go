@@ -0,0 +1,143 @@
+// Package graph exports the related contacts graph, built from labels,
+// relationships and related_contact_labels, to Graphviz DOT.
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// typeColors fill nodes by relationship type, so clusters stand out
+var typeColors = map[model.RelationshipType]string{
+	model.RelationshipFamily:     "#f4a261",
+	model.RelationshipClose:      "#e76f51",
+	model.RelationshipWork:       "#2a9d8f",
+	model.RelationshipNetwork:    "#8ab17d",
+	model.RelationshipSocial:     "#e9c46a",
+	model.RelationshipRecruiters: "#a8dadc",
+	model.RelationshipProviders:  "#cdb4db",
+}
+
+// Edge links two contacts by index. A is always below B, so links listed on
+// both sides come out once. Type is the relationship type read from A's
+// side, e.g. "manager" when A is B's manager, and empty for plain related
+// contacts.
+type Edge struct {
+	A, B int
+	Type string
+}
+
+// Edges returns the links between contacts, sorted. A plain link is dropped
+// when the pair also has a typed one. Labels no contact has are skipped.
+func Edges(contacts []model.Contact) []Edge {
+	seen := make(map[Edge]bool)
+	typed := make(map[[2]int]bool)
+	var edges []Edge
+	add := func(i, j int, r model.Relationship) {
+		e := Edge{A: i, B: j, Type: model.NormalizeRelationshipType(r.Type)}
+		if j < i {
+			e = Edge{A: j, B: i}
+			if r.Type != "" {
+				e.Type = r.Inverse("").Type
+			}
+		}
+		if !seen[e] {
+			seen[e] = true
+			edges = append(edges, e)
+		}
+		if e.Type != "" {
+			typed[[2]int{e.A, e.B}] = true
+		}
+	}
+	for i := range contacts {
+		for _, r := range contacts[i].Relationships {
+			if j := model.FindByLabel(contacts, r.Label); j >= 0 && j != i {
+				add(i, j, r)
+			}
+		}
+		for _, label := range contacts[i].RelatedContactLabels {
+			if j := model.FindByLabel(contacts, label); j >= 0 && j != i {
+				add(i, j, model.Relationship{})
+			}
+		}
+	}
+
+	kept := edges[:0]
+	for _, e := range edges {
+		if e.Type != "" || !typed[[2]int{e.A, e.B}] {
+			kept = append(kept, e)
+		}
+	}
+	edges = kept
+	sort.Slice(edges, func(x, y int) bool {
+		if edges[x].A != edges[y].A {
+			return edges[x].A < edges[y].A
+		}
+		if edges[x].B != edges[y].B {
+			return edges[x].B < edges[y].B
+		}
+		return edges[x].Type < edges[y].Type
+	})
+	return edges
+}
+
+// WriteDOT writes the graph as an undirected Graphviz graph. Contacts with no
+// links are left out unless all is set.
+func WriteDOT(w io.Writer, contacts []model.Contact, all bool) error {
+	edges := Edges(contacts)
+	linked := make(map[int]bool)
+	for _, e := range edges {
+		linked[e.A], linked[e.B] = true, true
+	}
+
+	var b strings.Builder
+	b.WriteString("graph contacts {\n")
+	b.WriteString("  overlap=false;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#eeeeee\", fontname=\"Helvetica\"];\n")
+	for i, c := range contacts {
+		if !all && !linked[i] {
+			continue
+		}
+		attrs := []string{"label=" + strconv.Quote(nodeLabel(c))}
+		if color, ok := typeColors[c.RelationshipType]; ok {
+			attrs = append(attrs, "fillcolor="+strconv.Quote(color))
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", nodeID(c, i), strings.Join(attrs, ", "))
+	}
+	for _, e := range edges {
+		attrs := ""
+		if e.Type != "" {
+			attrs = fmt.Sprintf(" [label=%s, fontsize=10]", strconv.Quote(e.Type))
+		}
+		fmt.Fprintf(&b, "  %s -- %s%s;\n", nodeID(contacts[e.A], e.A), nodeID(contacts[e.B], e.B), attrs)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// nodeID returns a stable node ID: the Denote identifier when there is one
+func nodeID(c model.Contact, i int) string {
+	if c.Identifier != "" {
+		return strconv.Quote(c.Identifier)
+	}
+	return strconv.Quote(fmt.Sprintf("contact-%d", i))
+}
+
+// nodeLabel shows the name with the label and company under it
+func nodeLabel(c model.Contact) string {
+	lines := []string{c.Title}
+	if label := model.NormalizeLabel(c.Label); label != "" {
+		lines = append(lines, label)
+	}
+	if c.Company != "" {
+		lines = append(lines, c.Company)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+func TestWriteDOT(t *testing.T) {
+	contacts := []model.Contact{
+		{Title: "Ann", Identifier: "20250101T000000", Label: "@ann", RelationshipType: model.RelationshipWork, RelatedContactLabels: []string{"@bob"}},
+		{Title: "Bob", Identifier: "20250102T000000", Label: "@bob", RelatedContactLabels: []string{"@ann", "@nobody"}},
+		{Title: "Cat", Identifier: "20250103T000000"},
+	}
+
+	var b strings.Builder
+	if err := WriteDOT(&b, contacts, false); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	if n := strings.Count(out, " -- "); n != 1 {
+		t.Errorf("WriteDOT() wrote %d edges, want 1 for a link listed on both sides:\n%s", n, out)
+	}
+	for _, want := range []string{
+		`"20250101T000000" -- "20250102T000000";`,
+		`label="Ann\n@ann"`,
+		`fillcolor="#2a9d8f"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteDOT() output lacks %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Cat") {
+		t.Errorf("WriteDOT() included an unlinked contact without all:\n%s", out)
+	}
+
+	b.Reset()
+	if err := WriteDOT(&b, contacts, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Cat") {
+		t.Errorf("WriteDOT() with all left out an unlinked contact:\n%s", b.String())
+	}
+}
+
+func TestEdgesTyped(t *testing.T) {
+	contacts := []model.Contact{
+		{Title: "Bob", Label: "@bob", Relationships: []model.Relationship{{Label: "@ann", Type: "reports-to"}}},
+		{Title: "Ann", Label: "@ann", Relationships: []model.Relationship{{Label: "@bob", Type: "manager"}}, RelatedContactLabels: []string{"@bob"}},
+	}
+	got := Edges(contacts)
+	want := []Edge{{A: 0, B: 1, Type: "reports-to"}}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Edges() = %+v, want %+v", got, want)
+	}
+}
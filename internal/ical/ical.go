@@ -0,0 +1,172 @@
+// Package ical writes contacts' due dates, follow-ups, deadlines, meetings
+// and yearly dates as an iCalendar feed.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/contentline"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// uidDomain ends every UID, so they can't clash with another feed's
+const uidDomain = "denote-contacts"
+
+// meetingLength is how long a timed meeting is shown for
+const meetingLength = time.Hour
+
+// stateScheduled is the state of a contact with a meeting or call arranged
+// for its follow-up date
+const stateScheduled = "scheduled"
+
+// Event is one calendar entry for a contact
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	Timed       bool   // Start has a time of day; otherwise it's all day
+	Rule        string // RRULE for a repeating event, e.g. "FREQ=YEARLY"
+}
+
+// UID returns a stable UID for one kind of event of a contact, so a
+// calendar subscribed to the feed updates the event when its date moves
+func UID(identifier, kind string) string {
+	return identifier + "-" + kind + "@" + uidDomain
+}
+
+// Events returns a contact's events: the next due date, a pending follow-up
+// (a meeting in the scheduled state), a deadline, meetings logged for today
+// or later, and each birthday or named date repeating yearly. Archived
+// contacts have no due date, but keep their yearly dates.
+func Events(c model.Contact) []Event {
+	var events []Event
+	today := model.StartOfDay(clock.Now())
+
+	// Follow-ups and deadlines get their own events, so the due date is
+	// only added when something else set it. An overdue contact shows
+	// today, where a calendar will show it, with how overdue in the
+	// description.
+	if due, ok := c.NextDue(); ok {
+		if source := c.DueSource(); source != "follow-up" && source != "deadline" {
+			if due.Before(today) {
+				due = today
+			}
+			events = append(events, Event{
+				UID:         UID(c.Identifier, "due"),
+				Summary:     "Contact " + c.Title,
+				Description: strings.Join(c.DueExplanation(), "\n"),
+				Start:       due,
+			})
+		}
+	}
+
+	// A follow-up is done once a contact is logged on or after its date.
+	// In the scheduled state it's the date of a meeting or call.
+	if c.FollowUpDate != nil {
+		followUp := model.StartOfDay(*c.FollowUpDate)
+		if c.LastContacted == nil || model.StartOfDay(*c.LastContacted).Before(followUp) {
+			summary := "Follow up with " + c.Title
+			if c.State == stateScheduled {
+				summary = "Meeting with " + c.Title
+			}
+			events = append(events, Event{
+				UID:     UID(c.Identifier, "follow-up"),
+				Summary: summary,
+				Start:   followUp,
+			})
+		}
+	}
+	if c.DeadlineDate != nil {
+		events = append(events, Event{
+			UID:     UID(c.Identifier, "deadline"),
+			Summary: "Deadline for " + c.Title,
+			Start:   model.StartOfDay(*c.DeadlineDate),
+		})
+	}
+
+	for _, in := range c.Interactions {
+		if in.Type != model.InteractionMeeting || in.Date.Before(today) {
+			continue
+		}
+		timed := in.Date.Hour() != 0 || in.Date.Minute() != 0
+		events = append(events, Event{
+			UID:         UID(c.Identifier, "meeting-"+in.Date.Format("20060102T1504")),
+			Summary:     "Meeting with " + c.Title,
+			Description: in.Summary,
+			Start:       in.Date,
+			Timed:       timed,
+		})
+	}
+
+	for _, e := range c.Events() {
+		events = append(events, yearly(c, e))
+	}
+	return events
+}
+
+// yearly makes a repeating event of a birthday or named date. It starts in
+// the year of the date when known. February 29 falls on the last day of
+// February in other years.
+func yearly(c model.Contact, e model.Event) Event {
+	year := e.Year
+	if year == 0 {
+		year = 2000 // A leap year, so February 29 is a real date
+	}
+	rule := "FREQ=YEARLY"
+	if e.Month == time.February && e.Day == 29 {
+		rule = "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1"
+	}
+	return Event{
+		UID:     UID(c.Identifier, strings.NewReplacer("_", "-", " ", "-").Replace(e.Name)),
+		Summary: fmt.Sprintf("%s: %s", c.Title, e.Label()),
+		Start:   e.In(year),
+		Rule:    rule,
+	}
+}
+
+// Write writes the events of the contacts as one calendar
+func Write(w io.Writer, contacts []model.Contact) error {
+	stamp := clock.Now().UTC().Format("20060102T150405Z")
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//denote-contacts//Contacts//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Contacts",
+	}
+	for _, c := range contacts {
+		for _, e := range Events(c) {
+			lines = append(lines, "BEGIN:VEVENT", "UID:"+e.UID, "DTSTAMP:"+stamp)
+			if e.Timed {
+				lines = append(lines,
+					"DTSTART:"+e.Start.UTC().Format("20060102T150405Z"),
+					"DTEND:"+e.Start.Add(meetingLength).UTC().Format("20060102T150405Z"))
+			} else {
+				lines = append(lines,
+					"DTSTART;VALUE=DATE:"+e.Start.Format("20060102"),
+					"DTEND;VALUE=DATE:"+e.Start.AddDate(0, 0, 1).Format("20060102"))
+			}
+			if e.Rule != "" {
+				lines = append(lines, "RRULE:"+e.Rule)
+			}
+			lines = append(lines, "SUMMARY:"+contentline.Escape(e.Summary))
+			if e.Description != "" {
+				lines = append(lines, "DESCRIPTION:"+contentline.Escape(e.Description))
+			}
+			lines = append(lines, "END:VEVENT")
+		}
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, contentline.Fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
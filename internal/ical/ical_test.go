@@ -0,0 +1,151 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+func date(year int, month time.Month, day int) *time.Time {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	return &t
+}
+
+func TestEvents(t *testing.T) {
+	previous := clock.Set(clock.Fixed(time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)))
+	t.Cleanup(func() { clock.Set(previous) })
+
+	ann := model.Contact{
+		Title:            "Ann Lee",
+		Identifier:       "20240101T100000",
+		RelationshipType: model.RelationshipClose,
+		ContactStyle:     model.StylePeriodic,
+		LastContacted:    date(2025, 6, 1),
+		Birthday:         "1988-02-29",
+		Dates:            map[string]string{"work_anniversary": "03-15"},
+		Interactions: []model.Interaction{
+			{Date: time.Date(2025, 6, 20, 14, 30, 0, 0, time.Local), Type: model.InteractionMeeting, Summary: "Lunch"},
+			{Date: time.Date(2025, 5, 1, 0, 0, 0, 0, time.Local), Type: model.InteractionMeeting},
+		},
+	}
+	bob := model.Contact{
+		Title:        "Bob Stone",
+		Identifier:   "20240102T100000",
+		ContactStyle: model.StyleAmbient,
+		FollowUpDate: date(2025, 6, 12),
+		DeadlineDate: date(2025, 7, 1),
+	}
+	done := bob
+	done.Identifier = "20240103T100000"
+	done.DeadlineDate = nil
+	done.LastContacted = date(2025, 6, 12)
+	overdue := model.Contact{
+		Title:            "Cat Ng",
+		Identifier:       "20240104T100000",
+		RelationshipType: model.RelationshipClose,
+		ContactStyle:     model.StylePeriodic,
+		LastContacted:    date(2024, 1, 1),
+	}
+	meeting := model.Contact{
+		Title:        "Dan Roe",
+		Identifier:   "20240105T100000",
+		ContactStyle: model.StyleAmbient,
+		State:        "scheduled",
+		FollowUpDate: date(2025, 6, 18),
+	}
+
+	due := ann.GetFrequencyDays()
+	tests := []struct {
+		name    string
+		contact model.Contact
+		want    map[string]Event // By UID
+	}{
+		{"periodic with meetings and yearly dates", ann, map[string]Event{
+			UID(ann.Identifier, "due"):                   {Start: *date(2025, 6, 1+due)},
+			UID(ann.Identifier, "meeting-20250620T1430"): {Start: ann.Interactions[0].Date, Timed: true},
+			UID(ann.Identifier, "birthday"):              {Start: *date(1988, 2, 29), Rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=-1"},
+			UID(ann.Identifier, "work-anniversary"):      {Start: *date(2000, 3, 15), Rule: "FREQ=YEARLY"},
+		}},
+		{"follow-up and deadline", bob, map[string]Event{
+			UID(bob.Identifier, "follow-up"): {Start: *date(2025, 6, 12)},
+			UID(bob.Identifier, "deadline"):  {Start: *date(2025, 7, 1)},
+		}},
+		{"follow-up done", done, map[string]Event{}},
+		{"overdue shows today", overdue, map[string]Event{
+			UID(overdue.Identifier, "due"): {Start: *date(2025, 6, 10)},
+		}},
+		{"scheduled meeting", meeting, map[string]Event{
+			UID(meeting.Identifier, "follow-up"): {Start: *date(2025, 6, 18), Summary: "Meeting with Dan Roe"},
+		}},
+	}
+	for _, tt := range tests {
+		got := Events(tt.contact)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Events() = %d events, want %d: %+v", tt.name, len(got), len(tt.want), got)
+			continue
+		}
+		for _, e := range got {
+			want, ok := tt.want[e.UID]
+			if !ok {
+				t.Errorf("%s: unexpected event %s", tt.name, e.UID)
+				continue
+			}
+			if want.Summary != "" && e.Summary != want.Summary {
+				t.Errorf("%s: %s summary = %q, want %q", tt.name, e.UID, e.Summary, want.Summary)
+			}
+			if !e.Start.Equal(want.Start) || e.Timed != want.Timed || e.Rule != want.Rule {
+				t.Errorf("%s: %s = %v timed %v rule %q, want %v timed %v rule %q",
+					tt.name, e.UID, e.Start, e.Timed, e.Rule, want.Start, want.Timed, want.Rule)
+			}
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	previous := clock.Set(clock.Fixed(time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)))
+	t.Cleanup(func() { clock.Set(previous) })
+
+	contacts := []model.Contact{{
+		Title:      "Ann Lee, PhD",
+		Identifier: "20240101T100000",
+		Birthday:   "05-01",
+		Interactions: []model.Interaction{{
+			Date:    time.Date(2025, 6, 20, 0, 0, 0, 0, time.Local),
+			Type:    model.InteractionMeeting,
+			Summary: "A long agenda for the meeting that has to be folded across more than one line.",
+		}},
+	}}
+
+	var b strings.Builder
+	if err := Write(&b, contacts); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:20240101T100000-birthday@denote-contacts\r\n",
+		"DTSTART;VALUE=DATE:20000501\r\nDTEND;VALUE=DATE:20000502\r\nRRULE:FREQ=YEARLY\r\n",
+		"DTSTART;VALUE=DATE:20250620\r\n",
+		`SUMMARY:Ann Lee\, PhD: Birthday`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Write() has no %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+
+	// Exporting again gives the same UIDs
+	var again strings.Builder
+	Write(&again, contacts)
+	if again.String() != out {
+		t.Error("Write() is not stable")
+	}
+}
@@ -0,0 +1,276 @@
+// Package index keeps an inverted index of the words in contact notes, so
+// searching notes doesn't scan every file. The index can be cached on disk
+// and is rebuilt only for files that changed since.
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// Doc is the indexed form of one contact body
+type Doc struct {
+	ModTime time.Time `json:"mod_time"`
+	Lines   []string  `json:"lines"` // Non-empty body lines, used for snippets
+	Terms   []string  `json:"terms"` // Unique lowercase terms in the body
+}
+
+// Hit is a body match for a single contact
+type Hit struct {
+	Path    string
+	Snippet string // Matching line, prefixed with its interaction heading
+}
+
+// Index is an in-memory inverted index over contact bodies
+type Index struct {
+	docs     map[string]Doc
+	postings map[string]map[string]bool // term -> set of file paths
+	terms    []string                   // Sorted terms for prefix lookups
+	dirty    bool                       // Terms need re-sorting
+}
+
+// New returns an empty index
+func New() *Index {
+	return &Index{
+		docs:     make(map[string]Doc),
+		postings: make(map[string]map[string]bool),
+	}
+}
+
+// Build indexes the bodies of the given contacts. Docs found in the cache
+// with an unchanged modification time are reused instead of re-tokenized.
+func Build(contacts []model.Contact, cache map[string]Doc) *Index {
+	idx := New()
+	for _, contact := range contacts {
+		modTime := fileModTime(contact.FilePath)
+		if doc, ok := cache[contact.FilePath]; ok && !modTime.IsZero() && doc.ModTime.Equal(modTime) {
+			idx.add(contact.FilePath, doc)
+			continue
+		}
+		idx.add(contact.FilePath, newDoc(contact.Content, modTime))
+	}
+	return idx
+}
+
+// Update re-indexes a single contact after it has been saved
+func (idx *Index) Update(contact model.Contact) {
+	idx.Remove(contact.FilePath)
+	idx.add(contact.FilePath, newDoc(contact.Content, fileModTime(contact.FilePath)))
+}
+
+// Remove drops a contact from the index
+func (idx *Index) Remove(path string) {
+	doc, ok := idx.docs[path]
+	if !ok {
+		return
+	}
+	for _, term := range doc.Terms {
+		delete(idx.postings[term], path)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+			idx.dirty = true
+		}
+	}
+	delete(idx.docs, path)
+}
+
+// Search returns the contacts whose body contains every term of the query.
+// The last term is matched as a prefix so results update while typing.
+func (idx *Index) Search(query string) map[string]Hit {
+	queryTerms := Tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	var candidates map[string]bool
+	for i, term := range queryTerms {
+		var paths map[string]bool
+		if i == len(queryTerms)-1 {
+			paths = idx.prefixPaths(term)
+		} else {
+			paths = idx.postings[term]
+		}
+
+		if candidates == nil {
+			candidates = make(map[string]bool, len(paths))
+			for path := range paths {
+				candidates[path] = true
+			}
+			continue
+		}
+		for path := range candidates {
+			if !paths[path] {
+				delete(candidates, path)
+			}
+		}
+	}
+
+	hits := make(map[string]Hit, len(candidates))
+	for path := range candidates {
+		hits[path] = Hit{Path: path, Snippet: snippet(idx.docs[path].Lines, queryTerms)}
+	}
+	return hits
+}
+
+// Docs returns the indexed docs keyed by file path, for caching
+func (idx *Index) Docs() map[string]Doc {
+	return idx.docs
+}
+
+// add inserts a doc into the postings
+func (idx *Index) add(path string, doc Doc) {
+	idx.docs[path] = doc
+	for _, term := range doc.Terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]bool)
+			idx.dirty = true
+		}
+		idx.postings[term][path] = true
+	}
+}
+
+// prefixPaths returns the union of postings for every term with the prefix
+func (idx *Index) prefixPaths(prefix string) map[string]bool {
+	if idx.dirty {
+		idx.terms = idx.terms[:0]
+		for term := range idx.postings {
+			idx.terms = append(idx.terms, term)
+		}
+		sort.Strings(idx.terms)
+		idx.dirty = false
+	}
+
+	paths := make(map[string]bool)
+	start := sort.SearchStrings(idx.terms, prefix)
+	for i := start; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], prefix); i++ {
+		for path := range idx.postings[idx.terms[i]] {
+			paths[path] = true
+		}
+	}
+	return paths
+}
+
+// Tokenize splits text into lowercase terms of letters and digits
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var terms []string
+	for _, f := range fields {
+		if len([]rune(f)) >= 2 {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// newDoc tokenizes a contact body
+func newDoc(content string, modTime time.Time) Doc {
+	doc := Doc{ModTime: modTime}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		doc.Lines = append(doc.Lines, line)
+		for _, term := range Tokenize(line) {
+			if !seen[term] {
+				seen[term] = true
+				doc.Terms = append(doc.Terms, term)
+			}
+		}
+	}
+	return doc
+}
+
+// snippet finds the line matching the most query terms and prefixes it with
+// the heading of the interaction it belongs to
+func snippet(lines []string, queryTerms []string) string {
+	bestLine, bestScore := -1, 0
+	for i, line := range lines {
+		score := 0
+		lineTerms := Tokenize(line)
+		for qi, q := range queryTerms {
+			for _, t := range lineTerms {
+				if t == q || (qi == len(queryTerms)-1 && strings.HasPrefix(t, q)) {
+					score++
+					break
+				}
+			}
+		}
+		if score > bestScore {
+			bestLine, bestScore = i, score
+		}
+	}
+	if bestLine < 0 {
+		return ""
+	}
+
+	line := lines[bestLine]
+	if strings.HasPrefix(line, "#") {
+		return strings.TrimSpace(strings.TrimLeft(line, "#"))
+	}
+	for i := bestLine - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(lines[i], "#"))
+			return heading + ": " + line
+		}
+	}
+	return line
+}
+
+// fileModTime returns the modification time of path, or zero if unavailable
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// CachePath returns the location of the on-disk index cache
+func CachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "denote-contacts", "index.json"), nil
+}
+
+// LoadCache reads cached docs from disk. A missing cache is not an error.
+func LoadCache(path string) (map[string]Doc, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading index cache: %w", err)
+	}
+
+	var docs map[string]Doc
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("error parsing index cache: %w", err)
+	}
+	return docs, nil
+}
+
+// SaveCache writes the index docs to disk
+func (idx *Index) SaveCache(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	data, err := json.Marshal(idx.docs)
+	if err != nil {
+		return fmt.Errorf("error encoding index cache: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,176 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// contacts are the bodies searched in the tests, keyed by file path
+var contacts = []model.Contact{
+	{FilePath: "ann.md", Content: "## 2025-03-01 meeting\nTalked about the garden project\n\n## 2025-02-01 email\nSent the budget"},
+	{FilePath: "bob.md", Content: "Met at the gardening club\nLikes budget spreadsheets"},
+	{FilePath: "cat.md", Content: "Café owner in Zürich"},
+}
+
+// paths returns the sorted paths of the hits
+func paths(hits map[string]Hit) []string {
+	var p []string
+	for path := range hits {
+		p = append(p, path)
+	}
+	sort.Strings(p)
+	return p
+}
+
+func TestSearch(t *testing.T) {
+	idx := Build(contacts, nil)
+
+	tests := []struct {
+		query   string
+		want    []string
+		snippet map[string]string // Snippet by path, where checked
+	}{
+		{query: "budget", want: []string{"ann.md", "bob.md"}, snippet: map[string]string{
+			"ann.md": "2025-02-01 email: Sent the budget",
+			"bob.md": "Likes budget spreadsheets",
+		}},
+		{query: "gard", want: []string{"ann.md", "bob.md"}},
+		{query: "garden budget", want: []string{"ann.md"}},
+		{query: "gard budget", want: nil}, // Only the last term is a prefix
+		{query: "GARDEN project", want: []string{"ann.md"}, snippet: map[string]string{
+			"ann.md": "2025-03-01 meeting: Talked about the garden project",
+		}},
+		{query: "meeting", want: []string{"ann.md"}, snippet: map[string]string{
+			"ann.md": "2025-03-01 meeting",
+		}},
+		{query: "zür", want: []string{"cat.md"}, snippet: map[string]string{
+			"cat.md": "Café owner in Zürich",
+		}},
+		{query: "nothing", want: nil},
+		{query: "a", want: nil}, // Too short to be a term
+	}
+	for _, tt := range tests {
+		hits := idx.Search(tt.query)
+		got := paths(hits)
+		if len(got) != len(tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+		for path, want := range tt.snippet {
+			if hits[path].Snippet != want {
+				t.Errorf("Search(%q) snippet for %s = %q, want %q", tt.query, path, hits[path].Snippet, want)
+			}
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	idx := Build(contacts, nil)
+
+	// Searching sorts the terms; the update must mark them for re-sorting
+	if got := paths(idx.Search("gard")); len(got) != 2 {
+		t.Fatalf("Search(gard) = %v before update", got)
+	}
+	idx.Update(model.Contact{FilePath: "bob.md", Content: "Plays chess on Sundays"})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"gard", []string{"ann.md"}},
+		{"spreadsheets", nil},
+		{"chess", []string{"bob.md"}},
+		{"sund", []string{"bob.md"}},
+	}
+	for _, tt := range tests {
+		got := paths(idx.Search(tt.query))
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("after Update, Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	idx.Remove("ann.md")
+	if got := paths(idx.Search("garden")); len(got) != 0 {
+		t.Errorf("after Remove, Search(garden) = %v, want none", got)
+	}
+	if _, ok := idx.Docs()["ann.md"]; ok {
+		t.Error("after Remove, ann.md is still in Docs")
+	}
+}
+
+func TestBuildFromCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ann.md")
+	if err := os.WriteFile(path, []byte("unused"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contact := model.Contact{FilePath: path, Content: "Talked about orchids"}
+
+	tests := []struct {
+		name    string
+		modTime time.Time
+		want    string // The term that should be found
+		notWant string
+	}{
+		{"fresh cache is reused", info.ModTime(), "tulips", "orchids"},
+		{"stale cache is re-read", info.ModTime().Add(-time.Hour), "orchids", "tulips"},
+	}
+	for _, tt := range tests {
+		cache := map[string]Doc{
+			path: newDoc("Talked about tulips", tt.modTime),
+		}
+		idx := Build([]model.Contact{contact}, cache)
+		if len(idx.Search(tt.want)) != 1 {
+			t.Errorf("%s: Search(%q) found nothing", tt.name, tt.want)
+		}
+		if len(idx.Search(tt.notWant)) != 0 {
+			t.Errorf("%s: Search(%q) found the other body", tt.name, tt.notWant)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "index.json")
+
+	docs, err := LoadCache(path)
+	if err != nil || docs != nil {
+		t.Fatalf("LoadCache(missing) = %v, %v, want nil, nil", docs, err)
+	}
+
+	if err := Build(contacts, nil).SaveCache(path); err != nil {
+		t.Fatalf("SaveCache: %v", err)
+	}
+	docs, err = LoadCache(path)
+	if err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	idx := New()
+	for p, doc := range docs {
+		idx.add(p, doc)
+	}
+	if got := paths(idx.Search("budget")); len(got) != 2 {
+		t.Errorf("Search(budget) on the loaded cache = %v, want ann.md and bob.md", got)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCache(path); err == nil {
+		t.Error("LoadCache(corrupt) = nil error, want an error")
+	}
+}
@@ -0,0 +1,103 @@
+package journal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffCells bounds the line diff's table. Bodies larger than this are
+// recorded as one hunk replacing everything.
+const maxDiffCells = 1 << 22
+
+// Hunk is one step of a line diff: keep Keep lines, then replace the lines
+// in Del with the lines in Add. Lines keep their trailing newline.
+type Hunk struct {
+	Keep int      `json:"keep,omitempty"`
+	Del  []string `json:"del,omitempty"`
+	Add  []string `json:"add,omitempty"`
+}
+
+// splitLines cuts text into lines, each with its newline, so joining them
+// gives the text back
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.SplitAfter(text, "\n")
+}
+
+// diffLines returns the hunks that turn a into b, using the longest common
+// subsequence of lines
+func diffLines(a, b []string) []Hunk {
+	n, m := len(a), len(b)
+	if n*m > maxDiffCells {
+		return []Hunk{{Del: a, Add: b}}
+	}
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var hunks []Hunk
+	var cur Hunk
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			if len(cur.Del) > 0 || len(cur.Add) > 0 {
+				hunks = append(hunks, cur)
+				cur = Hunk{}
+			}
+			cur.Keep++
+			i++
+			j++
+		case j >= m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			cur.Del = append(cur.Del, a[i])
+			i++
+		default:
+			cur.Add = append(cur.Add, b[j])
+			j++
+		}
+	}
+	if len(cur.Del) > 0 || len(cur.Add) > 0 {
+		hunks = append(hunks, cur)
+	}
+	return hunks
+}
+
+// patchLines applies hunks to lines, or undoes them when reverse is set.
+// It fails if the lines being replaced are not what the hunks expect.
+func patchLines(lines []string, hunks []Hunk, reverse bool) ([]string, error) {
+	var out []string
+	pos := 0
+	for _, h := range hunks {
+		from, to := h.Del, h.Add
+		if reverse {
+			from, to = to, from
+		}
+		if pos+h.Keep+len(from) > len(lines) {
+			return nil, fmt.Errorf("body is shorter than expected")
+		}
+		out = append(out, lines[pos:pos+h.Keep]...)
+		pos += h.Keep
+		for k, line := range from {
+			if lines[pos+k] != line {
+				return nil, fmt.Errorf("body line %d differs", pos+k+1)
+			}
+		}
+		pos += len(from)
+		out = append(out, to...)
+	}
+	return append(out, lines[pos:]...), nil
+}
@@ -0,0 +1,469 @@
+// Package journal records every change made to contact and task files so it
+// can be undone and redone, in this session or a later one.
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+)
+
+// DefaultLimit is how many operations the history keeps unless SetLimit
+// changes it
+const DefaultLimit = 500
+
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// Op is one recorded action and the files it changed
+type Op struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Changes []Change  `json:"changes"`
+}
+
+// Paths returns the files the operation changed
+func (op Op) Paths() []string {
+	paths := make([]string, len(op.Changes))
+	for i, c := range op.Changes {
+		paths[i] = c.Path
+	}
+	return paths
+}
+
+// Change is what an operation did to one file: the frontmatter before and
+// after, and a line diff of the body
+type Change struct {
+	Path        string `json:"path"`
+	Created     bool   `json:"created,omitempty"` // The file didn't exist before
+	Removed     bool   `json:"removed,omitempty"` // The file didn't exist after
+	FrontBefore string `json:"front_before,omitempty"`
+	FrontAfter  string `json:"front_after,omitempty"`
+	Body        []Hunk `json:"body,omitempty"`
+}
+
+// record is a line of the history file: an operation, or the undo or redo
+// of one
+type record struct {
+	Op   *Op       `json:"op,omitempty"`
+	Undo string    `json:"undo,omitempty"`
+	Redo string    `json:"redo,omitempty"`
+	Time time.Time `json:"time,omitempty"`
+}
+
+// snapshot is a file's content at one moment
+type snapshot struct {
+	exists bool
+	data   string
+}
+
+// tx collects the files touched while an action runs
+type tx struct {
+	before map[string]snapshot
+	order  []string
+}
+
+// Journal is the undo history, kept in a JSON lines file. A nil Journal
+// records nothing.
+type Journal struct {
+	path  string
+	limit int // Operations kept; older ones can no longer be undone
+
+	busy sync.Mutex // Held while an action runs and while undoing or redoing
+
+	mu     sync.Mutex // Guards the fields below
+	done   []*Op      // Applied operations, oldest first
+	undone []*Op      // Undone operations, most recently undone last
+	lines  int        // Records in the history file
+	tx     *tx
+}
+
+// Path returns the history file location
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "denote-contacts", "history.jsonl"), nil
+}
+
+// Open loads the history in path. A missing file is an empty history, and
+// lines that don't parse are skipped.
+func Open(path string) (*Journal, error) {
+	j := &Journal{path: path, limit: DefaultLimit}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read history '%s': %v", path, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		var r record
+		if strings.TrimSpace(line) == "" || json.Unmarshal([]byte(line), &r) != nil {
+			continue
+		}
+		j.lines++
+		switch {
+		case r.Op != nil:
+			j.done = append(j.done, r.Op)
+			j.undone = nil
+		case r.Undo != "":
+			if op, ok := take(&j.done, r.Undo); ok {
+				j.undone = append(j.undone, op)
+			}
+		case r.Redo != "":
+			if op, ok := take(&j.undone, r.Redo); ok {
+				j.done = append(j.done, op)
+			}
+		}
+	}
+	j.trim()
+	return j, nil
+}
+
+// SetLimit sets how many operations the history keeps, DefaultLimit when n
+// isn't positive. The file is trimmed on the next commit.
+func (j *Journal) SetLimit(n int) {
+	if j == nil {
+		return
+	}
+	if n <= 0 {
+		n = DefaultLimit
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.limit = n
+	j.trim()
+}
+
+// trim drops the oldest operations beyond the limit
+func (j *Journal) trim() {
+	if extra := len(j.done) - j.limit; extra > 0 {
+		j.done = append([]*Op(nil), j.done[extra:]...)
+	}
+	if extra := len(j.undone) - j.limit; extra > 0 {
+		j.undone = append([]*Op(nil), j.undone[extra:]...)
+	}
+}
+
+// take removes the operation with the id from ops
+func take(ops *[]*Op, id string) (*Op, bool) {
+	for i := len(*ops) - 1; i >= 0; i-- {
+		if (*ops)[i].ID == id {
+			op := (*ops)[i]
+			*ops = append((*ops)[:i], (*ops)[i+1:]...)
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+// Begin starts recording an action. Files touched until Commit are recorded
+// together, and other actions wait for Commit.
+func (j *Journal) Begin() {
+	if j == nil {
+		return
+	}
+	j.busy.Lock()
+	j.mu.Lock()
+	j.tx = &tx{before: make(map[string]snapshot)}
+	j.mu.Unlock()
+}
+
+// Touch notes files about to be written, moved or removed by the current
+// action. Files are read the first time they're touched. Outside an action
+// it does nothing.
+func (j *Journal) Touch(paths ...string) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.tx == nil {
+		return
+	}
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if _, ok := j.tx.before[path]; ok {
+			continue
+		}
+		j.tx.before[path] = readSnapshot(path)
+		j.tx.order = append(j.tx.order, path)
+	}
+}
+
+// Commit ends the current action and records what it changed under the
+// action's name. It returns nil when nothing changed. A new operation clears
+// what could be redone.
+func (j *Journal) Commit(action string) (*Op, error) {
+	if j == nil {
+		return nil, nil
+	}
+	defer j.busy.Unlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	t := j.tx
+	j.tx = nil
+	if t == nil {
+		return nil, nil
+	}
+
+	op := &Op{
+		ID:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		Time:   clock.Now(),
+		Action: action,
+	}
+	for _, path := range t.order {
+		before, after := t.before[path], readSnapshot(path)
+		if before == after {
+			continue
+		}
+		op.Changes = append(op.Changes, newChange(path, before, after))
+	}
+	if len(op.Changes) == 0 {
+		return nil, nil
+	}
+
+	j.done = append(j.done, op)
+	j.undone = nil
+	j.trim()
+	if err := j.append(record{Op: op}); err != nil {
+		return op, err
+	}
+	// Undos and redos add records too, so rewrite the file once it holds
+	// twice what the history keeps rather than after every commit
+	if j.lines > 2*j.limit {
+		return op, j.rewrite()
+	}
+	return op, nil
+}
+
+// Undo reverts the most recent operation still applied
+func (j *Journal) Undo() (*Op, error) {
+	if j == nil {
+		return nil, ErrNothingToUndo
+	}
+	return j.step(&j.done, &j.undone, false)
+}
+
+// Redo applies the most recently undone operation again
+func (j *Journal) Redo() (*Op, error) {
+	if j == nil {
+		return nil, ErrNothingToRedo
+	}
+	return j.step(&j.undone, &j.done, true)
+}
+
+// step moves the last operation of from onto to, rewriting its files
+// forward or back. Nothing is written unless every file is as the
+// operation left it.
+func (j *Journal) step(from, to *[]*Op, forward bool) (*Op, error) {
+	j.busy.Lock()
+	defer j.busy.Unlock()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(*from) == 0 {
+		if forward {
+			return nil, ErrNothingToRedo
+		}
+		return nil, ErrNothingToUndo
+	}
+	op := (*from)[len(*from)-1]
+
+	next := make([]snapshot, len(op.Changes))
+	for i, c := range op.Changes {
+		s, err := c.apply(readSnapshot(c.Path), forward)
+		if err != nil {
+			return nil, fmt.Errorf("can't %s %q: %s has changed since (%v)", verb(forward), op.Action, filepath.Base(c.Path), err)
+		}
+		next[i] = s
+	}
+	for i, c := range op.Changes {
+		if err := writeSnapshot(c.Path, next[i]); err != nil {
+			return nil, fmt.Errorf("failed to %s %q: %v", verb(forward), op.Action, err)
+		}
+	}
+
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, op)
+	r := record{Undo: op.ID, Time: clock.Now()}
+	if forward {
+		r = record{Redo: op.ID, Time: clock.Now()}
+	}
+	return op, j.append(r)
+}
+
+// verb names the direction of a step for messages
+func verb(forward bool) string {
+	if forward {
+		return "redo"
+	}
+	return "undo"
+}
+
+// History returns the applied operations, oldest first, and the operations
+// that can be redone, next to redo first
+func (j *Journal) History() (done, undone []Op) {
+	if j == nil {
+		return nil, nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, op := range j.done {
+		done = append(done, *op)
+	}
+	for i := len(j.undone) - 1; i >= 0; i-- {
+		undone = append(undone, *j.undone[i])
+	}
+	return done, undone
+}
+
+// append adds a record to the history file
+func (j *Journal) append(r record) error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %v", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %v", err)
+	}
+	j.lines++
+	return nil
+}
+
+// rewrite replaces the history file with a record for each operation kept.
+// It is only called with nothing to redo, so the operations are all it needs.
+func (j *Journal) rewrite() error {
+	if j.path == "" {
+		return nil
+	}
+	var data []byte
+	for _, op := range j.done {
+		line, err := json.Marshal(record{Op: op})
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history: %v", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write history: %v", err)
+	}
+	j.lines = len(j.done)
+	return nil
+}
+
+// newChange records how a file went from before to after
+func newChange(path string, before, after snapshot) Change {
+	frontBefore, bodyBefore := splitFront(before.data)
+	frontAfter, bodyAfter := splitFront(after.data)
+	return Change{
+		Path:        path,
+		Created:     !before.exists,
+		Removed:     !after.exists,
+		FrontBefore: frontBefore,
+		FrontAfter:  frontAfter,
+		Body:        diffLines(splitLines(bodyBefore), splitLines(bodyAfter)),
+	}
+}
+
+// apply works out the file on the other side of the change from current,
+// which must be what the change left on this side. forward goes from
+// before to after.
+func (c Change) apply(current snapshot, forward bool) (snapshot, error) {
+	fromExists, toExists := !c.Created, !c.Removed
+	fromFront, toFront := c.FrontBefore, c.FrontAfter
+	if !forward {
+		fromExists, toExists = toExists, fromExists
+		fromFront, toFront = toFront, fromFront
+	}
+
+	if current.exists != fromExists {
+		if current.exists {
+			return snapshot{}, errors.New("file was created")
+		}
+		return snapshot{}, errors.New("file was removed")
+	}
+	front, body := splitFront(current.data)
+	if front != fromFront {
+		return snapshot{}, errors.New("frontmatter differs")
+	}
+	lines, err := patchLines(splitLines(body), c.Body, !forward)
+	if err != nil {
+		return snapshot{}, err
+	}
+	if !toExists {
+		if len(lines) > 0 {
+			return snapshot{}, errors.New("body differs")
+		}
+		return snapshot{}, nil
+	}
+	return snapshot{exists: true, data: toFront + strings.Join(lines, "")}, nil
+}
+
+// splitFront cuts a file into its frontmatter, with both --- lines, and
+// the body after it
+func splitFront(content string) (front, body string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	end := strings.Index(content[4:], "\n---\n")
+	if end < 0 {
+		return "", content
+	}
+	cut := 4 + end + len("\n---\n")
+	return content[:cut], content[cut:]
+}
+
+// readSnapshot reads a file, which may not exist
+func readSnapshot(path string) snapshot {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot{}
+	}
+	return snapshot{exists: true, data: string(data)}
+}
+
+// writeSnapshot writes a file back, or removes it
+func writeSnapshot(path string, s snapshot) error {
+	if !s.exists {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(s.data), 0644)
+}
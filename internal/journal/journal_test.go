@@ -0,0 +1,267 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"identical", "a\nb\nc\n", "a\nb\nc\n"},
+		{"insert at top", "a\nb\n", "new\na\nb\n"},
+		{"insert in middle", "a\nb\n", "a\nnew\nb\n"},
+		{"delete", "a\nb\nc\n", "a\nc\n"},
+		{"replace", "a\nb\nc\n", "a\nB\nc\n"},
+		{"from empty", "", "a\nb\n"},
+		{"to empty", "a\nb\n", ""},
+		{"no trailing newline", "a\nb", "a\nb\nc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := diffLines(splitLines(tt.a), splitLines(tt.b))
+			forward, err := patchLines(splitLines(tt.a), hunks, false)
+			if err != nil {
+				t.Fatalf("patch forward: %v", err)
+			}
+			if got := strings.Join(forward, ""); got != tt.b {
+				t.Errorf("forward = %q, want %q", got, tt.b)
+			}
+			back, err := patchLines(splitLines(tt.b), hunks, true)
+			if err != nil {
+				t.Fatalf("patch back: %v", err)
+			}
+			if got := strings.Join(back, ""); got != tt.a {
+				t.Errorf("back = %q, want %q", got, tt.a)
+			}
+		})
+	}
+}
+
+// write creates a file for a test
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read returns a file's content, or "<missing>"
+func read(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "<missing>"
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUndoRedo(t *testing.T) {
+	dir := t.TempDir()
+	history := filepath.Join(dir, "history.jsonl")
+	path := filepath.Join(dir, "20240101T100000--ann__contact.md")
+	before := "---\ntitle: Ann\nstate: ok\n---\n## 2024-01-01 10:00 - call\n\nHello\n"
+	after := "---\ntitle: Ann\nstate: ping\n---\n## 2024-02-01 10:00 - note\n\n## 2024-01-01 10:00 - call\n\nHello\n"
+	write(t, path, before)
+
+	j, err := Open(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Begin()
+	j.Touch(path)
+	write(t, path, after)
+	op, err := j.Commit("Logged note with Ann")
+	if err != nil || op == nil {
+		t.Fatalf("Commit() = %v, %v", op, err)
+	}
+	if c := op.Changes[0]; c.FrontBefore != "---\ntitle: Ann\nstate: ok\n---\n" || len(c.Body) != 1 {
+		t.Errorf("change = %+v", c)
+	}
+
+	if _, err := j.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := read(t, path); got != before {
+		t.Errorf("after undo = %q, want %q", got, before)
+	}
+	if _, err := j.Undo(); err != ErrNothingToUndo {
+		t.Errorf("second Undo() error = %v, want %v", err, ErrNothingToUndo)
+	}
+
+	// The history survives a restart
+	j, err = Open(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Redo(); err != nil {
+		t.Fatalf("Redo() after reopening error = %v", err)
+	}
+	if got := read(t, path); got != after {
+		t.Errorf("after redo = %q, want %q", got, after)
+	}
+
+	j, err = Open(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, undone := j.History()
+	if len(done) != 1 || len(undone) != 0 || done[0].Action != "Logged note with Ann" {
+		t.Errorf("History() = %v, %v", done, undone)
+	}
+}
+
+func TestUndoMoveAndCreate(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open(filepath.Join(dir, "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "a__contact.md")
+	trashed := filepath.Join(dir, ".trash", "a__contact.md")
+	created := filepath.Join(dir, "task.md")
+	content := "---\ntitle: A\n---\nbody\n"
+	write(t, path, content)
+	os.MkdirAll(filepath.Dir(trashed), 0755)
+
+	j.Begin()
+	j.Touch(path, trashed, created)
+	os.Rename(path, trashed)
+	write(t, created, "---\ntitle: Task\n---\n")
+	if _, err := j.Commit("Moved A to the trash"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if read(t, path) != content || read(t, trashed) != "<missing>" || read(t, created) != "<missing>" {
+		t.Errorf("after undo: %q, %q, %q", read(t, path), read(t, trashed), read(t, created))
+	}
+	if _, err := j.Redo(); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	if read(t, path) != "<missing>" || read(t, trashed) != content {
+		t.Errorf("after redo: %q, %q", read(t, path), read(t, trashed))
+	}
+}
+
+func TestUndoRefusesChangedFile(t *testing.T) {
+	dir := t.TempDir()
+	j, err := Open(filepath.Join(dir, "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "a__contact.md")
+	write(t, path, "---\nbump_count: 1\n---\n")
+
+	j.Begin()
+	j.Touch(path)
+	write(t, path, "---\nbump_count: 2\n---\n")
+	j.Commit("Bumped A")
+
+	// Edited elsewhere since
+	write(t, path, "---\nbump_count: 2\nstate: ping\n---\n")
+	if _, err := j.Undo(); err == nil {
+		t.Fatal("Undo() of a changed file succeeded")
+	}
+	if got := read(t, path); got != "---\nbump_count: 2\nstate: ping\n---\n" {
+		t.Errorf("file rewritten to %q", got)
+	}
+
+	// Nothing changed, nothing recorded
+	j.Begin()
+	j.Touch(path)
+	if op, err := j.Commit("Nothing"); op != nil || err != nil {
+		t.Errorf("Commit() with no change = %v, %v", op, err)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	dir := t.TempDir()
+	history := filepath.Join(dir, "history.jsonl")
+	path := filepath.Join(dir, "20240101T100000--ann__contact.md")
+	version := func(n int) string { return fmt.Sprintf("---\ntitle: Ann\n---\nVersion %d\n", n) }
+	write(t, path, version(0))
+
+	j, err := Open(history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.SetLimit(3)
+	change := func(n int) {
+		t.Helper()
+		j.Begin()
+		j.Touch(path)
+		write(t, path, version(n))
+		if _, err := j.Commit(fmt.Sprintf("Change %d", n)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := func() int {
+		return strings.Count(read(t, history), "\n")
+	}
+	step := func(redo bool, want int) {
+		t.Helper()
+		step, name := j.Undo, "Undo"
+		if redo {
+			step, name = j.Redo, "Redo"
+		}
+		if _, err := step(); err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if got := read(t, path); got != version(want) {
+			t.Fatalf("after %s = %q, want %q", name, got, version(want))
+		}
+	}
+
+	for n := 1; n <= 7; n++ {
+		change(n)
+	}
+	// The seventh commit takes the file past twice the limit, so it is
+	// rewritten with the three operations kept
+	if got := lines(); got != 3 {
+		t.Errorf("history file has %d lines, want 3", got)
+	}
+	if done, _ := j.History(); len(done) != 3 || done[0].Action != "Change 5" {
+		t.Errorf("History() = %v, want changes 5 to 7", done)
+	}
+
+	step(false, 6)
+	step(false, 5)
+	step(false, 4)
+	if _, err := j.Undo(); err != ErrNothingToUndo {
+		t.Errorf("fourth Undo() error = %v, want %v", err, ErrNothingToUndo)
+	}
+	step(true, 5)
+	step(true, 6)
+
+	// Undo and redo still replay after the rewrite
+	if j, err = Open(history); err != nil {
+		t.Fatal(err)
+	}
+	j.SetLimit(3)
+	if done, undone := j.History(); len(done) != 2 || len(undone) != 1 || undone[0].Action != "Change 7" {
+		t.Errorf("History() after reopening = %v, %v", done, undone)
+	}
+	step(true, 7)
+
+	change(8)
+	if got := lines(); got != 3 {
+		t.Errorf("history file has %d lines after another commit, want 3", got)
+	}
+	step(false, 7)
+	step(false, 6)
+	step(false, 5)
+	if _, err := j.Undo(); err != ErrNothingToUndo {
+		t.Errorf("Undo() past the limit error = %v, want %v", err, ErrNothingToUndo)
+	}
+}
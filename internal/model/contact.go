@@ -1,7 +1,11 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
 )
 
 // RelationshipType defines the type of relationship and default contact frequency
@@ -26,6 +30,39 @@ const (
 	StyleTriggered ContactStyle = "triggered" // Event-based
 )
 
+// relationshipTypes are the known relationship types, in the order shown
+var relationshipTypes = []RelationshipType{
+	RelationshipClose, RelationshipFamily, RelationshipNetwork, RelationshipWork,
+	RelationshipSocial, RelationshipProviders, RelationshipRecruiters,
+}
+
+// contactStyles are the known contact styles
+var contactStyles = []ContactStyle{StylePeriodic, StyleAmbient, StyleTriggered}
+
+// ParseRelationshipType returns the relationship type named by s, in any case
+func ParseRelationshipType(s string) (RelationshipType, error) {
+	var names []string
+	for _, t := range relationshipTypes {
+		if strings.EqualFold(strings.TrimSpace(s), string(t)) {
+			return t, nil
+		}
+		names = append(names, string(t))
+	}
+	return "", fmt.Errorf("unknown type %q: use one of %s", s, strings.Join(names, ", "))
+}
+
+// ParseContactStyle returns the contact style named by s, in any case
+func ParseContactStyle(s string) (ContactStyle, error) {
+	var names []string
+	for _, st := range contactStyles {
+		if strings.EqualFold(strings.TrimSpace(s), string(st)) {
+			return st, nil
+		}
+		names = append(names, string(st))
+	}
+	return "", fmt.Errorf("unknown contact style %q: use one of %s", s, strings.Join(names, ", "))
+}
+
 // ContactState represents the current state of a contact
 type ContactState string
 
@@ -66,24 +103,46 @@ type Contact struct {
 	LastContacted    *time.Time       `yaml:"last_contacted,omitempty"`
 	LastBumpDate     *time.Time       `yaml:"last_bump_date,omitempty"`
 	BumpCount        int              `yaml:"bump_count,omitempty"`
+	SnoozedUntil     *time.Time       `yaml:"snoozed_until,omitempty"`
+	FollowUpDate     *time.Time       `yaml:"follow_up_date,omitempty"`
+	DeadlineDate     *time.Time       `yaml:"deadline_date,omitempty"`
+	Archived         bool             `yaml:"archived,omitempty"`
+	ArchivedAt       *time.Time       `yaml:"archived_at,omitempty"`
 	UpdatedAt        time.Time        `yaml:"updated_at"`
 
 	// Optional fields
-	Company              string   `yaml:"company,omitempty"`
-	Role                 string   `yaml:"role,omitempty"`
-	Location             string   `yaml:"location,omitempty"`
-	Birthday             string   `yaml:"birthday,omitempty"`
-	LinkedIn             string   `yaml:"linkedin,omitempty"`
-	Twitter              string   `yaml:"twitter,omitempty"`
-	Website              string   `yaml:"website,omitempty"`
-	Notes                string   `yaml:"notes,omitempty"`
-	CustomFrequencyDays  int      `yaml:"custom_frequency_days,omitempty"`
-	LastInteractionType  string   `yaml:"last_interaction_type,omitempty"`
-	RelatedContactLabels []string `yaml:"related_contact_labels,omitempty"`
+	Company               string   `yaml:"company,omitempty"`
+	Role                  string   `yaml:"role,omitempty"`
+	Location              string   `yaml:"location,omitempty"`
+	Birthday              string   `yaml:"birthday,omitempty"`                // MM-DD or YYYY-MM-DD
+	LinkedIn              string   `yaml:"linkedin,omitempty"`
+	Twitter               string   `yaml:"twitter,omitempty"`
+	Website               string   `yaml:"website,omitempty"`
+	Notes                 string   `yaml:"notes,omitempty"`
+	CustomFrequencyDays   int      `yaml:"custom_frequency_days,omitempty"`
+	RejectedFrequencyDays int      `yaml:"rejected_frequency_days,omitempty"` // Last frequency suggestion turned down
+	LastInteractionType   string   `yaml:"last_interaction_type,omitempty"`
+	RelatedContactLabels  []string `yaml:"related_contact_labels,omitempty"`
+
+	// Named yearly dates such as anniversary, MM-DD or YYYY-MM-DD
+	Dates map[string]string `yaml:"dates,omitempty"`
+
+	// Typed links to other contacts by label, kept in step on both sides
+	Relationships []Relationship `yaml:"relationships,omitempty"`
 
 	// Runtime fields (not in YAML)
-	FilePath string `yaml:"-"`
-	Content  string `yaml:"-"` // Markdown content after frontmatter
+	FilePath string    `yaml:"-"`
+	Content  string    `yaml:"-"` // Markdown content after frontmatter
+	Triggers []Trigger `yaml:"-"` // Set by the trigger rules in the config
+	
+	// Interaction log parsed from Content, newest first
+	Interactions []Interaction `yaml:"-"`
+}
+
+// Trigger is a reason from a trigger rule for a contact to be due
+type Trigger struct {
+	Date   time.Time
+	Reason string
 }
 
 // Interaction represents a single interaction with a contact
@@ -116,7 +175,7 @@ func (c *Contact) DaysSinceContact() int {
 	if c.LastContacted == nil {
 		return -1 // Never contacted
 	}
-	duration := time.Since(*c.LastContacted)
+	duration := clock.Now().Sub(*c.LastContacted)
 	days := int(duration.Hours() / 24)
 	// Handle future dates (negative days)
 	if duration < 0 {
@@ -125,45 +184,26 @@ func (c *Contact) DaysSinceContact() int {
 	return days
 }
 
-// IsOverdue returns true if contact is overdue based on frequency
+// IsOverdue returns true if the contact's next due date has passed.
+// Snoozes hold everything back except deadlines.
 func (c *Contact) IsOverdue() bool {
-	// Only check overdue for periodic style
-	if c.ContactStyle != StylePeriodic && c.ContactStyle != "" {
-		return false
-	}
-	
-	freq := c.GetFrequencyDays()
-	if freq == 0 {
-		return false // No frequency set
-	}
-	
-	days := c.DaysSinceContact()
-	if days == -1 {
-		return true // Never contacted
-	}
-	
-	return days > freq
+	return c.DueState().Overdue
 }
 
-// NeedsAttention returns true if contact needs attention soon
+// NeedsAttention returns true if contact is due within the next 7 days.
+// Snoozes hold everything back except deadlines.
 func (c *Contact) NeedsAttention() bool {
-	// Only check for periodic style
-	if c.ContactStyle != StylePeriodic && c.ContactStyle != "" {
-		return false
-	}
-	
-	freq := c.GetFrequencyDays()
-	if freq == 0 {
-		return false
-	}
-	
-	days := c.DaysSinceContact()
-	if days == -1 {
-		return true
+	return c.DueState().Attention
+}
+
+// DaysUntilDue returns the days left before the contact is due, negative
+// once overdue. ok is false when the contact has no due date.
+func (c *Contact) DaysUntilDue() (days int, ok bool) {
+	due, ok := c.NextDue()
+	if !ok {
+		return 0, false
 	}
-	
-	// Needs attention if within 7 days of being overdue
-	return days > (freq - 7) && days <= freq
+	return DaysBetween(clock.Now(), due), true
 }
 
 // IsWithinThreshold returns true if contact has been contacted within their expected frequency
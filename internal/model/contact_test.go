@@ -0,0 +1,289 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+)
+
+// today is the fixed "now" for all tests: noon, so day boundaries are clear
+var today = time.Date(2025, time.March, 15, 12, 0, 0, 0, time.Local)
+
+// useFixedClock stops the package clock at today for the duration of a test
+func useFixedClock(t *testing.T) {
+	t.Helper()
+	previous := clock.Set(clock.Fixed(today))
+	t.Cleanup(func() { clock.Set(previous) })
+}
+
+// daysAgo returns a pointer to the time n days before today
+func daysAgo(n int) *time.Time {
+	d := today.AddDate(0, 0, -n)
+	return &d
+}
+
+func TestGetFrequencyDays(t *testing.T) {
+	tests := []struct {
+		name    string
+		relType RelationshipType
+		custom  int
+		want    int
+	}{
+		{"close", RelationshipClose, 0, 30},
+		{"family", RelationshipFamily, 0, 30},
+		{"work", RelationshipWork, 0, 60},
+		{"network", RelationshipNetwork, 0, 90},
+		{"social has no default", RelationshipSocial, 0, 0},
+		{"providers has no default", RelationshipProviders, 0, 0},
+		{"recruiters has no default", RelationshipRecruiters, 0, 0},
+		{"untyped has no default", "", 0, 0},
+		{"custom overrides type", RelationshipNetwork, 14, 14},
+		{"custom applies without default", RelationshipSocial, 45, 45},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Contact{RelationshipType: tt.relType, CustomFrequencyDays: tt.custom}
+			if got := c.GetFrequencyDays(); got != tt.want {
+				t.Errorf("GetFrequencyDays() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDueStatus(t *testing.T) {
+	useFixedClock(t)
+	
+	tests := []struct {
+		name      string
+		contact   Contact
+		overdue   bool
+		attention bool
+		threshold bool
+	}{
+		{
+			name:    "close contacted yesterday is good",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(1)},
+			threshold: true,
+		},
+		{
+			name:    "close at half frequency is still good",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(15)},
+			threshold: true,
+		},
+		{
+			name:    "close past half frequency is just ok",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(16)},
+		},
+		{
+			name:    "close 23 days ago is not yet in the attention window",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(23)},
+		},
+		{
+			name:      "close 24 days ago needs attention",
+			contact:   Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(24)},
+			attention: true,
+		},
+		{
+			name:      "close due today needs attention",
+			contact:   Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(30)},
+			attention: true,
+		},
+		{
+			name:    "close one day past frequency is overdue",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(31)},
+			overdue: true,
+		},
+		{
+			name:    "work uses 60 days",
+			contact: Contact{RelationshipType: RelationshipWork, LastContacted: daysAgo(45)},
+		},
+		{
+			name:    "work past 60 days is overdue",
+			contact: Contact{RelationshipType: RelationshipWork, LastContacted: daysAgo(61)},
+			overdue: true,
+		},
+		{
+			name:      "network 85 days ago needs attention",
+			contact:   Contact{RelationshipType: RelationshipNetwork, LastContacted: daysAgo(85)},
+			attention: true,
+		},
+		{
+			name:    "custom frequency overrides type",
+			contact: Contact{RelationshipType: RelationshipNetwork, CustomFrequencyDays: 10, LastContacted: daysAgo(11)},
+			overdue: true,
+		},
+		{
+			name:    "never contacted is overdue from creation",
+			contact: Contact{RelationshipType: RelationshipClose, Date: today.AddDate(0, 0, -3)},
+			overdue: true,
+		},
+		{
+			name:      "never contacted and created today is due today",
+			contact:   Contact{RelationshipType: RelationshipClose, Date: today},
+			attention: true,
+		},
+		{
+			name:    "social has no schedule",
+			contact: Contact{RelationshipType: RelationshipSocial, LastContacted: daysAgo(400)},
+		},
+		{
+			name:    "ambient style is never due",
+			contact: Contact{RelationshipType: RelationshipClose, ContactStyle: StyleAmbient, LastContacted: daysAgo(400)},
+		},
+		{
+			name:    "explicit periodic style behaves like the default",
+			contact: Contact{RelationshipType: RelationshipClose, ContactStyle: StylePeriodic, LastContacted: daysAgo(31)},
+			overdue: true,
+		},
+		{
+			name:    "bump defers an overdue contact",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(40), LastBumpDate: daysAgo(2), BumpCount: 1},
+			attention: true,
+		},
+		{
+			name:    "bump before the last contact is ignored",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(31), LastBumpDate: daysAgo(35), BumpCount: 1},
+			overdue: true,
+		},
+		{
+			name:    "snooze suppresses overdue",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(40), SnoozedUntil: daysAgo(-10)},
+		},
+		{
+			name:    "expired snooze no longer suppresses",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(40), SnoozedUntil: daysAgo(1)},
+			overdue: true,
+		},
+		{
+			name:    "past follow-up makes an ambient contact overdue",
+			contact: Contact{RelationshipType: RelationshipClose, ContactStyle: StyleAmbient, FollowUpDate: daysAgo(2)},
+			overdue: true,
+		},
+		{
+			name:    "follow-up done once contacted on or after it",
+			contact: Contact{RelationshipType: RelationshipSocial, FollowUpDate: daysAgo(5), LastContacted: daysAgo(3)},
+		},
+		{
+			name:    "deadline pierces a snooze",
+			contact: Contact{RelationshipType: RelationshipSocial, DeadlineDate: daysAgo(1), SnoozedUntil: daysAgo(-30)},
+			overdue: true,
+		},
+		{
+			name:    "archived contact is never due",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(40), Archived: true},
+		},
+		{
+			name:    "archived state is never due, even with a deadline",
+			contact: Contact{RelationshipType: RelationshipClose, State: "archived", DeadlineDate: daysAgo(1), FollowUpDate: daysAgo(2)},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.contact
+			if got := c.IsOverdue(); got != tt.overdue {
+				t.Errorf("IsOverdue() = %v, want %v", got, tt.overdue)
+			}
+			if got := c.NeedsAttention(); got != tt.attention {
+				t.Errorf("NeedsAttention() = %v, want %v", got, tt.attention)
+			}
+			if got := c.IsWithinThreshold(); got != tt.threshold {
+				t.Errorf("IsWithinThreshold() = %v, want %v", got, tt.threshold)
+			}
+		})
+	}
+}
+
+func TestBumpPolicy(t *testing.T) {
+	useFixedClock(t)
+	t.Cleanup(func() { SetBumpPolicies(BumpPolicy{DeferDays: 7}, nil) })
+	SetBumpPolicies(BumpPolicy{DeferDays: 7}, map[RelationshipType]BumpPolicy{
+		RelationshipWork: {DeferDays: 14, MaxBumps: 2},
+	})
+	
+	tests := []struct {
+		name    string
+		contact Contact
+		wantDue time.Time
+	}{
+		{
+			name:    "default policy defers 7 days from the bump",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(40), LastBumpDate: daysAgo(1), BumpCount: 5},
+			wantDue: StartOfDay(today).AddDate(0, 0, 6),
+		},
+		{
+			name:    "work policy defers 14 days",
+			contact: Contact{RelationshipType: RelationshipWork, LastContacted: daysAgo(70), LastBumpDate: daysAgo(1), BumpCount: 2},
+			wantDue: StartOfDay(today).AddDate(0, 0, 13),
+		},
+		{
+			name:    "work bumps past the limit stop deferring",
+			contact: Contact{RelationshipType: RelationshipWork, LastContacted: daysAgo(70), LastBumpDate: daysAgo(1), BumpCount: 3},
+			wantDue: StartOfDay(today).AddDate(0, 0, -10),
+		},
+		{
+			name:    "a count left from before the last contact doesn't stop deferring",
+			contact: Contact{RelationshipType: RelationshipWork, LastContacted: daysAgo(70), LastBumpDate: daysAgo(80), BumpCount: 9},
+			wantDue: StartOfDay(today).AddDate(0, 0, -10),
+		},
+		{
+			name: "a running total is capped at the bumps logged since the last contact",
+			contact: Contact{RelationshipType: RelationshipWork, LastContacted: daysAgo(70), LastBumpDate: daysAgo(1), BumpCount: 9,
+				Interactions: []Interaction{{Date: *daysAgo(1), Type: InteractionBump}, {Date: *daysAgo(90), Type: InteractionBump}}},
+			wantDue: StartOfDay(today).AddDate(0, 0, 13),
+		},
+		{
+			name:    "bump never moves the due date earlier",
+			contact: Contact{RelationshipType: RelationshipClose, LastContacted: daysAgo(1), LastBumpDate: daysAgo(0), BumpCount: 1},
+			wantDue: StartOfDay(today).AddDate(0, 0, 29),
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.contact
+			due, ok := c.NextDue()
+			if !ok {
+				t.Fatalf("NextDue() not scheduled")
+			}
+			if !due.Equal(tt.wantDue) {
+				t.Errorf("NextDue() = %s, want %s", due.Format("2006-01-02"), tt.wantDue.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestParseDateInput(t *testing.T) {
+	base := StartOfDay(today)
+	
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2025-04-01", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.Local), false},
+		{"10d", base.AddDate(0, 0, 10), false},
+		{"+3d", base.AddDate(0, 0, 3), false},
+		{"2w", base.AddDate(0, 0, 14), false},
+		{"3m", base.AddDate(0, 3, 0), false},
+		{"1y", base.AddDate(1, 0, 0), false},
+		{"", time.Time{}, true},
+		{"0d", time.Time{}, true},
+		{"soon", time.Time{}, true},
+		{"5x", time.Time{}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDateInput(tt.input, today)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateInput(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseDateInput(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StartOfDay truncates t to local midnight
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// DaysBetween returns the number of calendar days from a to b
+func DaysBetween(a, b time.Time) int {
+	return int(math.Round(StartOfDay(b).Sub(StartOfDay(a)).Hours() / 24))
+}
+
+// ParseDateInput parses a date typed by the user, relative to now. It accepts
+// YYYY-MM-DD or a duration such as 10d, 2w, 3m or 1y.
+func ParseDateInput(input string, now time.Time) (time.Time, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+	
+	if t, err := time.ParseInLocation("2006-01-02", input, time.Local); err == nil {
+		return t, nil
+	}
+	
+	unit := input[len(input)-1]
+	n, err := strconv.Atoi(strings.TrimPrefix(input[:len(input)-1], "+"))
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or a duration like 10d, 2w, 3m", input)
+	}
+	
+	today := StartOfDay(now)
+	switch unit {
+	case 'd':
+		return today.AddDate(0, 0, n), nil
+	case 'w':
+		return today.AddDate(0, 0, 7*n), nil
+	case 'm':
+		return today.AddDate(0, n, 0), nil
+	case 'y':
+		return today.AddDate(n, 0, 0), nil
+	}
+	
+	return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or a duration like 10d, 2w, 3m", input)
+}
@@ -0,0 +1,318 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+)
+
+// attentionDays is the window before the due date in which a contact needs attention
+const attentionDays = 7
+
+// BumpPolicy controls how bumps defer a contact's due date
+type BumpPolicy struct {
+	DeferDays int // Each bump pushes the due date to at least this many days after the bump
+	MaxBumps  int // Consecutive bumps (since the last contact) that defer; 0 means no limit
+}
+
+// defaultBumpPolicy applies to relationship types without their own policy
+var defaultBumpPolicy = BumpPolicy{DeferDays: 7}
+
+// bumpPolicies holds per-relationship-type overrides
+var bumpPolicies = map[RelationshipType]BumpPolicy{}
+
+// SetBumpPolicies replaces the default and per-type bump policies
+func SetBumpPolicies(def BumpPolicy, byType map[RelationshipType]BumpPolicy) {
+	defaultBumpPolicy = def
+	bumpPolicies = byType
+	if bumpPolicies == nil {
+		bumpPolicies = map[RelationshipType]BumpPolicy{}
+	}
+}
+
+// BumpPolicy returns the bump policy for the contact's relationship type
+func (c *Contact) BumpPolicy() BumpPolicy {
+	if policy, ok := bumpPolicies[c.RelationshipType]; ok {
+		return policy
+	}
+	return defaultBumpPolicy
+}
+
+// NextDue returns the date the contact is next due for contact. ok is false
+// when nothing is scheduled for the contact.
+//
+// For periodic contacts the base due date is the last contact plus the
+// frequency, or the creation date for contacts never contacted. Bumps since
+// the last contact defer it according to the bump policy. Triggers from the
+// trigger rules, a pending follow-up or a deadline date bring the due date
+// forward for any style. A snooze defers everything except deadlines to the
+// end of the snooze.
+func (c *Contact) NextDue() (due time.Time, ok bool) {
+	s := c.schedule()
+	return s.due, s.ok
+}
+
+// DueSource returns what set the next due date: "periodic", "trigger",
+// "follow-up", "deadline" or "snooze", or "" when nothing is scheduled
+func (c *Contact) DueSource() string {
+	return c.schedule().source
+}
+
+// DueTrigger returns the trigger that set the next due date, if any
+func (c *Contact) DueTrigger() (Trigger, bool) {
+	return c.triggerOn(c.schedule())
+}
+
+// triggerOn returns the trigger that set a schedule's due date, if any
+func (c *Contact) triggerOn(s dueSchedule) (Trigger, bool) {
+	if s.source != "trigger" {
+		return Trigger{}, false
+	}
+	for _, t := range c.Triggers {
+		if StartOfDay(t.Date).Equal(s.due) {
+			return t, true
+		}
+	}
+	return Trigger{}, false
+}
+
+// DueState is what the list and agenda show about when a contact is due,
+// worked out from one pass over the schedule
+type DueState struct {
+	Due       time.Time
+	OK        bool     // There is a due date
+	Source    string   // As DueSource
+	Trigger   *Trigger // The trigger that set the due date, if one did
+	Days      int      // As DaysUntilDue
+	Overdue   bool     // As IsOverdue
+	Attention bool     // As NeedsAttention
+}
+
+// DueState works out the next due date and what follows from it at once,
+// for callers that need more than one of them
+func (c *Contact) DueState() DueState {
+	s := c.schedule()
+	st := DueState{Due: s.due, OK: s.ok, Source: s.source}
+	if !s.ok {
+		return st
+	}
+	if t, ok := c.triggerOn(s); ok {
+		st.Trigger = &t
+	}
+	st.Days = DaysBetween(clock.Now(), s.due)
+	// Snoozes hold everything back except deadlines
+	if !c.IsSnoozed() || s.source == "deadline" {
+		st.Overdue = st.Days < 0
+		st.Attention = st.Days >= 0 && st.Days < attentionDays
+	}
+	return st
+}
+
+// DueExplanation describes step by step how the next due date was reached
+func (c *Contact) DueExplanation() []string {
+	return c.schedule().reasons
+}
+
+// dueSchedule is the result of working out a contact's next due date
+type dueSchedule struct {
+	due     time.Time
+	ok      bool
+	source  string
+	reasons []string
+}
+
+// consider makes date the due date if it is earlier than the current one
+func (s *dueSchedule) consider(date time.Time, source string) {
+	if !s.ok || date.Before(s.due) {
+		s.due = date
+		s.ok = true
+		s.source = source
+	}
+}
+
+// schedule computes the next due date along with the reasons behind it
+func (c *Contact) schedule() dueSchedule {
+	const dateFmt = "Jan 2, 2006"
+	var s dueSchedule
+	
+	// Archived contacts are never due
+	if c.IsArchived() {
+		s.reasons = append(s.reasons, "Archived contacts are not due")
+		return s
+	}
+	
+	c.periodicSchedule(&s)
+	
+	// Trigger rules bring the due date forward for any style
+	for _, t := range c.Triggers {
+		s.reasons = append(s.reasons, fmt.Sprintf("%s, due %s", t.Reason, StartOfDay(t.Date).Format(dateFmt)))
+		s.consider(StartOfDay(t.Date), "trigger")
+	}
+	
+	// Follow-ups are pending until a contact is logged on or after the date
+	if c.FollowUpDate != nil {
+		followUp := StartOfDay(*c.FollowUpDate)
+		if c.LastContacted != nil && !StartOfDay(*c.LastContacted).Before(followUp) {
+			s.reasons = append(s.reasons, fmt.Sprintf("Follow-up for %s done (contacted since)", followUp.Format(dateFmt)))
+		} else {
+			s.reasons = append(s.reasons, fmt.Sprintf("Follow-up scheduled for %s", followUp.Format(dateFmt)))
+			s.consider(followUp, "follow-up")
+		}
+	}
+	
+	// Nothing but deadlines is due while snoozed
+	if s.ok && c.SnoozedUntil != nil {
+		until := StartOfDay(*c.SnoozedUntil)
+		if until.After(s.due) {
+			s.due = until
+			s.source = "snooze"
+			s.reasons = append(s.reasons, fmt.Sprintf("Snoozed until %s", until.Format(dateFmt)))
+		}
+	}
+	
+	// Deadlines count until they are cleared
+	if c.DeadlineDate != nil {
+		deadline := StartOfDay(*c.DeadlineDate)
+		s.reasons = append(s.reasons, fmt.Sprintf("Deadline %s", deadline.Format(dateFmt)))
+		s.consider(deadline, "deadline")
+	}
+	
+	if !s.ok {
+		return s
+	}
+	
+	days := DaysBetween(clock.Now(), s.due)
+	switch {
+	case days < 0:
+		s.reasons = append(s.reasons, fmt.Sprintf("Overdue by %d days", -days))
+	case days == 0:
+		s.reasons = append(s.reasons, "Due today")
+	default:
+		s.reasons = append(s.reasons, fmt.Sprintf("Due in %d days", days))
+	}
+	
+	return s
+}
+
+// periodicSchedule works out the frequency-based due date, adjusted for bumps
+func (c *Contact) periodicSchedule(s *dueSchedule) {
+	const dateFmt = "Jan 2, 2006"
+	
+	// Triggered contacts come due from trigger rules instead
+	if c.ContactStyle == StyleTriggered {
+		if len(c.Triggers) == 0 {
+			s.reasons = append(s.reasons, "Triggered contact with no trigger rule due")
+		}
+		return
+	}
+	
+	// Only periodic contacts come due
+	if c.ContactStyle != StylePeriodic && c.ContactStyle != "" {
+		s.reasons = append(s.reasons, fmt.Sprintf("%s contacts are not due on a schedule", c.ContactStyle))
+		return
+	}
+	
+	freq := c.GetFrequencyDays()
+	if freq == 0 {
+		s.reasons = append(s.reasons, fmt.Sprintf("No contact frequency for %s contacts", c.typeName()))
+		return
+	}
+	
+	if c.CustomFrequencyDays > 0 {
+		s.reasons = append(s.reasons, fmt.Sprintf("Every %d days (custom frequency)", freq))
+	} else {
+		s.reasons = append(s.reasons, fmt.Sprintf("Every %d days (%s default)", freq, c.typeName()))
+	}
+	
+	var due time.Time
+	if c.LastContacted != nil {
+		due = StartOfDay(*c.LastContacted).AddDate(0, 0, freq)
+		s.reasons = append(s.reasons, fmt.Sprintf("Last contacted %s, so due %s",
+			c.LastContacted.Format(dateFmt), due.Format(dateFmt)))
+	} else if !c.Date.IsZero() {
+		due = StartOfDay(c.Date)
+		s.reasons = append(s.reasons, fmt.Sprintf("Never contacted, so due since it was created %s", due.Format(dateFmt)))
+	} else {
+		due = StartOfDay(clock.Now())
+		s.reasons = append(s.reasons, "Never contacted, so due now")
+	}
+	
+	// A bump since the last contact means "thought about it, not yet"
+	if c.LastBumpDate != nil && (c.LastContacted == nil || c.LastBumpDate.After(*c.LastContacted)) {
+		policy := c.BumpPolicy()
+		switch {
+		case policy.DeferDays <= 0:
+			s.reasons = append(s.reasons, fmt.Sprintf("Bumped %s, but bumps do not defer %s contacts",
+				c.LastBumpDate.Format(dateFmt), c.typeName()))
+		case policy.MaxBumps > 0 && c.BumpsSinceContact() > policy.MaxBumps:
+			s.reasons = append(s.reasons, fmt.Sprintf("Bumped %d times since last contact; only %d bumps defer, so reach out",
+				c.BumpsSinceContact(), policy.MaxBumps))
+		default:
+			deferred := StartOfDay(*c.LastBumpDate).AddDate(0, 0, policy.DeferDays)
+			if deferred.After(due) {
+				due = deferred
+				s.reasons = append(s.reasons, fmt.Sprintf("Bumped %s%s, deferring %d days to %s",
+					c.LastBumpDate.Format(dateFmt), c.bumpCountNote(policy), policy.DeferDays, due.Format(dateFmt)))
+			} else {
+				s.reasons = append(s.reasons, fmt.Sprintf("Bumped %s%s, which does not move the due date",
+					c.LastBumpDate.Format(dateFmt), c.bumpCountNote(policy)))
+			}
+		}
+	}
+	
+	s.consider(due, "periodic")
+}
+
+// BumpsSinceContact returns how many times the contact has been bumped since
+// it was last contacted. bump_count only counts while the last bump is after
+// the last contact, since importing, merging or editing can move the last
+// contact without resetting it. Files from before it was reset on contact
+// kept a running total, so it is capped at the bumps logged since then when
+// the log has any.
+func (c *Contact) BumpsSinceContact() int {
+	if c.LastBumpDate == nil || (c.LastContacted != nil && !c.LastBumpDate.After(*c.LastContacted)) {
+		return 0
+	}
+	logged := 0
+	for _, in := range c.Interactions {
+		if in.Type == InteractionBump && (c.LastContacted == nil || in.Date.After(*c.LastContacted)) {
+			logged++
+		}
+	}
+	if logged > 0 && logged < c.BumpCount {
+		return logged
+	}
+	return c.BumpCount
+}
+
+// bumpCountNote formats the consecutive bump count against the policy limit
+func (c *Contact) bumpCountNote(policy BumpPolicy) string {
+	if policy.MaxBumps > 0 {
+		return fmt.Sprintf(" (%d of %d)", c.BumpsSinceContact(), policy.MaxBumps)
+	}
+	return ""
+}
+
+// typeName returns the relationship type for messages
+func (c *Contact) typeName() string {
+	if c.RelationshipType == "" {
+		return "untyped"
+	}
+	return string(c.RelationshipType)
+}
+
+// IsArchived returns true if the contact is archived, either with the
+// archived flag or the archived state
+func (c *Contact) IsArchived() bool {
+	return c.Archived || c.State == string(StateArchived)
+}
+
+// IsSnoozed returns true if the contact is snoozed and the snooze date has
+// not yet arrived
+func (c *Contact) IsSnoozed() bool {
+	if c.SnoozedUntil == nil {
+		return false
+	}
+	return StartOfDay(clock.Now()).Before(StartOfDay(*c.SnoozedUntil))
+}
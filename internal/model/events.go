@@ -0,0 +1,161 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+)
+
+// Event is a yearly date such as a birthday or anniversary
+type Event struct {
+	Name  string // "birthday" or the key under dates
+	Month time.Month
+	Day   int
+	Year  int // 0 when the year is not known
+}
+
+// Occurrence is the next time an event comes around
+type Occurrence struct {
+	Event
+	Date  time.Time
+	Years int // Age or years since the event; only meaningful when Year is set
+}
+
+// ParseEvent parses an event date in MM-DD or YYYY-MM-DD form
+func ParseEvent(name, value string) (Event, error) {
+	value = strings.TrimSpace(value)
+	e := Event{Name: name}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		e.Year, e.Month, e.Day = t.Date()
+		return e, nil
+	}
+
+	// Parse against a leap year so 02-29 is accepted
+	if t, err := time.Parse("2006-01-02", "2000-"+value); err == nil {
+		_, e.Month, e.Day = t.Date()
+		return e, nil
+	}
+
+	return Event{}, fmt.Errorf("invalid %s date %q: use MM-DD or YYYY-MM-DD", name, value)
+}
+
+// Label returns the event name for display, e.g. "work anniversary" becomes
+// "Work anniversary"
+func (e Event) Label() string {
+	name := strings.ReplaceAll(e.Name, "_", " ")
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// String formats the event as it is written in the frontmatter
+func (e Event) String() string {
+	if e.Year > 0 {
+		return fmt.Sprintf("%04d-%02d-%02d", e.Year, e.Month, e.Day)
+	}
+	return fmt.Sprintf("%02d-%02d", e.Month, e.Day)
+}
+
+// In returns the event's date in the given year. February 29 falls on
+// February 28 in other years.
+func (e Event) In(year int) time.Time {
+	day := e.Day
+	if e.Month == time.February && day == 29 && !isLeapYear(year) {
+		day = 28
+	}
+	return time.Date(year, e.Month, day, 0, 0, 0, 0, time.Local)
+}
+
+// Next returns the next occurrence of the event on or after from
+func (e Event) Next(from time.Time) Occurrence {
+	from = StartOfDay(from)
+	date := e.In(from.Year())
+	if date.Before(from) {
+		date = e.In(from.Year() + 1)
+	}
+
+	o := Occurrence{Event: e, Date: date}
+	if e.Year > 0 {
+		o.Years = date.Year() - e.Year
+	}
+	return o
+}
+
+// YearsNote describes the age or years at the occurrence, e.g. " (turns 40)"
+// or " (15 years)", or "" when the year is not known
+func (o Occurrence) YearsNote() string {
+	if o.Year == 0 {
+		return ""
+	}
+	if o.Name == "birthday" {
+		return fmt.Sprintf(" (turns %d)", o.Years)
+	}
+	return fmt.Sprintf(" (%d years)", o.Years)
+}
+
+// YearsNow returns the age or years since the event as of today. ok is false
+// when the year is not known.
+func (e Event) YearsNow() (years int, ok bool) {
+	if e.Year == 0 {
+		return 0, false
+	}
+	today := StartOfDay(clock.Now())
+	years = today.Year() - e.Year
+	if today.Before(e.In(today.Year())) {
+		years--
+	}
+	return years, true
+}
+
+// Events returns the contact's birthday and named dates, skipping any that
+// do not parse. Named dates are sorted by name.
+func (c *Contact) Events() []Event {
+	var events []Event
+	if c.Birthday != "" {
+		if e, err := ParseEvent("birthday", c.Birthday); err == nil {
+			events = append(events, e)
+		}
+	}
+
+	names := make([]string, 0, len(c.Dates))
+	for name := range c.Dates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if e, err := ParseEvent(name, c.Dates[name]); err == nil {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// UpcomingEvents returns the contact's events falling within the next days
+// days, including today, soonest first. Archived contacts have none.
+func (c *Contact) UpcomingEvents(days int) []Occurrence {
+	if c.IsArchived() {
+		return nil
+	}
+	today := StartOfDay(clock.Now())
+	var upcoming []Occurrence
+	for _, e := range c.Events() {
+		o := e.Next(today)
+		if DaysBetween(today, o.Date) <= days {
+			upcoming = append(upcoming, o)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date.Before(upcoming[j].Date)
+	})
+	return upcoming
+}
+
+// isLeapYear reports whether year has a February 29
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Event
+		wantErr bool
+	}{
+		{"1985-03-04", Event{Name: "birthday", Month: time.March, Day: 4, Year: 1985}, false},
+		{"03-04", Event{Name: "birthday", Month: time.March, Day: 4}, false},
+		{"02-29", Event{Name: "birthday", Month: time.February, Day: 29}, false},
+		{" 12-31 ", Event{Name: "birthday", Month: time.December, Day: 31}, false},
+		{"13-01", Event{}, true},
+		{"1985-02-30", Event{}, true},
+		{"March 4", Event{}, true},
+		{"", Event{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseEvent("birthday", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEvent(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEvent(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventNext(t *testing.T) {
+	from := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		event     Event
+		wantDate  time.Time
+		wantYears int
+	}{
+		{"later this year", Event{Month: time.June, Day: 1, Year: 1990}, time.Date(2025, time.June, 1, 0, 0, 0, 0, time.Local), 35},
+		{"today counts", Event{Month: time.March, Day: 15, Year: 2000}, time.Date(2025, time.March, 15, 0, 0, 0, 0, time.Local), 25},
+		{"already passed rolls over", Event{Month: time.March, Day: 14, Year: 2000}, time.Date(2026, time.March, 14, 0, 0, 0, 0, time.Local), 26},
+		{"leap day in a common year", Event{Month: time.February, Day: 29}, time.Date(2026, time.February, 28, 0, 0, 0, 0, time.Local), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.event.Next(from)
+			if !got.Date.Equal(tt.wantDate) {
+				t.Errorf("Next() date = %s, want %s", got.Date.Format("2006-01-02"), tt.wantDate.Format("2006-01-02"))
+			}
+			if got.Years != tt.wantYears {
+				t.Errorf("Next() years = %d, want %d", got.Years, tt.wantYears)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+)
+
+// minGapsForSuggestion is how many gaps between contacts are needed before a
+// frequency is suggested
+const minGapsForSuggestion = 3
+
+// suggestionTolerance is how far the actual gap may stray from the configured
+// frequency, as a ratio, before a new frequency is suggested
+const suggestionTolerance = 1.5
+
+// suggestionYears is how far back the interaction history is read
+const suggestionYears = 2
+
+// FrequencySuggestion proposes a contact frequency from the actual history
+type FrequencySuggestion struct {
+	Current   int // Configured frequency in days; 0 when none
+	Suggested int // Suggested frequency in days
+	MedianGap int // Median days between contacts
+	Gaps      int // Number of gaps the median is taken over
+}
+
+// MedianGap returns the median number of days between real contacts over the
+// last two years, and how many gaps it was taken from. Contacts on the same
+// day count once.
+func (c *Contact) MedianGap() (days int, gaps int) {
+	since := clock.Now().AddDate(-suggestionYears, 0, 0)
+	var dates []int // Days since since, oldest first
+	for i := len(c.Interactions) - 1; i >= 0; i-- {
+		in := c.Interactions[i]
+		if !in.IsContact() || in.Date.Before(since) {
+			continue
+		}
+		day := DaysBetween(since, in.Date)
+		if len(dates) == 0 || dates[len(dates)-1] != day {
+			dates = append(dates, day)
+		}
+	}
+	if len(dates) < 2 {
+		return 0, 0
+	}
+
+	var diffs []int
+	for i := 1; i < len(dates); i++ {
+		diffs = append(diffs, dates[i]-dates[i-1])
+	}
+	sort.Ints(diffs)
+	mid := len(diffs) / 2
+	if len(diffs)%2 == 1 {
+		return diffs[mid], len(diffs)
+	}
+	return (diffs[mid-1] + diffs[mid]) / 2, len(diffs)
+}
+
+// SuggestFrequency suggests a new frequency when the median gap between
+// contacts differs a lot from the configured frequency. Ambient, triggered and
+// archived contacts, and suggestions already rejected, are skipped.
+func (c *Contact) SuggestFrequency() (FrequencySuggestion, bool) {
+	if c.ContactStyle != StylePeriodic && c.ContactStyle != "" || c.IsArchived() {
+		return FrequencySuggestion{}, false
+	}
+
+	median, gaps := c.MedianGap()
+	if gaps < minGapsForSuggestion || median <= 0 {
+		return FrequencySuggestion{}, false
+	}
+
+	s := FrequencySuggestion{
+		Current:   c.GetFrequencyDays(),
+		Suggested: roundFrequency(median),
+		MedianGap: median,
+		Gaps:      gaps,
+	}
+	if s.Current > 0 {
+		ratio := float64(median) / float64(s.Current)
+		if ratio <= suggestionTolerance && ratio >= 1/suggestionTolerance {
+			return FrequencySuggestion{}, false
+		}
+	}
+	if s.Suggested == s.Current || s.Suggested == c.RejectedFrequencyDays {
+		return FrequencySuggestion{}, false
+	}
+	return s, true
+}
+
+// roundFrequency rounds a gap to a friendly frequency: whole weeks from two
+// weeks up
+func roundFrequency(days int) int {
+	if days < 14 {
+		return days
+	}
+	return int(math.Round(float64(days)/7)) * 7
+}
@@ -0,0 +1,39 @@
+package model
+
+import "testing"
+
+func TestSuggestFrequency(t *testing.T) {
+	useFixedClock(t)
+
+	tests := []struct {
+		name     string
+		contact  Contact
+		content  string
+		want     int
+		wantSome bool
+	}{
+		{"matches configured", Contact{RelationshipType: RelationshipClose}, history(InteractionCall, 6, 35, 5), 0, false},
+		{"much rarer than configured", Contact{RelationshipType: RelationshipClose}, history(InteractionCall, 5, 88, 5), 91, true},
+		{"much more often than configured", Contact{RelationshipType: RelationshipNetwork}, history(InteractionEmail, 8, 10, 1), 10, true},
+		{"no frequency configured", Contact{RelationshipType: RelationshipSocial}, history(InteractionMeeting, 4, 45, 5), 42, true},
+		{"too few gaps", Contact{RelationshipType: RelationshipClose}, history(InteractionCall, 3, 120, 5), 0, false},
+		{"bumps don't count", Contact{RelationshipType: RelationshipClose}, history(InteractionBump, 6, 120, 5), 0, false},
+		{"ambient skipped", Contact{RelationshipType: RelationshipClose, ContactStyle: StyleAmbient}, history(InteractionCall, 5, 88, 5), 0, false},
+		{"rejected skipped", Contact{RelationshipType: RelationshipClose, RejectedFrequencyDays: 91}, history(InteractionCall, 5, 88, 5), 0, false},
+		{"older than two years ignored", Contact{RelationshipType: RelationshipClose}, history(InteractionCall, 6, 120, 800), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.contact
+			c.Interactions = ParseInteractions(tt.content)
+			got, ok := c.SuggestFrequency()
+			if ok != tt.wantSome {
+				t.Fatalf("SuggestFrequency() = %+v, %v, want ok %v", got, ok, tt.wantSome)
+			}
+			if ok && got.Suggested != tt.want {
+				t.Errorf("SuggestFrequency().Suggested = %d, want %d", got.Suggested, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+)
+
+// Health score weights: the parts add up to 100
+const (
+	RecencyPoints = 50
+	CadencePoints = 25
+	MixPoints     = 25
+)
+
+// healthBaseFrequency stands in for the frequency of contacts without one
+const healthBaseFrequency = 90
+
+// healthTrendDays is how far back the trend compares
+const healthTrendDays = 30
+
+// interactionWeights rates each interaction type; a meeting counts for more
+// than an email, and a bump barely counts
+var interactionWeights = map[InteractionType]float64{
+	InteractionMeeting: 1.0,
+	"video":            0.9,
+	"phone":            0.8,
+	InteractionCall:    0.8,
+	"mail":             0.7,
+	InteractionEmail:   0.5,
+	InteractionSocial:  0.5,
+	InteractionText:    0.4,
+	InteractionNote:    0.2,
+	InteractionBump:    0.1,
+}
+
+// Health is a relationship health score from 0 to 100 and its parts
+type Health struct {
+	Score   int
+	Recency float64 // 0-1: time since the last contact relative to the frequency
+	Cadence float64 // 0-1: how regular the gaps between contacts are
+	Mix     float64 // 0-1: how substantial recent interactions were
+}
+
+// Health returns the contact's health score today
+func (c *Contact) Health() Health {
+	return c.HealthAt(clock.Now())
+}
+
+// HealthTrend returns the change in the health score over the last 30 days
+func (c *Contact) HealthTrend() int {
+	now := clock.Now()
+	return c.HealthAt(now).Score - c.HealthAt(now.AddDate(0, 0, -healthTrendDays)).Score
+}
+
+// HealthAt returns the health score as it stood at the given time, counting
+// only interactions logged by then
+func (c *Contact) HealthAt(at time.Time) Health {
+	freq := c.GetFrequencyDays()
+	if freq == 0 {
+		freq = healthBaseFrequency
+	}
+
+	// Interactions up to at, oldest first
+	var history []Interaction
+	for i := len(c.Interactions) - 1; i >= 0; i-- {
+		if !c.Interactions[i].Date.After(at) {
+			history = append(history, c.Interactions[i])
+		}
+	}
+
+	var h Health
+
+	// Recency: full marks up to half the frequency, none at twice it
+	if last, ok := c.lastContactAt(history, at); ok {
+		ratio := float64(DaysBetween(last, at)) / float64(freq)
+		h.Recency = clamp01(1 - (ratio-0.5)/1.5)
+	}
+
+	// Cadence: how much the gaps between contacts in the last year vary
+	var contactDates []time.Time
+	yearAgo := at.AddDate(-1, 0, 0)
+	for _, i := range history {
+		if i.IsContact() && i.Date.After(yearAgo) {
+			contactDates = append(contactDates, i.Date)
+		}
+	}
+	switch {
+	case len(contactDates) >= 3:
+		var gaps []float64
+		for i := 1; i < len(contactDates); i++ {
+			gaps = append(gaps, contactDates[i].Sub(contactDates[i-1]).Hours()/24)
+		}
+		mean, stddev := meanStddev(gaps)
+		if mean > 0 {
+			h.Cadence = clamp01(1 - stddev/mean)
+		}
+	case len(contactDates) == 2:
+		h.Cadence = 0.5 // One gap says little either way
+	}
+
+	// Mix: average weight of interactions within two frequencies
+	window := at.AddDate(0, 0, -2*freq)
+	var total float64
+	var count int
+	for _, i := range history {
+		if i.Date.After(window) {
+			total += interactionWeight(i.Type)
+			count++
+		}
+	}
+	if count > 0 {
+		h.Mix = total / float64(count)
+	}
+
+	h.Score = int(math.Round(RecencyPoints*h.Recency + CadencePoints*h.Cadence + MixPoints*h.Mix))
+	return h
+}
+
+// lastContactAt returns the last real contact up to at, from the interaction
+// history or the last_contacted field, whichever is later
+func (c *Contact) lastContactAt(history []Interaction, at time.Time) (time.Time, bool) {
+	var last time.Time
+	found := false
+	for _, i := range history {
+		if i.IsContact() && (!found || i.Date.After(last)) {
+			last, found = i.Date, true
+		}
+	}
+	if c.LastContacted != nil && !c.LastContacted.After(at) && (!found || c.LastContacted.After(last)) {
+		last, found = *c.LastContacted, true
+	}
+	return last, found
+}
+
+// interactionWeight returns the weight of an interaction type; unknown types
+// count as much as an email
+func interactionWeight(t InteractionType) float64 {
+	if w, ok := interactionWeights[t]; ok {
+		return w
+	}
+	return interactionWeights[InteractionEmail]
+}
+
+// meanStddev returns the mean and population standard deviation
+func meanStddev(values []float64) (mean, stddev float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		stddev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(stddev / float64(len(values)))
+}
+
+// clamp01 limits v to the range 0-1
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
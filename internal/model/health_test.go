@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseInteractions(t *testing.T) {
+	content := `
+## 2025-03-10 09:15 - email
+
+Sent the draft.
+
+## Notes
+
+Met at a conference.
+
+### 2025-01-05 14:30 - Meeting
+
+Coffee.
+More coffee.
+
+## 2025-02-01 - bump
+
+## 2024-12-24 - call
+
+Written by older versions, without a time.
+
+## Not a date - email
+`
+	got := ParseInteractions(content)
+
+	want := []struct {
+		date    string
+		typ     InteractionType
+		summary string
+	}{
+		{"2025-03-10 09:15", "email", "Sent the draft."},
+		{"2025-02-01 00:00", "bump", ""},
+		{"2025-01-05 14:30", "meeting", "Coffee.\nMore coffee."},
+		{"2024-12-24 00:00", "call", "Written by older versions, without a time."},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseInteractions() returned %d interactions, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if d := got[i].Date.Format("2006-01-02 15:04"); d != w.date {
+			t.Errorf("interaction %d date = %s, want %s", i, d, w.date)
+		}
+		if got[i].Type != w.typ {
+			t.Errorf("interaction %d type = %s, want %s", i, got[i].Type, w.typ)
+		}
+		if got[i].Summary != w.summary {
+			t.Errorf("interaction %d summary = %q, want %q", i, got[i].Summary, w.summary)
+		}
+	}
+}
+
+// history builds an interaction log with one interaction of the given type
+// every gap days, the newest one newest days before today
+func history(typ InteractionType, count, gap, newest int) string {
+	var b strings.Builder
+	for i := 0; i < count; i++ {
+		date := today.AddDate(0, 0, -(newest + i*gap))
+		fmt.Fprintf(&b, "## %s - %s\n\n", date.Format("2006-01-02"), typ)
+	}
+	return b.String()
+}
+
+func TestHealth(t *testing.T) {
+	useFixedClock(t)
+
+	tests := []struct {
+		name     string
+		relType  RelationshipType
+		content  string
+		min, max int
+	}{
+		{"regular recent meetings", RelationshipClose, history(InteractionMeeting, 6, 30, 5), 95, 100},
+		{"regular recent emails", RelationshipClose, history(InteractionEmail, 6, 30, 5), 80, 90},
+		{"only bumps", RelationshipClose, history(InteractionBump, 6, 30, 5), 0, 5},
+		{"lapsed", RelationshipClose, history(InteractionMeeting, 3, 30, 90), 10, 30},
+		{"no history", RelationshipClose, "", 0, 0},
+		{"no frequency uses 90 days", RelationshipSocial, history(InteractionCall, 3, 90, 10), 90, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Contact{RelationshipType: tt.relType, Interactions: ParseInteractions(tt.content)}
+			got := c.Health()
+			if got.Score < tt.min || got.Score > tt.max {
+				t.Errorf("Health() = %+v, want score in %d-%d", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestHealthTrend(t *testing.T) {
+	useFixedClock(t)
+
+	// Regular monthly contact that stopped two months ago is falling
+	lapsing := Contact{RelationshipType: RelationshipClose, Interactions: ParseInteractions(history(InteractionCall, 6, 30, 60))}
+	if trend := lapsing.HealthTrend(); trend >= 0 {
+		t.Errorf("lapsing HealthTrend() = %d, want negative", trend)
+	}
+
+	// Contact picked up again after a long gap is rising
+	content := history(InteractionMeeting, 2, 7, 2) + history(InteractionMeeting, 1, 1, 200)
+	renewed := Contact{RelationshipType: RelationshipClose, Interactions: ParseInteractions(content)}
+	if trend := renewed.HealthTrend(); trend <= 0 {
+		t.Errorf("renewed HealthTrend() = %d, want positive", trend)
+	}
+}
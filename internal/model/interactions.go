@@ -0,0 +1,72 @@
+package model
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// interactionHeading matches the headings interactions are logged under:
+// "## 2025-07-10 14:30 - email" as written by the app, "## 2025-07-10 -
+// email" as written by older versions, or "### 2025-07-10 14:30 - Meeting"
+// as in the spec
+var interactionHeading = regexp.MustCompile(`^#{2,3}\s+(\d{4}-\d{2}-\d{2})(?:\s+(\d{1,2}:\d{2}))?\s+-\s+(.+?)\s*$`)
+
+// ParseInteractions reads the interaction log from a contact's markdown body,
+// newest first. The summary is the text up to the next heading.
+func ParseInteractions(content string) []Interaction {
+	var interactions []Interaction
+	var current *Interaction
+	var summary []string
+
+	flush := func() {
+		if current != nil {
+			current.Summary = strings.TrimSpace(strings.Join(summary, "\n"))
+			interactions = append(interactions, *current)
+		}
+		current, summary = nil, nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			flush()
+			if in, ok := parseInteractionHeading(line); ok {
+				current = &in
+			}
+			continue
+		}
+		if current != nil {
+			summary = append(summary, line)
+		}
+	}
+	flush()
+
+	sort.SliceStable(interactions, func(i, j int) bool {
+		return interactions[i].Date.After(interactions[j].Date)
+	})
+	return interactions
+}
+
+// parseInteractionHeading reads an interaction heading line
+func parseInteractionHeading(line string) (Interaction, bool) {
+	match := interactionHeading.FindStringSubmatch(line)
+	if match == nil {
+		return Interaction{}, false
+	}
+	layout, value := "2006-01-02", match[1]
+	if match[2] != "" {
+		layout, value = "2006-01-02 15:04", match[1]+" "+match[2]
+	}
+	date, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return Interaction{}, false
+	}
+	return Interaction{Date: date, Type: InteractionType(strings.ToLower(match[3]))}, true
+}
+
+// IsContact reports whether the interaction was real contact, as opposed to
+// a bump or a note to self
+func (i Interaction) IsContact() bool {
+	return i.Type != InteractionBump && i.Type != InteractionNote
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeLabel returns a label in its stored form: lowercase with a
+// leading @ and dashes for spaces, e.g. "@mentor". Empty stays empty.
+func NormalizeLabel(label string) string {
+	label = strings.TrimPrefix(strings.TrimSpace(label), "@")
+	label = strings.ToLower(strings.Join(strings.Fields(label), "-"))
+	if label == "" {
+		return ""
+	}
+	return "@" + label
+}
+
+// ParseLabels splits a list of labels separated by spaces or commas,
+// normalizing each and dropping duplicates
+func ParseLabels(value string) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		label := NormalizeLabel(part)
+		if label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// HasLabel reports whether the contact goes by the label, ignoring case and
+// the leading @
+func (c *Contact) HasLabel(label string) bool {
+	label = NormalizeLabel(label)
+	return label != "" && NormalizeLabel(c.Label) == label
+}
+
+// FindByLabel returns the index of the contact with the label, or -1
+func FindByLabel(contacts []Contact, label string) int {
+	for i := range contacts {
+		if contacts[i].HasLabel(label) {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckLabel returns an error when another contact already uses the
+// contact's label. Contacts are told apart by file path.
+func CheckLabel(contacts []Contact, c Contact) error {
+	if NormalizeLabel(c.Label) == "" {
+		return nil
+	}
+	for i := range contacts {
+		if contacts[i].FilePath != c.FilePath && contacts[i].HasLabel(c.Label) {
+			return fmt.Errorf("label %s is already used by %s", NormalizeLabel(c.Label), contacts[i].Title)
+		}
+	}
+	return nil
+}
+
+// Related is a contact linked to another through related_contact_labels
+type Related struct {
+	Label    string   // The label the link is made through
+	Contact  *Contact // Nil when no contact has the label
+	Incoming bool     // True when the other contact lists this one
+}
+
+// RelatedContacts resolves the contact's related labels against contacts,
+// followed by the contacts that list this one as related. Contacts linked
+// both ways are listed once.
+func (c *Contact) RelatedContacts(contacts []Contact) []Related {
+	var related []Related
+	seen := make(map[string]bool) // File paths already listed
+	for _, label := range c.RelatedContactLabels {
+		r := Related{Label: NormalizeLabel(label)}
+		if i := FindByLabel(contacts, label); i >= 0 {
+			if contacts[i].FilePath == c.FilePath || seen[contacts[i].FilePath] {
+				continue
+			}
+			r.Contact = &contacts[i]
+			seen[contacts[i].FilePath] = true
+		}
+		related = append(related, r)
+	}
+
+	if NormalizeLabel(c.Label) == "" {
+		return related
+	}
+	for i := range contacts {
+		other := &contacts[i]
+		if other.FilePath == c.FilePath || seen[other.FilePath] {
+			continue
+		}
+		for _, label := range other.RelatedContactLabels {
+			if c.HasLabel(label) {
+				related = append(related, Related{Label: NormalizeLabel(other.Label), Contact: other, Incoming: true})
+				seen[other.FilePath] = true
+				break
+			}
+		}
+	}
+	return related
+}
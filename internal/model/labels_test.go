@@ -0,0 +1,63 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"@mentor", []string{"@mentor"}},
+		{"Mentor, @Alex  @mentor", []string{"@mentor", "@alex"}},
+		{" @ , ", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseLabels(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLabels(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCheckLabel(t *testing.T) {
+	contacts := []Contact{
+		{Title: "Ann", FilePath: "ann.md", Label: "@mentor"},
+		{Title: "Bob", FilePath: "bob.md"},
+	}
+	if err := CheckLabel(contacts, Contact{FilePath: "bob.md", Label: "Mentor"}); err == nil {
+		t.Error("CheckLabel() allowed a label another contact uses")
+	}
+	if err := CheckLabel(contacts, Contact{FilePath: "ann.md", Label: "@mentor"}); err != nil {
+		t.Errorf("CheckLabel() rejected a contact keeping its own label: %v", err)
+	}
+	if err := CheckLabel(contacts, Contact{FilePath: "new.md"}); err != nil {
+		t.Errorf("CheckLabel() rejected an empty label: %v", err)
+	}
+}
+
+func TestRelatedContacts(t *testing.T) {
+	contacts := []Contact{
+		{Title: "Ann", FilePath: "ann.md", Label: "@ann", RelatedContactLabels: []string{"@bob", "@gone", "@ann"}},
+		{Title: "Bob", FilePath: "bob.md", Label: "@bob", RelatedContactLabels: []string{"@ann"}},
+		{Title: "Cat", FilePath: "cat.md", RelatedContactLabels: []string{"@ann"}},
+	}
+
+	var got []string
+	for _, r := range contacts[0].RelatedContacts(contacts) {
+		name := "?"
+		if r.Contact != nil {
+			name = r.Contact.Title
+		}
+		if r.Incoming {
+			name += "<"
+		}
+		got = append(got, r.Label+":"+name)
+	}
+	want := []string{"@bob:Bob", "@gone:?", ":Cat<"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RelatedContacts() = %v, want %v", got, want)
+	}
+}
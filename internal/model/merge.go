@@ -0,0 +1,106 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// contentBlock is a run of body lines: an interaction entry with its note,
+// or anything else
+type contentBlock struct {
+	text        string
+	date        time.Time
+	interaction bool
+}
+
+// splitContent cuts a body into blocks at every heading
+func splitContent(content string) []contentBlock {
+	var blocks []contentBlock
+	var current *contentBlock
+	var lines []string
+	flush := func() {
+		if current != nil {
+			current.text = strings.Join(lines, "\n")
+			blocks = append(blocks, *current)
+		}
+		current, lines = nil, nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			flush()
+			in, ok := parseInteractionHeading(line)
+			current = &contentBlock{date: in.Date, interaction: ok}
+		} else if current == nil {
+			current = &contentBlock{}
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return blocks
+}
+
+// MergeContent merges the body of a duplicate contact into keep's body. The
+// interaction entries of both are combined newest first where keep's first
+// entry was (or at the top), with identical entries written once. The rest
+// of the duplicate's body, and any extra lines such as values it loses,
+// follow under a "## Merged from" heading.
+func MergeContent(keep, lose, loseTitle string, extra []string) string {
+	keepBlocks, loseBlocks := splitContent(keep), splitContent(lose)
+
+	var entries []contentBlock
+	seen := make(map[string]bool)
+	for _, b := range append(append([]contentBlock(nil), keepBlocks...), loseBlocks...) {
+		key := strings.TrimSpace(b.text)
+		if !b.interaction || seen[key] {
+			continue
+		}
+		seen[key] = true
+		entries = append(entries, b)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].date.After(entries[j].date)
+	})
+	var history strings.Builder
+	for _, e := range entries {
+		history.WriteString(strings.TrimRight(e.text, "\n") + "\n\n")
+	}
+
+	var b strings.Builder
+	placed := false
+	for _, block := range keepBlocks {
+		if block.interaction {
+			if !placed {
+				b.WriteString(history.String())
+				placed = true
+			}
+			continue
+		}
+		if strings.TrimSpace(block.text) == "" {
+			continue
+		}
+		b.WriteString(strings.TrimRight(block.text, "\n") + "\n\n")
+	}
+	out := b.String()
+	if !placed {
+		out = history.String() + out
+	}
+
+	var rest []string
+	for _, block := range loseBlocks {
+		text := strings.TrimSpace(block.text)
+		if block.interaction || text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "#") && !strings.Contains(text, "\n") {
+			continue // A heading with nothing under it, such as "## Recent Interactions"
+		}
+		rest = append(rest, text)
+	}
+	rest = append(rest, extra...)
+	if len(rest) > 0 {
+		out += "## Merged from " + loseTitle + "\n\n" + strings.Join(rest, "\n\n") + "\n"
+	}
+	return out
+}
@@ -0,0 +1,282 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Relationship is a typed link to another contact. The type says what this
+// contact is to the other: {label: "@alice", type: manager} means this
+// contact is Alice's manager, and Alice's file gets {type: reports-to} back.
+type Relationship struct {
+	Label string `yaml:"label"`
+	Type  string `yaml:"type"`
+}
+
+// relationshipKind is a relationship type with its inverse and how it reads
+type relationshipKind struct {
+	name    string
+	inverse string
+	phrase  string // Reads before the other contact's name
+}
+
+// relationshipKinds are the known relationship types
+var relationshipKinds = []relationshipKind{
+	{"spouse", "spouse", "Spouse of"},
+	{"partner", "partner", "Partner of"},
+	{"parent", "child", "Parent of"},
+	{"child", "parent", "Child of"},
+	{"sibling", "sibling", "Sibling of"},
+	{"friend", "friend", "Friend of"},
+	{"colleague", "colleague", "Colleague of"},
+	{"manager", "reports-to", "Manager of"},
+	{"reports-to", "manager", "Reports to"},
+	{"mentor", "mentee", "Mentor of"},
+	{"mentee", "mentor", "Mentee of"},
+	{"introduced-by", "introduced", "Introduced by"},
+	{"introduced", "introduced-by", "Introduced"},
+}
+
+// RelationshipTypes returns the names of the known relationship types
+func RelationshipTypes() []string {
+	names := make([]string, len(relationshipKinds))
+	for i, k := range relationshipKinds {
+		names[i] = k.name
+	}
+	return names
+}
+
+// lookupRelationship returns the kind with the given type name
+func lookupRelationship(name string) (relationshipKind, bool) {
+	name = NormalizeRelationshipType(name)
+	for _, k := range relationshipKinds {
+		if k.name == name {
+			return k, true
+		}
+	}
+	return relationshipKind{}, false
+}
+
+// NormalizeRelationshipType lowercases a type and joins words with dashes,
+// so "Reports to" becomes "reports-to"
+func NormalizeRelationshipType(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(name, "_", " ")), "-"))
+}
+
+// Inverse returns the relationship as seen from the other contact's side
+func (r Relationship) Inverse(label string) Relationship {
+	inverse := r.Type
+	if k, ok := lookupRelationship(r.Type); ok {
+		inverse = k.inverse
+	}
+	return Relationship{Label: NormalizeLabel(label), Type: inverse}
+}
+
+// Phrase describes the relationship, e.g. "Manager of"
+func (r Relationship) Phrase() string {
+	if k, ok := lookupRelationship(r.Type); ok {
+		return k.phrase
+	}
+	return r.Type
+}
+
+// same reports whether two relationships link to the same label by the same type
+func (r Relationship) same(o Relationship) bool {
+	return NormalizeLabel(r.Label) == NormalizeLabel(o.Label) &&
+		NormalizeRelationshipType(r.Type) == NormalizeRelationshipType(o.Type)
+}
+
+// ParseRelationships parses relationships written as "type @label" pairs
+// separated by commas, e.g. "manager @alice, spouse @bob"
+func ParseRelationships(value string) ([]Relationship, error) {
+	var rels []Relationship
+	for _, part := range strings.Split(value, ",") {
+		fields := strings.Fields(strings.ReplaceAll(part, ":", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 || !strings.HasPrefix(fields[len(fields)-1], "@") {
+			return nil, fmt.Errorf("invalid relationship %q: use type @label, e.g. manager @alice", strings.TrimSpace(part))
+		}
+		r := Relationship{
+			Label: NormalizeLabel(fields[len(fields)-1]),
+			Type:  NormalizeRelationshipType(strings.Join(fields[:len(fields)-1], " ")),
+		}
+		if _, ok := lookupRelationship(r.Type); !ok {
+			return nil, fmt.Errorf("unknown relationship type %q: use one of %s", r.Type, strings.Join(RelationshipTypes(), ", "))
+		}
+		rels = addRelationship(rels, r)
+	}
+	return rels, nil
+}
+
+// FormatRelationships formats relationships the way ParseRelationships reads them
+func FormatRelationships(rels []Relationship) string {
+	parts := make([]string, len(rels))
+	for i, r := range rels {
+		parts[i] = r.Type + " " + r.Label
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Person is a relationship resolved to the contact it points at
+type Person struct {
+	Relationship
+	Contact *Contact // Nil when no contact has the label
+}
+
+// People resolves the contact's relationships against contacts
+func (c *Contact) People(contacts []Contact) []Person {
+	people := make([]Person, 0, len(c.Relationships))
+	for _, r := range c.Relationships {
+		p := Person{Relationship: r}
+		if i := FindByLabel(contacts, r.Label); i >= 0 && contacts[i].FilePath != c.FilePath {
+			p.Contact = &contacts[i]
+		}
+		people = append(people, p)
+	}
+	return people
+}
+
+// SyncRelationships keeps the other side of every relationship in step after
+// a contact changes from before to after. It adds inverses for after's
+// relationships, removes inverses of relationships dropped since before, and
+// follows a label rename. It returns the other contacts it changed, which the
+// caller saves; contacts itself is left alone.
+func SyncRelationships(contacts []Contact, before, after Contact) []Contact {
+	oldLabel, newLabel := NormalizeLabel(before.Label), NormalizeLabel(after.Label)
+	changed := make(map[int]*Contact)
+	var order []int
+	edit := func(i int) *Contact {
+		if c, ok := changed[i]; ok {
+			return c
+		}
+		c := contacts[i]
+		c.Relationships = append([]Relationship(nil), c.Relationships...)
+		c.RelatedContactLabels = append([]string(nil), c.RelatedContactLabels...)
+		changed[i] = &c
+		order = append(order, i)
+		return &c
+	}
+	other := func(label string) int {
+		i := FindByLabel(contacts, label)
+		if i < 0 || contacts[i].FilePath == after.FilePath {
+			return -1
+		}
+		return i
+	}
+
+	// A renamed label is renamed wherever others point at it
+	if oldLabel != "" && oldLabel != newLabel {
+		for i := range contacts {
+			if contacts[i].FilePath == after.FilePath || !pointsAt(contacts[i], oldLabel) {
+				continue
+			}
+			c := edit(i)
+			var rels []Relationship
+			for _, r := range c.Relationships {
+				if NormalizeLabel(r.Label) == oldLabel {
+					if newLabel == "" {
+						continue
+					}
+					r.Label = newLabel
+				}
+				rels = addRelationship(rels, r)
+			}
+			c.Relationships = rels
+			var labels []string
+			for _, l := range c.RelatedContactLabels {
+				if NormalizeLabel(l) == oldLabel {
+					l = newLabel
+				}
+				if l != "" {
+					labels = append(labels, l)
+				}
+			}
+			c.RelatedContactLabels = labels
+		}
+	}
+
+	// Relationships dropped since before lose their inverse
+	for _, r := range before.Relationships {
+		if hasRelationship(after.Relationships, r) {
+			continue
+		}
+		i := other(r.Label)
+		if i < 0 {
+			continue
+		}
+		current := contacts[i].Relationships
+		if c, ok := changed[i]; ok {
+			current = c.Relationships
+		}
+		var kept []Relationship
+		for _, o := range current {
+			if o.same(r.Inverse(oldLabel)) || o.same(r.Inverse(newLabel)) {
+				continue
+			}
+			kept = append(kept, o)
+		}
+		if len(kept) != len(current) {
+			edit(i).Relationships = kept
+		}
+	}
+
+	// Every relationship has its inverse on the other side
+	if newLabel != "" {
+		for _, r := range after.Relationships {
+			i := other(r.Label)
+			if i < 0 {
+				continue
+			}
+			inverse := r.Inverse(newLabel)
+			current := contacts[i].Relationships
+			if c, ok := changed[i]; ok {
+				current = c.Relationships
+			}
+			if !hasRelationship(current, inverse) {
+				c := edit(i)
+				c.Relationships = addRelationship(c.Relationships, inverse)
+			}
+		}
+	}
+
+	result := make([]Contact, 0, len(order))
+	for _, i := range order {
+		result = append(result, *changed[i])
+	}
+	return result
+}
+
+// pointsAt reports whether the contact links to the label in any way
+func pointsAt(c Contact, label string) bool {
+	for _, r := range c.Relationships {
+		if NormalizeLabel(r.Label) == label {
+			return true
+		}
+	}
+	for _, l := range c.RelatedContactLabels {
+		if NormalizeLabel(l) == label {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRelationship reports whether rels already holds r
+func hasRelationship(rels []Relationship, r Relationship) bool {
+	for _, o := range rels {
+		if o.same(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// addRelationship appends r unless rels already holds it
+func addRelationship(rels []Relationship, r Relationship) []Relationship {
+	if hasRelationship(rels, r) {
+		return rels
+	}
+	return append(rels, r)
+}
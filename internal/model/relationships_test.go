@@ -0,0 +1,63 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRelationships(t *testing.T) {
+	got, err := ParseRelationships("Manager @Alice, reports to: @bob, manager @alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Relationship{{"@alice", "manager"}, {"@bob", "reports-to"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRelationships() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"manager", "@alice", "boss @alice"} {
+		if _, err := ParseRelationships(bad); err == nil {
+			t.Errorf("ParseRelationships(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestSyncRelationships(t *testing.T) {
+	contacts := []Contact{
+		{Title: "Ann", FilePath: "ann.md", Label: "@ann", Relationships: []Relationship{{"@cat", "spouse"}}},
+		{Title: "Bob", FilePath: "bob.md", Label: "@bob"},
+		{Title: "Cat", FilePath: "cat.md", Label: "@cat", Relationships: []Relationship{{"@ann", "spouse"}}, RelatedContactLabels: []string{"@ann"}},
+	}
+
+	// Ann becomes Bob's manager and stops being Cat's spouse
+	before := contacts[0]
+	after := before
+	after.Relationships = []Relationship{{"@bob", "manager"}}
+	got := SyncRelationships(contacts, before, after)
+	want := []Contact{
+		{Title: "Cat", FilePath: "cat.md", Label: "@cat", RelatedContactLabels: []string{"@ann"}},
+		{Title: "Bob", FilePath: "bob.md", Label: "@bob", Relationships: []Relationship{{"@ann", "reports-to"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SyncRelationships() = %+v, want %+v", got, want)
+	}
+	if len(contacts[2].Relationships) != 1 {
+		t.Error("SyncRelationships() changed the contacts passed in")
+	}
+
+	// Renaming Ann's label follows her everywhere
+	after = before
+	after.Label = "@annie"
+	got = SyncRelationships(contacts, before, after)
+	want = []Contact{
+		{Title: "Cat", FilePath: "cat.md", Label: "@cat", Relationships: []Relationship{{"@annie", "spouse"}}, RelatedContactLabels: []string{"@annie"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SyncRelationships() after rename = %+v, want %+v", got, want)
+	}
+
+	// Nothing to do when both sides already agree
+	if got := SyncRelationships(contacts, before, before); len(got) != 0 {
+		t.Errorf("SyncRelationships() with no change = %+v, want none", got)
+	}
+}
@@ -7,8 +7,12 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/conflict"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"golang.org/x/text/unicode/norm"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,6 +23,11 @@ func ParseContactFile(path string) (model.Contact, error) {
 		return model.Contact{}, fmt.Errorf("error reading file: %w", err)
 	}
 
+	// A file left in conflict by git would parse as one side or not at all
+	if conflict.HasMarkers(content) {
+		return model.Contact{}, conflict.ErrMarkers
+	}
+
 	// Split frontmatter and content
 	parts := bytes.SplitN(content, []byte("---\n"), 3)
 	if len(parts) < 3 {
@@ -39,6 +48,7 @@ func ParseContactFile(path string) (model.Contact, error) {
 	// Set runtime fields
 	contact.FilePath = path
 	contact.Content = string(parts[2])
+	contact.Interactions = model.ParseInteractions(contact.Content)
 
 	// Parse filename to extract identifier if not set
 	if contact.Identifier == "" {
@@ -51,6 +61,59 @@ func ParseContactFile(path string) (model.Contact, error) {
 	return contact, nil
 }
 
+// LoadContacts parses every contact file under dir. Files that fail to
+// parse, including those in conflict, are skipped so one bad file doesn't
+// hide the rest.
+func LoadContacts(dir string) ([]model.Contact, error) {
+	contacts := []model.Contact{}
+	
+	// Check the contacts directory exists and is a directory
+	if info, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("contacts directory '%s' does not exist. Please create it or check your configuration", dir)
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot access contacts directory '%s': %v", dir, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("contacts path '%s' exists but is not a directory", dir)
+	}
+	
+	// Walk the contacts directory
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("error reading file '%s': %v", path, err)
+		}
+		
+		// Skip hidden directories such as .trash
+		if info.IsDir() && path != dir && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+		
+		// Skip directories and non-markdown files
+		if info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		
+		// Check if it's a contact file
+		if !strings.Contains(filepath.Base(path), "__contact.md") {
+			return nil
+		}
+		
+		// Parse the contact file
+		contact, err := ParseContactFile(path)
+		if err != nil {
+			// Log error but continue loading other files
+			return nil
+		}
+		
+		contacts = append(contacts, contact)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return contacts, nil
+}
+
 // SaveContactFile saves a contact to a Denote-format file
 func SaveContactFile(contact model.Contact) error {
 	// Generate filename if needed
@@ -59,7 +122,7 @@ func SaveContactFile(contact model.Contact) error {
 	}
 
 	// Ensure updated_at is set
-	contact.UpdatedAt = time.Now()
+	contact.UpdatedAt = clock.Now()
 
 	// Marshal frontmatter
 	frontmatter, err := yaml.Marshal(contact)
@@ -75,7 +138,58 @@ func SaveContactFile(contact model.Contact) error {
 	content.WriteString(contact.Content)
 
 	// Write file
-	return os.WriteFile(contact.FilePath, content.Bytes(), 0644)
+	return WriteFile(contact.FilePath, content.Bytes())
+}
+
+// CreateContact saves a new contact in contactsDir, named as NewContactFile
+// names it. The saved contact is returned.
+func CreateContact(contactsDir string, contact model.Contact) (model.Contact, error) {
+	contact, err := NewContactFile(contactsDir, contact)
+	if err != nil {
+		return contact, err
+	}
+	if err := SaveContactFile(contact); err != nil {
+		return contact, fmt.Errorf("failed to save contact '%s': %v", contact.Title, err)
+	}
+	return contact, nil
+}
+
+// NewContactFile readies a new contact to be saved in contactsDir without
+// writing anything. Its identifier and Denote file name come from its date,
+// now if unset; the time is moved on a second at a time while another file
+// has the identifier, so contacts created together each get their own.
+func NewContactFile(contactsDir string, contact model.Contact) (model.Contact, error) {
+	name := strings.TrimSpace(contact.Title)
+	if name == "" {
+		return contact, fmt.Errorf("name is required")
+	}
+	
+	// Check the contacts directory exists before trying to save
+	if _, err := os.Stat(contactsDir); os.IsNotExist(err) {
+		return contact, fmt.Errorf("cannot create contact: directory '%s' does not exist. Please create it first", contactsDir)
+	} else if err != nil {
+		return contact, fmt.Errorf("cannot access contacts directory '%s': %v", contactsDir, err)
+	}
+	
+	if contact.Date.IsZero() {
+		contact.Date = clock.Now()
+	}
+	for {
+		contact.Identifier = contact.Date.Format("20060102T150405")
+		if used, _ := filepath.Glob(filepath.Join(contactsDir, contact.Identifier+"--*")); len(used) == 0 {
+			break
+		}
+		contact.Date = contact.Date.Add(time.Second)
+	}
+	contact.UpdatedAt = clock.Now()
+	if !containsTag(contact.Tags, "contact") {
+		contact.Tags = append([]string{"contact"}, contact.Tags...)
+	}
+	
+	// Generate Denote filename using the same timestamp as the identifier
+	filename := fmt.Sprintf("%s--%s__contact.md", contact.Identifier, Slug(name))
+	contact.FilePath = filepath.Join(contactsDir, filename)
+	return contact, nil
 }
 
 // GenerateFilename generates a Denote-compliant filename for a contact
@@ -83,28 +197,41 @@ func GenerateFilename(contact model.Contact) string {
 	// Use creation date or current date
 	date := contact.Date
 	if date.IsZero() {
-		date = time.Now()
+		date = clock.Now()
 	}
 
 	// Format: YYYYMMDD--kebab-case-name__contact.md
 	identifier := date.Format("20060102")
-	name := strings.ToLower(contact.Title)
-	name = strings.ReplaceAll(name, " ", "-")
-	name = sanitizeName(name)
-
-	return fmt.Sprintf("%s--%s__contact.md", identifier, name)
+	return fmt.Sprintf("%s--%s__contact.md", identifier, Slug(contact.Title))
 }
 
-// sanitizeName removes special characters and ensures valid filename
-func sanitizeName(name string) string {
-	// Remove special characters, keep only alphanumeric and hyphens
-	var result strings.Builder
-	for _, ch := range name {
-		if (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' {
-			result.WriteRune(ch)
+// Slug turns a name into the title part of a Denote file name, using only
+// a-z, 0-9 and single hyphens. Accents are dropped ("José" becomes "jose"),
+// apostrophes and dots removed, and anything else such as spaces, "/" or
+// "&" separates words. A name with nothing left becomes "contact".
+func Slug(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, ch := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9'):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(ch)
+		case ch == '\'' || ch == '’' || ch == '.' || unicode.Is(unicode.Mn, ch):
+			// Dropped without separating words
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch):
+			// Letters with no ASCII form are left out
+		default:
+			hyphen = true
 		}
 	}
-	return result.String()
+	if b.Len() == 0 {
+		return "contact"
+	}
+	return b.String()
 }
 
 // containsTag checks if a tag exists in the tags slice
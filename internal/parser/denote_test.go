@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Sarah Chen", "sarah-chen"},
+		{"AT&T / Support", "at-t-support"},
+		{"Re: Project: Q3", "re-project-q3"},
+		{"Dr. Mary O'Brien", "dr-mary-obrien"},
+		{"José Núñez", "jose-nunez"},
+		{"Zoë  Ångström-Berg", "zoe-angstrom-berg"},
+		{"  --Trailing--  ", "trailing"},
+		{"张伟", "contact"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.name); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCreateContact(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+
+	for _, name := range []string{"AT&T / Support", "Re: Q3", "Łukasz Żak"} {
+		c, err := CreateContact(dir, model.Contact{Title: name, Date: date})
+		if err != nil {
+			t.Fatalf("CreateContact(%q) = %v", name, err)
+		}
+		if filepath.Dir(c.FilePath) != dir {
+			t.Errorf("CreateContact(%q) wrote %s outside the contacts directory", name, c.FilePath)
+		}
+		if _, err := os.Stat(c.FilePath); err != nil {
+			t.Errorf("CreateContact(%q): %v", name, err)
+		}
+	}
+
+	// Contacts created at the same time each get their own identifier
+	files, _ := filepath.Glob(filepath.Join(dir, "*__contact.md"))
+	want := []string{
+		"20250610T090000--at-t-support__contact.md",
+		"20250610T090001--re-q3__contact.md",
+		"20250610T090002--ukasz-zak__contact.md",
+	}
+	if len(files) != len(want) {
+		t.Fatalf("files = %v, want %v", files, want)
+	}
+	for i, f := range files {
+		if filepath.Base(f) != want[i] {
+			t.Errorf("file %d = %s, want %s", i, filepath.Base(f), want[i])
+		}
+	}
+}
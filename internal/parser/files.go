@@ -0,0 +1,216 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// beforeWrite is called with the files a change is about to write, move or
+// remove, so the change can be recorded for undo
+var beforeWrite = func(paths ...string) {}
+
+// SetBeforeWrite sets the function called before contact and task files
+// change
+func SetBeforeWrite(fn func(paths ...string)) {
+	if fn == nil {
+		fn = func(paths ...string) {}
+	}
+	beforeWrite = fn
+}
+
+// ErrReadOnly is returned by every change while SetReadOnly is on
+var ErrReadOnly = errors.New("changes are turned off while previewing another date with --as-of")
+
+// readOnly turns off writing contact and task files
+var readOnly bool
+
+// SetReadOnly turns writing contact and task files off or back on
+func SetReadOnly(on bool) {
+	readOnly = on
+}
+
+// Writable returns ErrReadOnly when changes are turned off. Changes made
+// other than through this package, such as undo, check it first.
+func Writable() error {
+	if readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// WriteFile writes a file that belongs with the contacts, such as a task
+func WriteFile(path string, data []byte) error {
+	if err := Writable(); err != nil {
+		return err
+	}
+	beforeWrite(path)
+	return os.WriteFile(path, data, 0644)
+}
+
+// RemoveFile removes a file that belongs with the contacts, such as a sync
+// conflict copy that has been merged
+func RemoveFile(path string) error {
+	if err := Writable(); err != nil {
+		return err
+	}
+	beforeWrite(path)
+	return os.Remove(path)
+}
+
+// TrashDir is where removed contacts go, inside the contacts directory.
+// LoadContacts skips it like any hidden directory.
+func TrashDir(contactsDir string) string {
+	return filepath.Join(contactsDir, ".trash")
+}
+
+// MoveToTrash moves a contact file into the trash and returns its new path.
+// A file already in the trash with the same name is not overwritten.
+func MoveToTrash(contactsDir, path string) (string, error) {
+	if err := Writable(); err != nil {
+		return "", err
+	}
+	trash := TrashDir(contactsDir)
+	if err := os.MkdirAll(trash, 0755); err != nil {
+		return "", fmt.Errorf("failed to create trash directory: %v", err)
+	}
+
+	base := filepath.Base(path)
+	dest := filepath.Join(trash, base)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dest); os.IsNotExist(err) {
+			break
+		}
+		dest = filepath.Join(trash, fmt.Sprintf("%s.%d%s", strings.TrimSuffix(base, ".md"), n, ".md"))
+	}
+	beforeWrite(path, dest)
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("failed to move '%s' to trash: %v", base, err)
+	}
+	return dest, nil
+}
+
+// trashSuffix is the ".N" MoveToTrash adds to keep a name free in the trash
+var trashSuffix = regexp.MustCompile(`\.\d+\.md$`)
+
+// LoadTrash parses the contact files in the trash, most recently trashed
+// first. Their FilePath is where they are in the trash.
+func LoadTrash(contactsDir string) ([]model.Contact, error) {
+	trash := TrashDir(contactsDir)
+	entries, err := os.ReadDir(trash)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read trash directory '%s': %v", trash, err)
+	}
+
+	type trashed struct {
+		contact model.Contact
+		modTime time.Time
+	}
+	var found []trashed
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		contact, err := ParseContactFile(filepath.Join(trash, e.Name()))
+		if err != nil {
+			continue
+		}
+		var modTime time.Time
+		if info, err := e.Info(); err == nil {
+			modTime = info.ModTime()
+		}
+		found = append(found, trashed{contact, modTime})
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].modTime.After(found[j].modTime)
+	})
+
+	contacts := make([]model.Contact, len(found))
+	for i, t := range found {
+		contacts[i] = t.contact
+	}
+	return contacts, nil
+}
+
+// RestoreFromTrash moves a trashed contact file back into the contacts
+// directory under its original name and returns its new path. A contact
+// already there with that name is not overwritten.
+func RestoreFromTrash(contactsDir, path string) (string, error) {
+	if err := Writable(); err != nil {
+		return "", err
+	}
+	name := trashSuffix.ReplaceAllString(filepath.Base(path), ".md")
+	dest := filepath.Join(contactsDir, name)
+	if _, err := os.Stat(dest); err == nil {
+		return "", fmt.Errorf("can't restore '%s': a contact file with that name already exists", name)
+	}
+	beforeWrite(path, dest)
+	if err := os.Rename(path, dest); err != nil {
+		return "", fmt.Errorf("failed to restore '%s' from trash: %v", name, err)
+	}
+	return dest, nil
+}
+
+// TasksDir returns the directory tasks are written to. Tasks always go to
+// ~/notes, where denote-tasks looks for them.
+func TasksDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "notes")
+}
+
+// RewriteTaskContactID points every task in dir whose contact_id is from at
+// to instead, and returns how many tasks changed. Only the frontmatter is
+// touched. A missing directory has no tasks.
+func RewriteTaskContactID(dir, from, to string) (int, error) {
+	if from == "" || from == to {
+		return 0, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("cannot read tasks directory '%s': %v", dir, err)
+	}
+
+	line := regexp.MustCompile(`(?m)^contact_id:[ \t]*"?` + regexp.QuoteMeta(from) + `"?[ \t]*$`)
+	changed := 0
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") || !strings.Contains(e.Name(), "__task") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return changed, fmt.Errorf("failed to read task '%s': %v", e.Name(), err)
+		}
+
+		// Frontmatter is between the first two --- lines
+		content := string(data)
+		if !strings.HasPrefix(content, "---\n") {
+			continue
+		}
+		end := strings.Index(content[4:], "\n---")
+		if end < 0 {
+			continue
+		}
+		front := content[:4+end]
+		if !line.MatchString(front) {
+			continue
+		}
+		content = line.ReplaceAllString(front, "contact_id: "+to) + content[4+end:]
+		if err := WriteFile(path, []byte(content)); err != nil {
+			return changed, fmt.Errorf("failed to update task '%s': %v", e.Name(), err)
+		}
+		changed++
+	}
+	return changed, nil
+}
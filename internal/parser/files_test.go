@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// touch sets the modification time of path
+func touch(t *testing.T, path string, when time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTrashRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Date(2025, 6, 10, 9, 0, 0, 0, time.Local)
+	first, err := CreateContact(dir, model.Contact{Title: "Ann Lee", Date: date})
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Base(first.FilePath)
+
+	// Trash the contact, then a second file that took the same name
+	trashed, err := MoveToTrash(dir, first.FilePath)
+	if err != nil {
+		t.Fatalf("MoveToTrash() = %v", err)
+	}
+	if trashed != filepath.Join(TrashDir(dir), name) {
+		t.Errorf("MoveToTrash() = %s, want the same name in the trash", trashed)
+	}
+	touch(t, trashed, date)
+	second := first
+	second.Title = "Ann Lee (again)"
+	if err := SaveContactFile(second); err != nil {
+		t.Fatal(err)
+	}
+	trashedAgain, err := MoveToTrash(dir, second.FilePath)
+	if err != nil {
+		t.Fatalf("second MoveToTrash() = %v", err)
+	}
+	if want := strings.TrimSuffix(name, ".md") + ".2.md"; filepath.Base(trashedAgain) != want {
+		t.Errorf("second MoveToTrash() = %s, want %s", filepath.Base(trashedAgain), want)
+	}
+	touch(t, trashedAgain, date.Add(time.Hour))
+	if _, err := os.Stat(first.FilePath); !os.IsNotExist(err) {
+		t.Errorf("%s is still in the contacts directory", name)
+	}
+
+	// The trash lists the most recently trashed first; LoadContacts skips it
+	trash, err := LoadTrash(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 2 || trash[0].Title != "Ann Lee (again)" || trash[1].Title != "Ann Lee" {
+		t.Fatalf("LoadTrash() = %+v, want the second then the first", trash)
+	}
+	if contacts, err := LoadContacts(dir); err != nil || len(contacts) != 0 {
+		t.Errorf("LoadContacts() = %d contacts, %v, want none", len(contacts), err)
+	}
+
+	// Each restores under the original name, and never over another file
+	restored, err := RestoreFromTrash(dir, trashedAgain)
+	if err != nil {
+		t.Fatalf("RestoreFromTrash() = %v", err)
+	}
+	if restored != first.FilePath {
+		t.Errorf("RestoreFromTrash() = %s, want %s", restored, first.FilePath)
+	}
+	if _, err := RestoreFromTrash(dir, trashed); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("RestoreFromTrash() over a taken name = %v, want an error", err)
+	}
+	if _, err := os.Stat(trashed); err != nil {
+		t.Errorf("the clashing contact left the trash: %v", err)
+	}
+	c, err := ParseContactFile(first.FilePath)
+	if err != nil || c.Title != "Ann Lee (again)" {
+		t.Errorf("restored contact = %q, %v, want Ann Lee (again)", c.Title, err)
+	}
+
+	if err := os.Remove(first.FilePath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreFromTrash(dir, trashed); err != nil {
+		t.Errorf("RestoreFromTrash() once the name is free = %v", err)
+	}
+	if trash, _ := LoadTrash(dir); len(trash) != 0 {
+		t.Errorf("LoadTrash() = %d contacts after restoring both, want none", len(trash))
+	}
+}
+
+func TestTrashReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	c, err := CreateContact(dir, model.Contact{Title: "Ann Lee", Date: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetReadOnly(true)
+	t.Cleanup(func() { SetReadOnly(false) })
+
+	if _, err := MoveToTrash(dir, c.FilePath); err != ErrReadOnly {
+		t.Errorf("MoveToTrash() = %v, want %v", err, ErrReadOnly)
+	}
+	if _, err := os.Stat(c.FilePath); err != nil {
+		t.Errorf("contact moved while read-only: %v", err)
+	}
+}
+
+func TestRewriteTaskContactID(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    string // Content afterwards; empty means unchanged
+	}{
+		{
+			file:    "20250101T000000--call-ann__task.md",
+			content: "---\ntitle: Call Ann\ncontact_id: 20240101T000000\n---\nBody\n",
+			want:    "---\ntitle: Call Ann\ncontact_id: 20250101T120000\n---\nBody\n",
+		},
+		{
+			file:    "20250102T000000--email-ann__task.md",
+			content: "---\ntitle: Email Ann\ncontact_id: \"20240101T000000\"\n---\n",
+			want:    "---\ntitle: Email Ann\ncontact_id: 20250101T120000\n---\n",
+		},
+		{
+			file:    "20250103T000000--call-bob__task.md",
+			content: "---\ntitle: Call Bob\ncontact_id: 20240101T000001\n---\n",
+		},
+		{
+			file:    "20250104T000000--note__task.md",
+			content: "---\ntitle: Note\n---\ncontact_id: 20240101T000000\n",
+		},
+		{
+			file:    "20250105T000000--ann-lee__contact.md",
+			content: "---\ntitle: Ann\ncontact_id: 20240101T000000\n---\n",
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changed, err := RewriteTaskContactID(dir, "20240101T000000", "20250101T120000")
+	if err != nil || changed != 2 {
+		t.Errorf("RewriteTaskContactID() = %d, %v, want 2 tasks", changed, err)
+	}
+	for _, tt := range tests {
+		want := tt.want
+		if want == "" {
+			want = tt.content
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, tt.file))
+		if string(data) != want {
+			t.Errorf("%s =\n%s\nwant\n%s", tt.file, data, want)
+		}
+	}
+
+	if n, err := RewriteTaskContactID(filepath.Join(dir, "missing"), "a", "b"); n != 0 || err != nil {
+		t.Errorf("RewriteTaskContactID(missing dir) = %d, %v, want 0, nil", n, err)
+	}
+}
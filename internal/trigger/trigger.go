@@ -0,0 +1,278 @@
+// Package trigger turns trigger rules from the config into due dates for
+// contacts. Rules cover yearly dates such as birthdays, tag-based campaigns,
+// and surfacing contacts when another contact is logged.
+package trigger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// Rule kinds
+const (
+	KindDate     = "date"     // Birthdays and named dates of triggered contacts
+	KindCampaign = "campaign" // Every contact with a tag, from a start date
+	KindLogged   = "logged"   // Contacts to surface when another is logged
+)
+
+// defaultLeadDays is how many days before a date a date rule is due
+const defaultLeadDays = 7
+
+// defaultWithinDays is how long a logged rule keeps its contacts surfaced
+const defaultWithinDays = 14
+
+// Rule is a compiled trigger rule
+type Rule struct {
+	Kind   string
+	Reason string // Shown in place of the generated reason when set
+
+	// Date rules
+	Dates    []string // Event names to trigger on; empty means all
+	LeadDays int
+
+	// Campaign rules
+	Tag   string
+	From  time.Time
+	Until time.Time // Zero when the campaign has no end
+
+	// Logged rules
+	When       string   // Selector for the contact whose interaction fires the rule
+	Surface    []string // Selectors for the contacts to surface
+	WithinDays int
+}
+
+// rules holds the active rules
+var rules = []Rule{{Kind: KindDate, LeadDays: defaultLeadDays}}
+
+// SetRules replaces the active rules
+func SetRules(r []Rule) {
+	rules = r
+}
+
+// Compile checks the trigger rules in the config. When no date rule is
+// configured, a date rule with defaultLeadDays (or event_lead_days) is added
+// so triggered contacts still come due before their birthdays.
+func Compile(cfg *config.Config) ([]Rule, error) {
+	var compiled []Rule
+	hasDateRule := false
+
+	for i, t := range cfg.Triggers {
+		r := Rule{Kind: strings.ToLower(strings.TrimSpace(t.Kind)), Reason: t.Reason}
+		where := fmt.Sprintf("trigger %d", i+1)
+		if t.Name != "" {
+			where = fmt.Sprintf("trigger %q", t.Name)
+		}
+
+		switch r.Kind {
+		case KindDate:
+			hasDateRule = true
+			r.Dates = t.Dates
+			r.LeadDays = defaultLeadDays
+			if t.LeadDays != nil {
+				r.LeadDays = *t.LeadDays
+			} else if cfg.EventLeadDays != nil {
+				r.LeadDays = *cfg.EventLeadDays
+			}
+			if r.LeadDays < 0 {
+				return nil, fmt.Errorf("%s: lead_days cannot be negative", where)
+			}
+
+		case KindCampaign:
+			r.Tag = strings.TrimPrefix(strings.TrimSpace(t.Tag), "#")
+			if r.Tag == "" {
+				return nil, fmt.Errorf("%s: campaign needs a tag", where)
+			}
+			from, err := parseRuleDate(t.From)
+			if err != nil || from.IsZero() {
+				return nil, fmt.Errorf("%s: campaign needs from = \"YYYY-MM-DD\"", where)
+			}
+			r.From = from
+			if r.Until, err = parseRuleDate(t.Until); err != nil {
+				return nil, fmt.Errorf("%s: until must be \"YYYY-MM-DD\"", where)
+			}
+
+		case KindLogged:
+			r.When = strings.TrimSpace(t.When)
+			r.Surface = t.Surface
+			if r.When == "" || len(r.Surface) == 0 {
+				return nil, fmt.Errorf("%s: logged rule needs when and surface", where)
+			}
+			r.WithinDays = t.WithinDays
+			if r.WithinDays <= 0 {
+				r.WithinDays = defaultWithinDays
+			}
+
+		default:
+			return nil, fmt.Errorf("%s: unknown kind %q (use date, campaign or logged)", where, t.Kind)
+		}
+
+		compiled = append(compiled, r)
+	}
+
+	if !hasDateRule {
+		lead := defaultLeadDays
+		if cfg.EventLeadDays != nil && *cfg.EventLeadDays >= 0 {
+			lead = *cfg.EventLeadDays
+		}
+		compiled = append([]Rule{{Kind: KindDate, LeadDays: lead}}, compiled...)
+	}
+
+	return compiled, nil
+}
+
+// parseRuleDate parses an optional YYYY-MM-DD date
+func parseRuleDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+// Apply evaluates the active rules and sets the Triggers of each contact
+func Apply(contacts []model.Contact) {
+	for i := range contacts {
+		contacts[i].Triggers = nil
+	}
+
+	today := model.StartOfDay(clock.Now())
+	for _, r := range rules {
+		switch r.Kind {
+		case KindDate:
+			r.applyDate(contacts)
+		case KindCampaign:
+			r.applyCampaign(contacts, today)
+		case KindLogged:
+			r.applyLogged(contacts, today)
+		}
+	}
+}
+
+// applyDate makes triggered contacts due LeadDays before their next event.
+// A contact logged within the lead time covers the event, moving the trigger
+// on to the following year.
+func (r Rule) applyDate(contacts []model.Contact) {
+	today := model.StartOfDay(clock.Now())
+	for i := range contacts {
+		c := &contacts[i]
+		if c.ContactStyle != model.StyleTriggered {
+			continue
+		}
+
+		var best model.Trigger
+		found := false
+		for _, e := range c.Events() {
+			if len(r.Dates) > 0 && !containsFold(r.Dates, e.Name) {
+				continue
+			}
+			o := e.Next(today)
+			due := o.Date.AddDate(0, 0, -r.LeadDays)
+			if contactedSince(c, due) {
+				o = e.Next(o.Date.AddDate(0, 0, 1))
+				due = o.Date.AddDate(0, 0, -r.LeadDays)
+			}
+			if !found || due.Before(best.Date) {
+				best = model.Trigger{Date: due, Reason: r.reason(dateReason(o))}
+				found = true
+			}
+		}
+		if found {
+			c.Triggers = append(c.Triggers, best)
+		}
+	}
+}
+
+// applyCampaign makes every contact with the tag due from the start date
+// until contacted, or until the campaign ends
+func (r Rule) applyCampaign(contacts []model.Contact, today time.Time) {
+	if !r.Until.IsZero() && today.After(r.Until) {
+		return
+	}
+	for i := range contacts {
+		c := &contacts[i]
+		if !containsFold(c.Tags, r.Tag) || contactedSince(c, r.From) {
+			continue
+		}
+		c.Triggers = append(c.Triggers, model.Trigger{
+			Date:   r.From,
+			Reason: r.reason("Campaign #" + r.Tag),
+		})
+	}
+}
+
+// applyLogged surfaces contacts after the When contact is logged, until they
+// are contacted themselves or WithinDays pass
+func (r Rule) applyLogged(contacts []model.Contact, today time.Time) {
+	for _, x := range contacts {
+		if !matches(x, r.When) || x.LastContacted == nil {
+			continue
+		}
+		logged := model.StartOfDay(*x.LastContacted)
+		if model.DaysBetween(logged, today) > r.WithinDays {
+			continue
+		}
+
+		for i := range contacts {
+			c := &contacts[i]
+			if c.FilePath == x.FilePath || contactedSince(c, logged) {
+				continue
+			}
+			for _, selector := range r.Surface {
+				if matches(*c, selector) {
+					c.Triggers = append(c.Triggers, model.Trigger{
+						Date:   logged,
+						Reason: r.reason(fmt.Sprintf("%s was contacted %s", x.Title, logged.Format("Jan 2"))),
+					})
+					break
+				}
+			}
+		}
+	}
+}
+
+// reason returns the rule's reason, or the generated one when it has none
+func (r Rule) reason(generated string) string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+	return generated
+}
+
+// dateReason describes an upcoming event, e.g. "Birthday on Mar 20 (turns 40)"
+func dateReason(o model.Occurrence) string {
+	return fmt.Sprintf("%s on %s%s", o.Label(), o.Date.Format("Jan 2"), o.YearsNote())
+}
+
+// contactedSince reports whether the contact was logged on or after date
+func contactedSince(c *model.Contact, date time.Time) bool {
+	return c.LastContacted != nil && !model.StartOfDay(*c.LastContacted).Before(date)
+}
+
+// matches reports whether a contact is picked out by a selector: @label,
+// #tag, or the contact's name
+func matches(c model.Contact, selector string) bool {
+	selector = strings.TrimSpace(selector)
+	switch {
+	case strings.HasPrefix(selector, "@"):
+		return c.Label != "" && strings.EqualFold(strings.TrimPrefix(c.Label, "@"), selector[1:])
+	case strings.HasPrefix(selector, "#"):
+		return containsFold(c.Tags, selector[1:])
+	default:
+		return strings.EqualFold(c.Title, selector)
+	}
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// today is the fixed "now" for all tests
+var today = time.Date(2025, time.March, 15, 12, 0, 0, 0, time.Local)
+
+// day returns local midnight on the given day of March 2025
+func day(d int) time.Time {
+	return time.Date(2025, time.March, d, 0, 0, 0, 0, time.Local)
+}
+
+// ptr returns a pointer to t
+func ptr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestApply(t *testing.T) {
+	previous := clock.Set(clock.Fixed(today))
+	t.Cleanup(func() {
+		clock.Set(previous)
+		SetRules([]Rule{{Kind: KindDate, LeadDays: defaultLeadDays}})
+	})
+
+	tests := []struct {
+		name     string
+		triggers []config.Trigger
+		contacts []model.Contact
+		want     map[string]time.Time // Due date by title; missing means not due
+	}{
+		{
+			name: "birthday a week ahead by default",
+			contacts: []model.Contact{
+				{Title: "Ann", ContactStyle: model.StyleTriggered, Birthday: "03-20"},
+				{Title: "Bob", ContactStyle: model.StylePeriodic, Birthday: "03-20"},
+			},
+			want: map[string]time.Time{"Ann": day(13)},
+		},
+		{
+			name: "earliest of several dates",
+			contacts: []model.Contact{
+				{Title: "Ann", ContactStyle: model.StyleTriggered, Birthday: "08-01", Dates: map[string]string{"anniversary": "2010-04-01"}},
+			},
+			want: map[string]time.Time{"Ann": day(25)},
+		},
+		{
+			name: "contact within the lead time covers this year",
+			contacts: []model.Contact{
+				{Title: "Ann", ContactStyle: model.StyleTriggered, Birthday: "03-20", LastContacted: ptr(day(14))},
+			},
+			want: map[string]time.Time{"Ann": time.Date(2026, time.March, 13, 0, 0, 0, 0, time.Local)},
+		},
+		{
+			name:     "date rule limited to named dates with its own lead",
+			triggers: []config.Trigger{{Kind: "date", Dates: []string{"anniversary"}, LeadDays: intPtr(2)}},
+			contacts: []model.Contact{
+				{Title: "Ann", ContactStyle: model.StyleTriggered, Birthday: "03-16", Dates: map[string]string{"anniversary": "03-25"}},
+			},
+			want: map[string]time.Time{"Ann": day(23)},
+		},
+		{
+			name:     "campaign surfaces tagged contacts until contacted",
+			triggers: []config.Trigger{{Kind: "campaign", Tag: "#cards", From: "2025-03-10"}},
+			contacts: []model.Contact{
+				{Title: "Ann", Tags: []string{"contact", "cards"}},
+				{Title: "Bob", Tags: []string{"contact", "cards"}, LastContacted: ptr(day(11))},
+				{Title: "Cat", Tags: []string{"contact"}},
+			},
+			want: map[string]time.Time{"Ann": day(10)},
+		},
+		{
+			name:     "ended campaign does nothing",
+			triggers: []config.Trigger{{Kind: "campaign", Tag: "cards", From: "2025-03-01", Until: "2025-03-14"}},
+			contacts: []model.Contact{
+				{Title: "Ann", Tags: []string{"contact", "cards"}},
+			},
+		},
+		{
+			name:     "logging one contact surfaces another",
+			triggers: []config.Trigger{{Kind: "logged", When: "@ann", Surface: []string{"@bob", "#team"}}},
+			contacts: []model.Contact{
+				{Title: "Ann", FilePath: "a", Label: "@ann", LastContacted: ptr(day(12))},
+				{Title: "Bob", FilePath: "b", Label: "@bob"},
+				{Title: "Cat", FilePath: "c", Tags: []string{"contact", "team"}, LastContacted: ptr(day(13))},
+				{Title: "Dan", FilePath: "d", Tags: []string{"contact", "team"}},
+			},
+			want: map[string]time.Time{"Bob": day(12), "Dan": day(12)},
+		},
+		{
+			name:     "logged rule expires",
+			triggers: []config.Trigger{{Kind: "logged", When: "Ann", Surface: []string{"Bob"}, WithinDays: 3}},
+			contacts: []model.Contact{
+				{Title: "Ann", FilePath: "a", LastContacted: ptr(day(10))},
+				{Title: "Bob", FilePath: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := Compile(&config.Config{Triggers: tt.triggers})
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			SetRules(rules)
+			Apply(tt.contacts)
+
+			for _, c := range tt.contacts {
+				want, wantDue := tt.want[c.Title]
+				due, ok := c.DueTrigger()
+				if ok != wantDue {
+					t.Fatalf("%s: triggered = %v, want %v", c.Title, ok, wantDue)
+				}
+				if ok && !due.Date.Equal(want) {
+					t.Errorf("%s: due %s, want %s", c.Title, due.Date.Format("2006-01-02"), want.Format("2006-01-02"))
+				}
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger config.Trigger
+	}{
+		{"unknown kind", config.Trigger{Kind: "weekly"}},
+		{"campaign without tag", config.Trigger{Kind: "campaign", From: "2025-03-01"}},
+		{"campaign without start", config.Trigger{Kind: "campaign", Tag: "cards"}},
+		{"campaign with bad end", config.Trigger{Kind: "campaign", Tag: "cards", From: "2025-03-01", Until: "soon"}},
+		{"logged without surface", config.Trigger{Kind: "logged", When: "@ann"}},
+		{"negative lead", config.Trigger{Kind: "date", LeadDays: intPtr(-1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(&config.Config{Triggers: []config.Trigger{tt.trigger}}); err == nil {
+				t.Errorf("Compile() succeeded, want error")
+			}
+		})
+	}
+}
+
+// intPtr returns a pointer to n
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+func TestAgendaGroups(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.Local)
+	previous := clock.Set(clock.Fixed(now))
+	t.Cleanup(func() { clock.Set(previous) })
+
+	// due returns a periodic contact due the given number of days from today
+	due := func(title string, days int) model.Contact {
+		last := now.AddDate(0, 0, days-30)
+		return model.Contact{Title: title, ContactStyle: model.StylePeriodic, CustomFrequencyDays: 30, LastContacted: &last}
+	}
+	m := Model{contacts: []model.Contact{
+		due("In a week", 7),
+		due("Tomorrow", 1),
+		due("In eight days", 8),
+		due("Today", 0),
+		due("Yesterday", -1),
+		due("Last month", -30),
+		{Title: "Ambient", ContactStyle: model.StyleAmbient},
+		{Title: "Birthday", RelationshipType: model.RelationshipSocial, Birthday: "03-25"},
+	}}
+
+	want := []struct {
+		title    string
+		contacts string
+	}{
+		{"Overdue", "Last month, Yesterday"},
+		{"Today", "Today"},
+		{"This week", "Tomorrow, In a week"},
+		{"Later", "In eight days"},
+		{"Maybe reach out", "Ambient"},
+		{"Upcoming dates (next 30 days)", "Birthday"},
+	}
+	groups := m.agendaGroups()
+	if len(groups) != len(want) {
+		t.Fatalf("agendaGroups() = %d groups, want %d", len(groups), len(want))
+	}
+	for i, g := range groups {
+		var titles []string
+		for _, item := range g.contacts {
+			titles = append(titles, item.contact.Title)
+		}
+		if g.title != want[i].title || strings.Join(titles, ", ") != want[i].contacts {
+			t.Errorf("group %d = %s: %s, want %s: %s", i, g.title, strings.Join(titles, ", "), want[i].title, want[i].contacts)
+		}
+	}
+
+	if got := groups[groupAmbient].title; got != "Maybe reach out" {
+		t.Errorf("groupAmbient is %q", got)
+	}
+	if got := groups[groupUpcoming].contacts; len(got) != 1 || got[0].event == nil || got[0].days != 10 {
+		t.Errorf("groupUpcoming = %+v, want the birthday in 10 days", got)
+	}
+}
@@ -0,0 +1,345 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/ambient"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// agendaGroup is one section of the agenda
+type agendaGroup struct {
+	title    string
+	style    lipgloss.Style
+	contacts []agendaItem
+}
+
+// agendaItem is a contact with its computed due date
+type agendaItem struct {
+	contact model.Contact
+	due     time.Time
+	days    int // Days until due, negative when overdue
+	state   model.DueState    // Why the contact is due, for contacts with a due date
+	event   *model.Occurrence // Set for upcoming birthdays and other dates
+	ambient bool              // Set for the ambient pick
+}
+
+// The agenda groups, in the order shown
+const (
+	groupOverdue = iota
+	groupToday
+	groupThisWeek
+	groupLater
+	groupAmbient
+	groupUpcoming
+	groupCount
+)
+
+// upcomingDays is how far ahead the agenda lists birthdays and other dates
+const upcomingDays = 30
+
+// agendaGroups buckets scheduled contacts into Overdue / Today / This week /
+// Later, followed by the ambient pick and upcoming dates
+func (m Model) agendaGroups() []agendaGroup {
+	groups := make([]agendaGroup, groupCount)
+	groups[groupOverdue] = agendaGroup{title: "Overdue", style: overdueColor}
+	groups[groupToday] = agendaGroup{title: "Today", style: attentionColor}
+	groups[groupThisWeek] = agendaGroup{title: "This week", style: goodColor}
+	groups[groupLater] = agendaGroup{title: "Later", style: baseColor}
+	groups[groupAmbient] = agendaGroup{title: "Maybe reach out", style: snoozedColor}
+	groups[groupUpcoming] = agendaGroup{title: fmt.Sprintf("Upcoming dates (next %d days)", upcomingDays), style: headerColor}
+	
+	now := clock.Now()
+	for _, contact := range m.contacts {
+		st := contact.DueState()
+		if !st.OK {
+			continue
+		}
+		item := agendaItem{contact: contact, due: st.Due, days: st.Days, state: st}
+		g := groupLater
+		switch {
+		case item.days < 0:
+			g = groupOverdue
+		case item.days == 0:
+			g = groupToday
+		case item.days <= 7:
+			g = groupThisWeek
+		}
+		groups[g].contacts = append(groups[g].contacts, item)
+	}
+	
+	// Birthdays and other dates coming up, whatever the contact's style
+	for _, contact := range m.contacts {
+		for _, o := range contact.UpcomingEvents(upcomingDays) {
+			o := o
+			groups[groupUpcoming].contacts = append(groups[groupUpcoming].contacts, agendaItem{
+				contact: contact,
+				due:     o.Date,
+				days:    model.DaysBetween(now, o.Date),
+				event:   &o,
+			})
+		}
+	}
+	
+	for _, g := range groups {
+		sort.SliceStable(g.contacts, func(i, j int) bool {
+			if !g.contacts[i].due.Equal(g.contacts[j].due) {
+				return g.contacts[i].due.Before(g.contacts[j].due)
+			}
+			return strings.ToLower(g.contacts[i].contact.Title) < strings.ToLower(g.contacts[j].contact.Title)
+		})
+	}
+	
+	// Ambient contacts picked for today, in pick order
+	var settings config.Ambient
+	if m.cfg != nil {
+		settings = m.cfg.Ambient
+	}
+	for _, contact := range ambient.Pick(m.contacts, settings) {
+		groups[groupAmbient].contacts = append(groups[groupAmbient].contacts, agendaItem{contact: contact, ambient: true})
+	}
+	
+	return groups
+}
+
+// agendaItems flattens the agenda groups in display order
+func (m Model) agendaItems() []agendaItem {
+	var items []agendaItem
+	for _, g := range m.agendaGroups() {
+		items = append(items, g.contacts...)
+	}
+	return items
+}
+
+// updateAgenda handles input in the agenda view
+func (m Model) updateAgenda(msg tea.KeyMsg) (Model, tea.Cmd) {
+	items := m.agendaItems()
+	
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+		
+	case "esc", "q", "A":
+		m.currentView = ViewList
+		
+	case "j", "down":
+		if m.agendaCursor < len(items)-1 {
+			m.agendaCursor++
+		}
+		
+	case "k", "up":
+		if m.agendaCursor > 0 {
+			m.agendaCursor--
+		}
+		
+	case "g", "home":
+		m.agendaCursor = 0
+		
+	case "G", "end":
+		m.agendaCursor = len(items) - 1
+		
+	case "enter":
+		if m.agendaCursor < len(items) {
+			contact := items[m.agendaCursor].contact
+			m.selectedContact = &contact
+			m.detailParent = m.currentView
+			m.detailHistory = nil
+			m.currentView = ViewDetail
+		}
+		
+	case "d":
+		// Log an interaction
+		if m.agendaCursor < len(items) {
+			contact := items[m.agendaCursor].contact
+			m.contactToMark = &contact
+			m.entryView = m.currentView  // Capture where we came from
+			m.currentView = ViewInteractionType
+			m.contactLogStep = 0
+			m.interactionType = ""
+			m.interactionState = ""
+			m.interactionNote = ""
+		}
+		
+	case "b":
+		// Bump contact
+		if m.agendaCursor < len(items) {
+			return m, m.bumpContact(items[m.agendaCursor].contact)
+		}
+	}
+	
+	if m.agendaCursor >= len(items) {
+		m.agendaCursor = len(items) - 1
+	}
+	if m.agendaCursor < 0 {
+		m.agendaCursor = 0
+	}
+	
+	return m, nil
+}
+
+// viewAgenda renders the agenda view
+func (m Model) viewAgenda() string {
+	var b strings.Builder
+	
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	b.WriteString(titleStyle.Render("Agenda"))
+	b.WriteString(headerColor.Render("  " + clock.Now().Format("Monday, January 2, 2006")))
+	b.WriteString("\n")
+	
+	// Show message if present
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("82")).
+			Bold(true)
+		b.WriteString(messageStyle.Render("→ " + m.message))
+		b.WriteString("\n")
+	}
+	
+	// Render all lines first so the cursor can be scrolled into view
+	var lines []string
+	cursorLine := 0
+	index := 0
+	for _, g := range m.agendaGroups() {
+		lines = append(lines, "")
+		lines = append(lines, g.style.Bold(true).Render(fmt.Sprintf("%s (%d)", g.title, len(g.contacts))))
+		if len(g.contacts) == 0 {
+			lines = append(lines, emptyStyle.Render("  Nothing here"))
+		}
+		for _, item := range g.contacts {
+			if index == m.agendaCursor {
+				cursorLine = len(lines)
+			}
+			lines = append(lines, m.renderAgendaLine(item, index == m.agendaCursor))
+			index++
+		}
+	}
+	
+	// Header (1-2 lines) and footer (2 lines)
+	listHeight := m.height - 4
+	if m.message != "" {
+		listHeight--
+	}
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	start := 0
+	if cursorLine >= listHeight {
+		start = cursorLine - listHeight + 1
+	}
+	end := start + listHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+	visible := lines[start:end]
+	for len(visible) < listHeight {
+		visible = append(visible, "")
+	}
+	b.WriteString(strings.Join(visible, "\n"))
+	b.WriteString("\n\n")
+	
+	keys := []string{
+		"j/k:navigate",
+		"enter:view",
+		"d:contacted",
+		"b:bump",
+		"esc:back",
+	}
+	b.WriteString(headerColor.Render(strings.Join(keys, " • ")))
+	
+	return b.String()
+}
+
+// renderAgendaLine renders one contact in the agenda
+func (m Model) renderAgendaLine(item agendaItem, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = "> "
+	}
+	
+	if item.ambient {
+		return m.renderAmbientLine(item.contact, cursor, selected)
+	}
+	
+	var when string
+	switch {
+	case item.days < -1:
+		when = fmt.Sprintf("%d days overdue", -item.days)
+	case item.days == -1:
+		when = "1 day overdue"
+	case item.days == 0:
+		when = "today"
+	case item.days == 1:
+		when = "tomorrow"
+	default:
+		when = fmt.Sprintf("in %d days", item.days)
+	}
+	
+	if item.event != nil {
+		// Name the date and what it marks
+		when = item.event.Label() + item.event.YearsNote() + ", " + when
+	} else if t := item.state.Trigger; t != nil {
+		when += " (" + t.Reason + ")"
+	} else {
+		// Say why when it isn't the regular rhythm
+		switch source := item.state.Source; source {
+		case "follow-up", "deadline":
+			when += " (" + source + ")"
+		}
+	}
+	
+	name := item.contact.Title
+	if lipgloss.Width(name) > 30 {
+		name = truncateString(name, 27) + "..."
+	}
+	
+	line := fmt.Sprintf("%s%-12s %-30s  %-10s  %s",
+		cursor,
+		item.due.Format("Mon Jan 02"),
+		name,
+		item.contact.RelationshipType,
+		when,
+	)
+	
+	if selected {
+		return selectedColor.Render(line)
+	}
+	return baseColor.Render(line)
+}
+
+// renderAmbientLine renders a contact from the ambient pick
+func (m Model) renderAmbientLine(contact model.Contact, cursor string, selected bool) string {
+	when := "never contacted"
+	switch days := contact.DaysSinceContact(); {
+	case days == 0:
+		when = "contacted today"
+	case days == 1:
+		when = "last contacted yesterday"
+	case days > 1:
+		when = fmt.Sprintf("last contacted %d days ago", days)
+	}
+	
+	name := contact.Title
+	if lipgloss.Width(name) > 30 {
+		name = truncateString(name, 27) + "..."
+	}
+	
+	line := fmt.Sprintf("%s%-12s %-30s  %-10s  %s",
+		cursor,
+		"",
+		name,
+		contact.RelationshipType,
+		when,
+	)
+	
+	if selected {
+		return selectedColor.Render(line)
+	}
+	return baseColor.Render(line)
+}
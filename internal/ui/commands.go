@@ -4,18 +4,23 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/conflict"
+	"github.com/mph-llm-experiments/denote-contacts/internal/index"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
 	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+	"github.com/mph-llm-experiments/denote-contacts/internal/vcard"
 )
 
 // Message types
 type contactsLoadedMsg struct {
-	contacts []model.Contact
+	contacts  []model.Contact
+	bodyIndex *index.Index
+	conflicts []conflict.File // Contacts left in conflict by a sync
 }
 
 type contactSelectedMsg struct {
@@ -29,91 +34,65 @@ type errorMsg struct {
 type contactUpdatedMsg struct {
 	contact model.Contact
 	message string
+	action  string          // Names the change in the history; message if empty
+	others  []model.Contact // Other contacts saved along with it
+}
+
+type contactDeletedMsg struct {
+	message string
+	action  string // Names the change in the history; message if empty
 }
 
 type clearMessageMsg struct{}
 
+// exportedMsg reports a file written outside the contacts directory
+type exportedMsg struct {
+	message string
+}
+
 // loadContacts returns a command that loads all contacts from the directory
 func (m Model) loadContacts() tea.Cmd {
 	return func() tea.Msg {
-		contacts := []model.Contact{}
-		
-		// Check if the contacts directory exists
-		if _, err := os.Stat(m.contactsDir); os.IsNotExist(err) {
-			return errorMsg{err: fmt.Errorf("contacts directory '%s' does not exist. Please create it or check your configuration", m.contactsDir)}
-		} else if err != nil {
-			return errorMsg{err: fmt.Errorf("cannot access contacts directory '%s': %v", m.contactsDir, err)}
+		contacts, err := parser.LoadContacts(m.contactsDir)
+		if err != nil {
+			return errorMsg{err: err}
 		}
 		
-		// Check if it's actually a directory
-		if info, err := os.Stat(m.contactsDir); err != nil {
-			return errorMsg{err: fmt.Errorf("cannot stat contacts directory '%s': %v", m.contactsDir, err)}
-		} else if !info.IsDir() {
-			return errorMsg{err: fmt.Errorf("contacts path '%s' exists but is not a directory", m.contactsDir)}
+		// Index contact bodies for notes search, reusing the disk cache
+		// when one is present. The cache is best-effort: failures only
+		// cost a full re-index next time.
+		var cache map[string]index.Doc
+		cachePath, cacheErr := index.CachePath()
+		if cacheErr == nil {
+			cache, _ = index.LoadCache(cachePath)
 		}
-		
-		// Walk the contacts directory
-		err := filepath.Walk(m.contactsDir, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return fmt.Errorf("error reading file '%s': %v", path, err)
-			}
-			
-			// Skip directories and non-markdown files
-			if info.IsDir() || !strings.HasSuffix(path, ".md") {
-				return nil
-			}
-			
-			// Check if it's a contact file
-			if !strings.Contains(filepath.Base(path), "__contact.md") {
-				return nil
-			}
-			
-			// Parse the contact file
-			contact, err := parser.ParseContactFile(path)
-			if err != nil {
-				// Log error but continue loading other files
-				return nil
-			}
-			
-			contact.FilePath = path
-			contacts = append(contacts, contact)
-			return nil
-		})
-		
-		if err != nil {
-			return errorMsg{err: err}
+		bodyIndex := index.Build(contacts, cache)
+		if cacheErr == nil {
+			_ = bodyIndex.SaveCache(cachePath)
 		}
 		
-		// Sort contacts alphabetically by name for now
-		// TODO: Add configurable sort options
-		sort.Slice(contacts, func(i, j int) bool {
-			return strings.ToLower(contacts[i].Title) < strings.ToLower(contacts[j].Title)
-		})
+		// Contacts in conflict are skipped above and listed for resolving
+		conflicts, _ := conflict.Find(m.contactsDir)
 		
-		return contactsLoadedMsg{contacts: contacts}
+		return contactsLoadedMsg{contacts: contacts, bodyIndex: bodyIndex, conflicts: conflicts}
 	}
 }
 
 // logContactInteraction returns a command that logs a complete interaction
 func (m Model) logContactInteraction(contact model.Contact) tea.Cmd {
-	return func() tea.Msg {
+	return m.journaled(func() tea.Msg {
 		// Update the contact with all interaction details
-		now := time.Now()
+		now := clock.Now()
 		contact.LastContacted = &now
 		contact.LastInteractionType = m.interactionType
+		// Real contact ends a run of bumps
+		contact.BumpCount = 0
 		oldState := contact.State
 		contact.State = m.interactionState
 		
-		// Add note to content if provided
-		if m.interactionNote != "" {
-			// Add timestamp and note to the beginning of content
-			noteEntry := fmt.Sprintf("## %s - %s\n\n%s\n\n", 
-				now.Format("2006-01-02"),
-				m.interactionType,
-				m.interactionNote)
-			
-			contact.Content = noteEntry + contact.Content
-		}
+		// Record the interaction at the top of the content, with the note
+		// if one was given, so the history can be read back
+		contact.Content = interactionEntry(now, m.interactionType, m.interactionNote) + contact.Content
 		
 		// Save the updated contact
 		err := parser.SaveContactFile(contact)
@@ -154,8 +133,19 @@ func (m Model) logContactInteraction(contact model.Contact) tea.Cmd {
 		return contactUpdatedMsg{
 			contact: updatedContact,
 			message: message,
+			action:  fmt.Sprintf("Logged %s with %s", m.interactionType, contact.Title),
 		}
+	})
+}
+
+// interactionEntry formats an interaction heading and optional note for the
+// contact's content, in the form model.ParseInteractions reads
+func interactionEntry(date time.Time, interactionType, note string) string {
+	entry := fmt.Sprintf("## %s - %s\n\n", date.Format("2006-01-02 15:04"), interactionType)
+	if note != "" {
+		entry += note + "\n\n"
 	}
+	return entry
 }
 
 // clearMessageAfter returns a command that clears the message after a delay
@@ -167,11 +157,13 @@ func clearMessageAfter(d time.Duration) tea.Cmd {
 
 // bumpContact returns a command that updates a contact's bump date
 func (m Model) bumpContact(contact model.Contact) tea.Cmd {
-	return func() tea.Msg {
-		// Update the bump date and increment count
-		now := time.Now()
+	return m.journaled(func() tea.Msg {
+		// Update the bump date and count the bump, starting again from
+		// one if the count is left over from before the last contact
+		now := clock.Now()
+		contact.BumpCount = contact.BumpsSinceContact() + 1
 		contact.LastBumpDate = &now
-		contact.BumpCount++
+		contact.Content = interactionEntry(now, string(model.InteractionBump), "") + contact.Content
 		
 		// Save the updated contact
 		err := parser.SaveContactFile(contact)
@@ -187,14 +179,99 @@ func (m Model) bumpContact(contact model.Contact) tea.Cmd {
 		
 		return contactUpdatedMsg{
 			contact: updatedContact,
-			message: fmt.Sprintf("Bumped %s (review #%d)", contact.Title, contact.BumpCount),
+			message: fmt.Sprintf("Bumped %s (review #%d since last contact)", contact.Title, contact.BumpCount),
+			action:  fmt.Sprintf("Bumped %s", contact.Title),
 		}
-	}
+	})
+}
+
+// snoozeContact returns a command that snoozes a contact until a date, or
+// clears the snooze when until is nil
+func (m Model) snoozeContact(contact model.Contact, until *time.Time) tea.Cmd {
+	return m.journaled(func() tea.Msg {
+		contact.SnoozedUntil = until
+		
+		// Save the updated contact
+		err := parser.SaveContactFile(contact)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save snooze for '%s': %v", contact.Title, err)}
+		}
+		
+		// Reload the contact to get the updated state
+		updatedContact, err := parser.ParseContactFile(contact.FilePath)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to reload contact '%s' after snooze: %v", contact.Title, err)}
+		}
+		
+		message := fmt.Sprintf("Unsnoozed %s", contact.Title)
+		if until != nil {
+			message = fmt.Sprintf("Snoozed %s until %s", contact.Title, until.Format("January 2, 2006"))
+		}
+		
+		return contactUpdatedMsg{
+			contact: updatedContact,
+			message: message,
+		}
+	})
+}
+
+// archiveContact returns a command that archives a contact, or restores it
+// from the archive when archive is false
+func (m Model) archiveContact(contact model.Contact, archive bool) tea.Cmd {
+	return m.journaled(func() tea.Msg {
+		contact.Archived = archive
+		contact.ArchivedAt = nil
+		if archive {
+			now := clock.Now()
+			contact.ArchivedAt = &now
+		} else if contact.State == string(model.StateArchived) {
+			contact.State = string(model.StateActive)
+		}
+		
+		// Save the updated contact
+		err := parser.SaveContactFile(contact)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save archive for '%s': %v", contact.Title, err)}
+		}
+		
+		// Reload the contact to get the updated state
+		updatedContact, err := parser.ParseContactFile(contact.FilePath)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to reload contact '%s' after archive: %v", contact.Title, err)}
+		}
+		
+		message := fmt.Sprintf("Restored %s from the archive", contact.Title)
+		action := message
+		if archive {
+			message = fmt.Sprintf("Archived %s (hidden from the list; f then A shows archived)", contact.Title)
+			action = fmt.Sprintf("Archived %s", contact.Title)
+		}
+		
+		return contactUpdatedMsg{
+			contact: updatedContact,
+			message: message,
+			action:  action,
+		}
+	})
+}
+
+// deleteContact returns a command that moves a contact's file to the trash
+func (m Model) deleteContact(contact model.Contact) tea.Cmd {
+	contactsDir := m.contactsDir
+	return m.journaled(func() tea.Msg {
+		if _, err := parser.MoveToTrash(contactsDir, contact.FilePath); err != nil {
+			return errorMsg{err: err}
+		}
+		return contactDeletedMsg{
+			message: fmt.Sprintf("Moved %s to the trash (denote-contacts restore brings it back)", contact.Title),
+			action:  fmt.Sprintf("Moved %s to the trash", contact.Title),
+		}
+	})
 }
 
 // saveEditedContact returns a command that saves the edited contact
 func (m Model) saveEditedContact() tea.Cmd {
-	return func() tea.Msg {
+	return m.journaled(func() tea.Msg {
 		if m.editingContact == nil {
 			return errorMsg{err: fmt.Errorf("no contact being edited")}
 		}
@@ -214,6 +291,46 @@ func (m Model) saveEditedContact() tea.Cmd {
 		contact.ContactStyle = model.ContactStyle(strings.TrimSpace(m.editValues[fieldContactStyle]))
 		contact.State = strings.TrimSpace(m.editValues[fieldState])
 		
+		// Parse scheduled dates
+		oldFollowUp, oldDeadline := contact.FollowUpDate, contact.DeadlineDate
+		followUp, err := parseEditDate("follow-up", m.editValues[fieldFollowUp])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		deadline, err := parseEditDate("deadline", m.editValues[fieldDeadline])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		contact.FollowUpDate = followUp
+		contact.DeadlineDate = deadline
+		
+		// Parse birthday and other yearly dates
+		birthday, err := parseEditBirthday(m.editValues[fieldBirthday])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		dates, err := parseEditDates(m.editValues[fieldDates])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		contact.Birthday = birthday
+		contact.Dates = dates
+		
+		// Labels must be unique so related contacts resolve to one contact
+		contact.Label = model.NormalizeLabel(m.editValues[fieldLabel])
+		contact.RelatedContactLabels = model.ParseLabels(m.editValues[fieldRelated])
+		contact.Relationships, err = model.ParseRelationships(m.editValues[fieldPeople])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		if contact.Label == "" && len(contact.Relationships) > 0 {
+			// The other side needs a label to point back at
+			contact.Label = model.NormalizeLabel(contact.Title)
+		}
+		if err := model.CheckLabel(m.contacts, contact); err != nil {
+			return errorMsg{err: err}
+		}
+		
 		// Parse and update tags
 		tagStr := strings.TrimSpace(m.editValues[fieldTags])
 		tags := []string{"contact"} // Always include the contact tag
@@ -228,15 +345,21 @@ func (m Model) saveEditedContact() tea.Cmd {
 		contact.Tags = tags
 		
 		// Update the updated_at timestamp
-		now := time.Now()
+		now := clock.Now()
 		contact.UpdatedAt = now
 		
 		// Save the updated contact
-		err := parser.SaveContactFile(contact)
+		err = parser.SaveContactFile(contact)
 		if err != nil {
 			return errorMsg{err: fmt.Errorf("failed to save changes to '%s': %v", contact.Title, err)}
 		}
 		
+		// Keep the other side of each relationship in step
+		others, err := saveRelationshipSync(m.contacts, *m.editingContact, contact)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		
 		// Create task if state changed to one requiring action
 		var taskCreated bool
 		if oldState != contact.State {
@@ -251,6 +374,11 @@ func (m Model) saveEditedContact() tea.Cmd {
 			}
 		}
 		
+		// Create dated tasks for new or moved follow-up and deadline dates
+		if m.createScheduledTasks(contact, oldFollowUp, oldDeadline) {
+			taskCreated = true
+		}
+		
 		// Reload the contact to get the updated state
 		updatedContact, err := parser.ParseContactFile(contact.FilePath)
 		if err != nil {
@@ -258,6 +386,10 @@ func (m Model) saveEditedContact() tea.Cmd {
 		}
 		
 		message := fmt.Sprintf("Updated %s", contact.Title)
+		if len(others) > 0 {
+			message += fmt.Sprintf(" and %d related", len(others))
+		}
+		action := message
 		if taskCreated {
 			message += " [task created]"
 		}
@@ -265,8 +397,28 @@ func (m Model) saveEditedContact() tea.Cmd {
 		return contactUpdatedMsg{
 			contact: updatedContact,
 			message: message,
+			action:  action,
+			others:  others,
 		}
+	})
+}
+
+// saveRelationshipSync saves the other contacts whose relationships change
+// when a contact goes from before to after, and returns them reloaded
+func saveRelationshipSync(contacts []model.Contact, before, after model.Contact) ([]model.Contact, error) {
+	var saved []model.Contact
+	for _, other := range model.SyncRelationships(contacts, before, after) {
+		other.UpdatedAt = clock.Now()
+		if err := parser.SaveContactFile(other); err != nil {
+			return nil, fmt.Errorf("saved '%s' but failed to update related contact '%s': %v", after.Title, other.Title, err)
+		}
+		reloaded, err := parser.ParseContactFile(other.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reload related contact '%s': %v", other.Title, err)
+		}
+		saved = append(saved, reloaded)
 	}
+	return saved, nil
 }
 
 // createTaskForContact creates a task when a contact changes to an action-requiring state
@@ -290,8 +442,59 @@ func (m Model) createTaskForContact(contact model.Contact, newState string) erro
 		taskTitle += " (no response)"
 	}
 	
+	// Add task description
+	var description string
+	switch newState {
+	case "followup":
+		description = fmt.Sprintf("Follow up with %s regarding previous conversation.\n", contact.Title)
+	case "ping":
+		description = fmt.Sprintf("Send a quick check-in message to %s.\n", contact.Title)
+	case "scheduled":
+		description = fmt.Sprintf("Scheduled meeting or call with %s.\n", contact.Title)
+	case "timeout":
+		description = fmt.Sprintf("%s has not responded. Consider following up or closing the loop.\n", contact.Title)
+	}
+	
+	return writeTaskFile(contact, taskTitle, "contact-"+newState, description, nil)
+}
+
+// createScheduledTasks creates dated tasks for follow-up and deadline dates
+// that are new or have moved. Returns true if any task was created.
+func (m Model) createScheduledTasks(contact model.Contact, oldFollowUp, oldDeadline *time.Time) bool {
+	created := false
+	
+	if contact.FollowUpDate != nil && !sameDay(contact.FollowUpDate, oldFollowUp) {
+		title := fmt.Sprintf("Follow up with %s", contact.Title)
+		description := fmt.Sprintf("Scheduled follow-up with %s.\n", contact.Title)
+		if err := writeTaskFile(contact, title, "contact-followup", description, contact.FollowUpDate); err == nil {
+			created = true
+		}
+	}
+	
+	if contact.DeadlineDate != nil && !sameDay(contact.DeadlineDate, oldDeadline) {
+		title := fmt.Sprintf("Deadline for %s", contact.Title)
+		description := fmt.Sprintf("Deadline related to %s.\n", contact.Title)
+		if err := writeTaskFile(contact, title, "contact-deadline", description, contact.DeadlineDate); err == nil {
+			created = true
+		}
+	}
+	
+	return created
+}
+
+// sameDay reports whether two optional dates fall on the same day
+func sameDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return model.StartOfDay(*a).Equal(model.StartOfDay(*b))
+}
+
+// writeTaskFile writes a denote-tasks task linked to the contact, with an
+// optional due date
+func writeTaskFile(contact model.Contact, taskTitle, stateTag, description string, dueDate *time.Time) error {
 	// Generate task filename
-	now := time.Now()
+	now := clock.Now()
 	dateStr := now.Format("20060102T150405")
 	titleSlug := strings.ToLower(strings.ReplaceAll(taskTitle, " ", "-"))
 	titleSlug = strings.ReplaceAll(titleSlug, "(", "")
@@ -300,7 +503,7 @@ func (m Model) createTaskForContact(contact model.Contact, newState string) erro
 	titleSlug = strings.ReplaceAll(titleSlug, ".", "")
 	
 	// Create tags based on contact
-	tags := []string{"task", fmt.Sprintf("contact-%s", newState)}
+	tags := []string{"task", stateTag}
 	
 	// Generate index_id - simple timestamp-based ID
 	indexID := now.Unix() % 100000
@@ -315,29 +518,19 @@ func (m Model) createTaskForContact(contact model.Contact, newState string) erro
 	taskContent.WriteString(fmt.Sprintf("index_id: %d\n", indexID))
 	taskContent.WriteString("type: task\n")
 	taskContent.WriteString("status: open\n")
+	if dueDate != nil {
+		taskContent.WriteString(fmt.Sprintf("due_date: %s\n", dueDate.Format("2006-01-02")))
+	}
 	if contact.Label != "" {
 		taskContent.WriteString(fmt.Sprintf("label: %s\n", contact.Label))
 	}
 	taskContent.WriteString(fmt.Sprintf("contact_id: %s\n", contact.Identifier))
 	taskContent.WriteString("---\n\n")
-	
-	// Add task description
-	switch newState {
-	case "followup":
-		taskContent.WriteString(fmt.Sprintf("Follow up with %s regarding previous conversation.\n", contact.Title))
-	case "ping":
-		taskContent.WriteString(fmt.Sprintf("Send a quick check-in message to %s.\n", contact.Title))
-	case "scheduled":
-		taskContent.WriteString(fmt.Sprintf("Scheduled meeting or call with %s.\n", contact.Title))
-	case "timeout":
-		taskContent.WriteString(fmt.Sprintf("%s has not responded. Consider following up or closing the loop.\n", contact.Title))
-	}
+	taskContent.WriteString(description)
 	
 	// Save task file
 	filename := fmt.Sprintf("%s--%s__task.md", dateStr, titleSlug)
-	// Always save tasks to ~/notes directory
-	homeDir, _ := os.UserHomeDir()
-	notesDir := filepath.Join(homeDir, "notes")
+	notesDir := parser.TasksDir()
 	
 	// Create notes directory if it doesn't exist
 	if err := os.MkdirAll(notesDir, 0755); err != nil {
@@ -346,7 +539,7 @@ func (m Model) createTaskForContact(contact model.Contact, newState string) erro
 	
 	taskPath := filepath.Join(notesDir, filename)
 	
-	if err := os.WriteFile(taskPath, []byte(taskContent.String()), 0644); err != nil {
+	if err := parser.WriteFile(taskPath, []byte(taskContent.String())); err != nil {
 		return fmt.Errorf("failed to create task file '%s': %v", filename, err)
 	}
 	
@@ -355,9 +548,9 @@ func (m Model) createTaskForContact(contact model.Contact, newState string) erro
 
 // saveQuickTypeChange returns a command that saves a quick type change
 func (m Model) saveQuickTypeChange(contact model.Contact) tea.Cmd {
-	return func() tea.Msg {
+	return m.journaled(func() tea.Msg {
 		// Update the updated_at timestamp
-		now := time.Now()
+		now := clock.Now()
 		contact.UpdatedAt = now
 
 		// Save the updated contact
@@ -376,12 +569,12 @@ func (m Model) saveQuickTypeChange(contact model.Contact) tea.Cmd {
 			contact: updatedContact,
 			message: fmt.Sprintf("Changed %s to %s", contact.Title, contact.RelationshipType),
 		}
-	}
+	})
 }
 
 // saveNewContact returns a command that creates and saves a new contact
 func (m Model) saveNewContact() tea.Cmd {
-	return func() tea.Msg {
+	return m.journaled(func() tea.Msg {
 		// Validate required fields
 		name := strings.TrimSpace(m.editValues[fieldTitle])
 		if name == "" {
@@ -389,12 +582,10 @@ func (m Model) saveNewContact() tea.Cmd {
 		}
 		
 		// Create new contact from form values
-		now := time.Now()
-		dateStr := now.Format("20060102T150405")
+		now := clock.Now()
 		contact := model.Contact{
 			Date:       now,
 			Title:      name,
-			Identifier: dateStr, // Set the identifier for task linkage
 			Email:      strings.TrimSpace(m.editValues[fieldEmail]),
 			Phone:      strings.TrimSpace(m.editValues[fieldPhone]),
 			Company:    strings.TrimSpace(m.editValues[fieldCompany]),
@@ -419,24 +610,55 @@ func (m Model) saveNewContact() tea.Cmd {
 		}
 		contact.Tags = tags
 		
-		// Check if contacts directory exists before trying to save
-		if _, err := os.Stat(m.contactsDir); os.IsNotExist(err) {
-			return errorMsg{err: fmt.Errorf("cannot create contact: directory '%s' does not exist. Please create it first", m.contactsDir)}
-		} else if err != nil {
-			return errorMsg{err: fmt.Errorf("cannot access contacts directory '%s': %v", m.contactsDir, err)}
+		// Parse scheduled dates
+		followUp, err := parseEditDate("follow-up", m.editValues[fieldFollowUp])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		deadline, err := parseEditDate("deadline", m.editValues[fieldDeadline])
+		if err != nil {
+			return errorMsg{err: err}
 		}
+		contact.FollowUpDate = followUp
+		contact.DeadlineDate = deadline
 		
-		// Generate Denote filename using the same timestamp as the identifier
-		nameSlug := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
-		nameSlug = strings.ReplaceAll(nameSlug, "'", "")
-		nameSlug = strings.ReplaceAll(nameSlug, ".", "")
-		filename := fmt.Sprintf("%s--%s__contact.md", dateStr, nameSlug)
-		contact.FilePath = filepath.Join(m.contactsDir, filename)
+		// Parse birthday and other yearly dates
+		birthday, err := parseEditBirthday(m.editValues[fieldBirthday])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		dates, err := parseEditDates(m.editValues[fieldDates])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		contact.Birthday = birthday
+		contact.Dates = dates
 		
-		// Save the new contact
-		err := parser.SaveContactFile(contact)
+		// Labels must be unique so related contacts resolve to one contact
+		contact.Label = model.NormalizeLabel(m.editValues[fieldLabel])
+		contact.RelatedContactLabels = model.ParseLabels(m.editValues[fieldRelated])
+		contact.Relationships, err = model.ParseRelationships(m.editValues[fieldPeople])
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		if contact.Label == "" && len(contact.Relationships) > 0 {
+			// The other side needs a label to point back at
+			contact.Label = model.NormalizeLabel(contact.Title)
+		}
+		if err := model.CheckLabel(m.contacts, contact); err != nil {
+			return errorMsg{err: err}
+		}
+		
+		// Save the new contact, named from its identifier for task linkage
+		contact, err = parser.CreateContact(m.contactsDir, contact)
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		
+		// Point the other side of each relationship back at the new contact
+		others, err := saveRelationshipSync(m.contacts, model.Contact{}, contact)
 		if err != nil {
-			return errorMsg{err: fmt.Errorf("failed to save contact '%s': %v", name, err)}
+			return errorMsg{err: err}
 		}
 		
 		// Create task if new contact has an action-requiring state
@@ -452,6 +674,9 @@ func (m Model) saveNewContact() tea.Cmd {
 				taskCreated = true
 			}
 		}
+		if m.createScheduledTasks(contact, nil, nil) {
+			taskCreated = true
+		}
 		
 		// Reload the contact to get the saved state
 		savedContact, err := parser.ParseContactFile(contact.FilePath)
@@ -460,6 +685,10 @@ func (m Model) saveNewContact() tea.Cmd {
 		}
 		
 		message := fmt.Sprintf("Created %s", contact.Title)
+		if len(others) > 0 {
+			message += fmt.Sprintf(" and updated %d related", len(others))
+		}
+		action := message
 		if taskCreated {
 			message += " [task created]"
 		}
@@ -467,6 +696,56 @@ func (m Model) saveNewContact() tea.Cmd {
 		return contactUpdatedMsg{
 			contact: savedContact,
 			message: message,
+			action:  action,
 		}
+	})
+}
+
+// exportVCards returns a command that writes contacts to a .vcf file in the
+// directory the app was started from. An earlier export is never replaced:
+// the new file gets a -2, -3, ... suffix instead.
+func exportVCards(contacts []model.Contact, filename string) tea.Cmd {
+	return func() tea.Msg {
+		if len(contacts) == 0 {
+			return errorMsg{err: fmt.Errorf("no contacts to export")}
+		}
+		dir, err := os.Getwd()
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		f, path, err := createUnused(filepath.Join(dir, filename))
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to export vCards: %v", err)}
+		}
+		if err := vcard.Write(f, contacts); err != nil {
+			f.Close()
+			return errorMsg{err: fmt.Errorf("failed to export vCards: %v", err)}
+		}
+		if err := f.Close(); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to export vCards: %v", err)}
+		}
+		noun := "contacts"
+		if len(contacts) == 1 {
+			noun = "contact"
+		}
+		return exportedMsg{message: fmt.Sprintf("Exported %d %s to %s", len(contacts), noun, path)}
 	}
-}
\ No newline at end of file
+}
+
+// createUnused creates path, or the first of path-2, path-3, ... (before the
+// extension) that doesn't exist yet, and returns the file and its path
+func createUnused(path string) (*os.File, string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 1; ; n++ {
+		name := path
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		return f, name, err
+	}
+}
@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/conflict"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// openConflicts shows the contacts left in conflict by a sync
+func (m Model) openConflicts() Model {
+	m.conflictCursor = 0
+	m.conflictMerge = nil
+	m.currentView = ViewConflicts
+	return m
+}
+
+// updateConflicts handles input in the conflict resolver: a list of files in
+// conflict, and for the open one the fields the merge couldn't settle
+func (m Model) updateConflicts(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	// File list
+	if m.conflictMerge == nil {
+		switch msg.String() {
+		case "esc", "q":
+			m.currentView = ViewList
+		case "j", "down":
+			if m.conflictCursor < len(m.conflicts)-1 {
+				m.conflictCursor++
+			}
+		case "k", "up":
+			if m.conflictCursor > 0 {
+				m.conflictCursor--
+			}
+		case "enter":
+			if m.conflictCursor < len(m.conflicts) {
+				result, err := conflict.Load(m.conflicts[m.conflictCursor])
+				if err != nil {
+					m.message = err.Error()
+					return m, clearMessageAfter(5 * time.Second)
+				}
+				m.conflictMerge = result
+				m.conflictField = 0
+			}
+		}
+		return m, nil
+	}
+
+	// Remaining conflicts in the open file
+	switch msg.String() {
+	case "esc", "q":
+		m.conflictMerge = nil
+	case "j", "down":
+		if m.conflictField < len(m.conflictMerge.Conflicts)-1 {
+			m.conflictField++
+		}
+	case "k", "up":
+		if m.conflictField > 0 {
+			m.conflictField--
+		}
+	case "o", "1":
+		m.conflictMerge.Choose(m.conflictField, conflict.Ours)
+		m.conflictField = min(m.conflictField+1, max(len(m.conflictMerge.Conflicts)-1, 0))
+	case "t", "2":
+		m.conflictMerge.Choose(m.conflictField, conflict.Theirs)
+		m.conflictField = min(m.conflictField+1, max(len(m.conflictMerge.Conflicts)-1, 0))
+	case "s":
+		if m.conflictMerge.Unresolved() == 0 {
+			return m, m.resolveConflict(m.conflicts[m.conflictCursor], m.conflictMerge)
+		}
+	}
+	return m, nil
+}
+
+// resolveConflict returns a command that writes the merged contact and
+// removes the conflict copy it came from
+func (m Model) resolveConflict(f conflict.File, result *conflict.Result) tea.Cmd {
+	return m.journaled(func() tea.Msg {
+		content, err := result.Content()
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		if err := parser.WriteFile(f.Path, content); err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save merged contact: %v", err)}
+		}
+		if f.Copy != "" {
+			if err := parser.RemoveFile(f.Copy); err != nil {
+				return errorMsg{err: fmt.Errorf("merged, but failed to remove the conflict copy: %v", err)}
+			}
+		}
+		return contactsSavedMsg{message: "Resolved conflict in " + result.Title()}
+	})
+}
+
+// viewConflicts renders the conflict resolver
+func (m Model) viewConflicts() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	b.WriteString(titleStyle.Render("Sync Conflicts"))
+	b.WriteString(headerColor.Render(fmt.Sprintf("  %d files", len(m.conflicts))))
+	b.WriteString("\n")
+
+	// Show message if present
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("82")).
+			Bold(true)
+		b.WriteString(messageStyle.Render("→ " + m.message))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	var lines []string
+	var keys []string
+	if m.conflictMerge == nil {
+		lines = m.renderConflictList()
+		keys = []string{"j/k:navigate", "enter:merge", "esc:back"}
+	} else {
+		lines = m.renderConflictFields()
+		keys = []string{"j/k:navigate", "o:keep ours", "t:take theirs", "esc:back to files"}
+		if m.conflictMerge.Unresolved() == 0 {
+			keys = append(keys[:3], "s:save merge", "esc:back to files")
+		}
+	}
+
+	// Header (3 lines) and footer (2 lines)
+	height := m.height - 5
+	if m.message != "" {
+		height--
+	}
+	if height < 1 {
+		height = 1
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	b.WriteString(strings.Join(lines[:height], "\n"))
+	b.WriteString("\n\n")
+	b.WriteString(headerColor.Render(strings.Join(keys, " • ")))
+
+	return b.String()
+}
+
+// renderConflictList lists the files in conflict, scrolled to the cursor
+func (m Model) renderConflictList() []string {
+	if len(m.conflicts) == 0 {
+		return []string{emptyStyle.Render("  No sync conflicts")}
+	}
+
+	height := m.height - 5
+	start := 0
+	if m.conflictCursor >= height && height > 0 {
+		start = m.conflictCursor - height + 1
+	}
+	var lines []string
+	for i := start; i < len(m.conflicts); i++ {
+		f := m.conflicts[i]
+		cursor := "  "
+		style := baseColor
+		if i == m.conflictCursor {
+			cursor = "> "
+			style = selectedColor
+		}
+		kind := "git conflict markers"
+		if f.Copy != "" {
+			kind = "Syncthing copy"
+		}
+		line := fmt.Sprintf("%-56s %s", truncateString(f.Name(), 56), kind)
+		lines = append(lines, cursor+style.Render(line))
+	}
+	return lines
+}
+
+// renderConflictFields shows the open file's remaining conflicts with both
+// versions. Everything else has been merged already.
+func (m Model) renderConflictFields() []string {
+	r := m.conflictMerge
+	f := m.conflicts[m.conflictCursor]
+	ours, theirs := "OURS (this file)", "THEIRS (conflict copy)"
+	if f.Copy == "" {
+		ours, theirs = "OURS (HEAD)", "THEIRS (incoming)"
+	}
+
+	var lines []string
+	if len(r.Conflicts) == 0 {
+		lines = append(lines, goodColor.Render("  Merged cleanly: fields were combined and interactions unioned by date."))
+		lines = append(lines, headerColor.Render("  Press s to save "+r.Title()+"."))
+		return lines
+	}
+	lines = append(lines,
+		headerColor.Render(fmt.Sprintf("  %s: %d of %d left to choose", r.Title(), r.Unresolved(), len(r.Conflicts))),
+		"",
+		headerColor.Render(fmt.Sprintf("    %-24s  %-32s  %s", "", ours, theirs)))
+	for i, c := range r.Conflicts {
+		cursor := "  "
+		if i == m.conflictField {
+			cursor = "> "
+		}
+		oursStyle, theirsStyle := baseColor, baseColor
+		switch c.Choice {
+		case conflict.Ours:
+			oursStyle, theirsStyle = goodColor, headerColor
+		case conflict.Theirs:
+			oursStyle, theirsStyle = headerColor, goodColor
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s  %s  %s", cursor,
+			labelStyle.Render(fmt.Sprintf("%-24s", truncateString(c.Field, 24))),
+			oursStyle.Render(fmt.Sprintf("%-32s", truncateString(oneLine(c.Ours), 32))),
+			theirsStyle.Render(truncateString(oneLine(c.Theirs), 48))))
+	}
+
+	// The selected conflict in full, as values may be long
+	if m.conflictField < len(r.Conflicts) {
+		c := r.Conflicts[m.conflictField]
+		lines = append(lines, "", labelStyle.Render("  Ours:"))
+		for _, line := range strings.Split(conflictValue(c.Ours), "\n") {
+			lines = append(lines, "    "+line)
+		}
+		lines = append(lines, labelStyle.Render("  Theirs:"))
+		for _, line := range strings.Split(conflictValue(c.Theirs), "\n") {
+			lines = append(lines, "    "+line)
+		}
+	}
+	return lines
+}
+
+// oneLine shows a value on one line for the table
+func oneLine(s string) string {
+	return conflictValue(strings.Join(strings.Fields(s), " "))
+}
+
+// conflictValue shows a missing value as such
+func conflictValue(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -45,6 +45,20 @@ func (m Model) updateCreate(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.editField = fieldState
 		case "T":
 			m.editField = fieldTags
+		case "f":
+			m.editField = fieldFollowUp
+		case "d":
+			m.editField = fieldDeadline
+		case "b":
+			m.editField = fieldBirthday
+		case "a":
+			m.editField = fieldDates
+		case "L":
+			m.editField = fieldLabel
+		case "R":
+			m.editField = fieldRelated
+		case "P":
+			m.editField = fieldPeople
 		}
 	} else {
 		// Field editing mode
@@ -62,7 +76,7 @@ func (m Model) updateCreate(msg tea.KeyMsg) (Model, tea.Cmd) {
 		case "backspace":
 			// Delete character from current field
 			if len(m.editValues[m.editField]) > 0 {
-				m.editValues[m.editField] = m.editValues[m.editField][:len(m.editValues[m.editField])-1]
+				m.editValues[m.editField] = dropLastRune(m.editValues[m.editField])
 			}
 
 		default:
@@ -161,6 +175,15 @@ func (m Model) viewCreate() string {
 		Foreground(lipgloss.Color("214"))
 	b.WriteString(titleStyle.Render("Create New Contact"))
 	b.WriteString("\n\n")
+	
+	// Show message if present
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("82")).
+			Bold(true)
+		b.WriteString(messageStyle.Render("→ " + m.message))
+		b.WriteString("\n\n")
+	}
 
 	if m.editField == -1 {
 		// Field selection mode - show all fields with hotkeys
@@ -185,8 +208,6 @@ func (m Model) renderCreateFieldSelectionView() string {
 	var b strings.Builder
 	
 	// Show all fields with their current values and hotkeys
-	fieldHotkeys := []string{"n", "e", "p", "c", "r", "l", "t", "s", "S", "T"}
-	
 	for i := 0; i < fieldCount; i++ {
 		label := fieldLabels[i]
 		value := m.editValues[i]
@@ -237,6 +258,16 @@ func (m Model) renderCreateFieldEditingView() string {
 		displayValue := value + "█"
 		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render(displayValue))
 		b.WriteString("\n\n")
+		if isDateField(m.editField) {
+			b.WriteString(renderDateHint())
+			b.WriteString("\n\n")
+		} else if isEventField(m.editField) {
+			b.WriteString(renderEventHint(m.editField))
+			b.WriteString("\n\n")
+		} else if isLabelField(m.editField) {
+			b.WriteString(renderLabelHint(m.editField))
+			b.WriteString("\n\n")
+		}
 	}
 	
 	// Instructions
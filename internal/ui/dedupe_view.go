@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/dedupe"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// openDedupe finds likely duplicates and shows them
+func (m Model) openDedupe() Model {
+	m.dedupeCandidates = dedupe.Find(m.contacts)
+	m.dedupeCursor = 0
+	m.dedupePair = -1
+	m.currentView = ViewDedupe
+	return m
+}
+
+// updateDedupe handles input in the duplicate review: a list of candidate
+// pairs, and for the open pair a field diff where fields can be taken from
+// the newer contact before merging
+func (m Model) updateDedupe(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	// Pair list
+	if m.dedupePair < 0 {
+		switch msg.String() {
+		case "esc", "q":
+			m.currentView = ViewList
+			m.dedupeCandidates = nil
+		case "j", "down":
+			if m.dedupeCursor < len(m.dedupeCandidates)-1 {
+				m.dedupeCursor++
+			}
+		case "k", "up":
+			if m.dedupeCursor > 0 {
+				m.dedupeCursor--
+			}
+		case "enter":
+			if m.dedupeCursor < len(m.dedupeCandidates) {
+				m.dedupePair = m.dedupeCursor
+				m.dedupeField = 0
+				m.dedupeTake = make(map[string]bool)
+				m.dedupeConfirm = false
+			}
+		}
+		return m, nil
+	}
+
+	// Confirming a merge
+	if m.dedupeConfirm {
+		switch msg.String() {
+		case "y", "Y":
+			m.dedupeConfirm = false
+			return m, m.mergeDuplicates(m.dedupeCandidates[m.dedupePair], m.dedupeTake)
+		case "n", "N", "esc":
+			m.dedupeConfirm = false
+		}
+		return m, nil
+	}
+
+	// Field diff for the open pair
+	c := m.dedupeCandidates[m.dedupePair]
+	diffs := dedupe.Compare(m.contacts[c.A], m.contacts[c.B])
+	switch msg.String() {
+	case "esc", "q":
+		m.dedupePair = -1
+	case "j", "down":
+		if m.dedupeField < len(diffs)-1 {
+			m.dedupeField++
+		}
+	case "k", "up":
+		if m.dedupeField > 0 {
+			m.dedupeField--
+		}
+	case " ", "tab":
+		// Choose which side's value the merged contact keeps
+		if m.dedupeField < len(diffs) {
+			d := diffs[m.dedupeField]
+			if d.Conflict {
+				m.dedupeTake[d.Field] = !m.dedupeTake[d.Field]
+			}
+		}
+	case "m":
+		m.dedupeConfirm = true
+	}
+	return m, nil
+}
+
+// mergeDuplicates returns a command that merges the newer contact of the pair
+// into the older one
+func (m Model) mergeDuplicates(c dedupe.Candidate, take map[string]bool) tea.Cmd {
+	contacts := m.contacts
+	contactsDir := m.contactsDir
+	return m.journaled(func() tea.Msg {
+		keep, lose := contacts[c.A], contacts[c.B]
+		result, err := dedupe.Apply(contacts, keep, lose, take, contactsDir, parser.TasksDir())
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		message := fmt.Sprintf("Merged %s into %s", lose.Title, result.Merged.Title)
+		if n := len(result.Others); n > 0 {
+			message += fmt.Sprintf(", updated %d linked", n)
+		}
+		if result.Tasks > 0 {
+			message += fmt.Sprintf(", repointed %d tasks", result.Tasks)
+		}
+		return contactsSavedMsg{message: message}
+	})
+}
+
+// viewDedupe renders the duplicate review
+func (m Model) viewDedupe() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	b.WriteString(titleStyle.Render("Duplicates"))
+	b.WriteString(headerColor.Render(fmt.Sprintf("  %d likely pairs", len(m.dedupeCandidates))))
+	b.WriteString("\n")
+
+	// Show message if present
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("82")).
+			Bold(true)
+		b.WriteString(messageStyle.Render("→ " + m.message))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	var lines []string
+	var keys []string
+	if m.dedupePair < 0 {
+		lines = m.renderDedupeList()
+		keys = []string{"j/k:navigate", "enter:compare", "esc:back"}
+	} else {
+		lines = m.renderDedupePair()
+		keys = []string{"j/k:navigate", "space:take other value", "m:merge", "esc:back to pairs"}
+		if m.dedupeConfirm {
+			c := m.dedupeCandidates[m.dedupePair]
+			prompt := fmt.Sprintf("Merge %s into %s and move it to the trash? (y/n)",
+				m.contacts[c.B].Title, m.contacts[c.A].Title)
+			keys = []string{attentionColor.Render(prompt)}
+		}
+	}
+
+	// Header (3 lines) and footer (2 lines)
+	height := m.height - 5
+	if m.message != "" {
+		height--
+	}
+	if height < 1 {
+		height = 1
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	b.WriteString(strings.Join(lines[:height], "\n"))
+	b.WriteString("\n\n")
+	b.WriteString(headerColor.Render(strings.Join(keys, " • ")))
+
+	return b.String()
+}
+
+// renderDedupeList lists the candidate pairs, scrolled to the cursor
+func (m Model) renderDedupeList() []string {
+	if len(m.dedupeCandidates) == 0 {
+		return []string{emptyStyle.Render("  No likely duplicates")}
+	}
+
+	height := m.height - 5
+	start := 0
+	if m.dedupeCursor >= height && height > 0 {
+		start = m.dedupeCursor - height + 1
+	}
+	var lines []string
+	for i := start; i < len(m.dedupeCandidates); i++ {
+		c := m.dedupeCandidates[i]
+		cursor := "  "
+		style := baseColor
+		if i == m.dedupeCursor {
+			cursor = "> "
+			style = selectedColor
+		}
+		line := fmt.Sprintf("%3.0f%%  %-28s ⇐ %-28s %s", c.Score*100,
+			truncateString(m.contacts[c.A].Title, 28), truncateString(m.contacts[c.B].Title, 28),
+			strings.Join(c.Reasons, ", "))
+		lines = append(lines, cursor+style.Render(line))
+	}
+	return lines
+}
+
+// renderDedupePair shows the open pair's fields side by side. The older
+// contact is kept; marked fields take the newer contact's value instead.
+func (m Model) renderDedupePair() []string {
+	c := m.dedupeCandidates[m.dedupePair]
+	keep, lose := m.contacts[c.A], m.contacts[c.B]
+
+	lines := []string{
+		headerColor.Render(fmt.Sprintf("    %-10s  %-32s  %s", "", "KEEP "+keep.Identifier, "MERGE IN "+lose.Identifier)),
+	}
+	for i, d := range dedupe.Compare(keep, lose) {
+		cursor := "  "
+		if i == m.dedupeField {
+			cursor = "> "
+		}
+		keepStyle, loseStyle := baseColor, headerColor
+		if m.dedupeTake[d.Field] || d.Keep == "" {
+			keepStyle, loseStyle = headerColor, baseColor
+		}
+		if d.Conflict {
+			if m.dedupeTake[d.Field] {
+				loseStyle = goodColor
+			} else {
+				keepStyle = goodColor
+			}
+		}
+		mark := "  "
+		if d.Conflict {
+			mark = "≠ "
+		}
+		lines = append(lines, fmt.Sprintf("%s  %-10s  %s  %s%s", cursor,
+			labelStyle.Render(d.Field),
+			keepStyle.Render(fmt.Sprintf("%-32s", truncateString(d.Keep, 32))),
+			mark, loseStyle.Render(d.Lose)))
+	}
+
+	lines = append(lines, "",
+		headerColor.Render(fmt.Sprintf("  %d and %d interactions are combined newest first; tags, dates and links are merged.",
+			len(keep.Interactions), len(lose.Interactions))),
+		headerColor.Render("  Values left out are noted in the merged contact's body. Tasks are pointed at the kept contact."))
+	return lines
+}
@@ -3,10 +3,13 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/vcard"
 )
 
 // Detail view styles
@@ -28,11 +31,37 @@ var (
 
 // updateDetail handles input in detail view
 func (m Model) updateDetail(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Confirming a delete
+	if m.confirmDelete {
+		m.confirmDelete = false
+		if (msg.String() == "y" || msg.String() == "Y") && m.selectedContact != nil {
+			return m, m.deleteContact(*m.selectedContact)
+		}
+		return m, nil
+	}
+	
 	switch msg.String() {
 	case "esc", "q":
-		m.currentView = ViewList
+		// Step back through related contacts before leaving
+		if n := len(m.detailHistory); n > 0 {
+			m.selectedContact = m.detailHistory[n-1]
+			m.detailHistory = m.detailHistory[:n-1]
+			return m, nil
+		}
+		m.currentView = m.detailParent
 		m.selectedContact = nil
 		
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// Jump to a person or related contact
+		if m.selectedContact != nil {
+			links := m.detailLinks(*m.selectedContact)
+			i := int(msg.String()[0] - '1')
+			if i < len(links) {
+				m.detailHistory = append(m.detailHistory, m.selectedContact)
+				m.selectedContact = links[i]
+			}
+		}
+		
 	case "d":
 		// Show interaction type selector
 		if m.selectedContact != nil {
@@ -61,8 +90,36 @@ func (m Model) updateDetail(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.editField = -1 // Start in field selection mode
 		}
 		
+	case "a":
+		// Archive, or bring back from the archive
+		if m.selectedContact != nil {
+			return m, m.archiveContact(*m.selectedContact, !m.selectedContact.IsArchived())
+		}
+		
 	case "x":
-		// TODO: Delete contact
+		// Delete contact, after confirming
+		if m.selectedContact != nil {
+			m.confirmDelete = true
+		}
+		
+	case "E":
+		// Export this contact as a vCard
+		if m.selectedContact != nil {
+			contact := *m.selectedContact
+			return m, exportVCards([]model.Contact{contact}, vcard.FileName(contact))
+		}
+		
+	case "H":
+		// Commits of this contact's file
+		return m.openGitHistory()
+		
+	case "u":
+		// Undo the last change
+		return m, m.undo()
+		
+	case "ctrl+r":
+		// Redo the last undone change
+		return m, m.redo()
 	}
 	return m, nil
 }
@@ -101,6 +158,43 @@ func (m Model) viewDetail() string {
 	b.WriteString(m.renderRelationshipInfo(contact))
 	b.WriteString("\n")
 	
+	// Birthday and other yearly dates
+	if events := contact.Events(); len(events) > 0 {
+		b.WriteString(sectionStyle.Render("Dates"))
+		b.WriteString("\n")
+		b.WriteString(m.renderEvents(events))
+		b.WriteString("\n")
+	}
+	
+	// Typed relationships, then contacts linked by label, numbered for jumping
+	jump := 0
+	if people := contact.People(m.contacts); len(people) > 0 {
+		b.WriteString(sectionStyle.Render("People"))
+		b.WriteString("\n")
+		b.WriteString(m.renderPeople(people, &jump))
+		b.WriteString("\n")
+	}
+	if related := contact.RelatedContacts(m.contacts); len(related) > 0 {
+		b.WriteString(sectionStyle.Render("Related"))
+		b.WriteString("\n")
+		b.WriteString(m.renderRelated(related, &jump))
+		b.WriteString("\n")
+	}
+	
+	// Follow-up and deadline dates and triggers
+	if contact.FollowUpDate != nil || contact.DeadlineDate != nil || len(contact.Triggers) > 0 {
+		b.WriteString(sectionStyle.Render("Scheduled"))
+		b.WriteString("\n")
+		b.WriteString(m.renderScheduled(contact))
+		b.WriteString("\n")
+	}
+	
+	// Why the contact is or isn't due
+	b.WriteString(sectionStyle.Render("Due Status"))
+	b.WriteString("\n")
+	b.WriteString(m.renderDueExplanation(contact))
+	b.WriteString("\n")
+	
 	// Contact History
 	b.WriteString(sectionStyle.Render("Contact History"))
 	b.WriteString("\n")
@@ -128,7 +222,15 @@ func (m Model) renderDetailHeader(contact model.Contact) string {
 	// Status indicators
 	var status []string
 	
-	if contact.IsOverdue() {
+	if contact.IsArchived() {
+		archived := "a Archived"
+		if contact.ArchivedAt != nil {
+			archived += " " + contact.ArchivedAt.Format("January 2, 2006")
+		}
+		status = append(status, headerColor.Render(archived))
+	} else if contact.IsSnoozed() {
+		status = append(status, snoozedColor.Render("z Snoozed until "+contact.SnoozedUntil.Format("January 2, 2006")))
+	} else if contact.IsOverdue() {
 		status = append(status, overdueColor.Render("● Overdue"))
 	} else if contact.NeedsAttention() {
 		status = append(status, attentionColor.Render("! Needs Attention"))
@@ -233,8 +335,25 @@ func (m Model) renderRelationshipInfo(contact model.Contact) string {
 	if contact.CustomFrequencyDays > 0 {
 		freqStr += " (custom)"
 	}
+	if s, ok := contact.SuggestFrequency(); ok {
+		freqStr += fmt.Sprintf(" → history suggests every %d days (R in the list to review)", s.Suggested)
+	}
 	lines = append(lines, m.renderField("Frequency", freqStr))
 	
+	// Health score and its parts
+	health := contact.Health()
+	healthStr := fmt.Sprintf("%d/100 (recency %.0f/%d, cadence %.0f/%d, mix %.0f/%d)", health.Score,
+		health.Recency*model.RecencyPoints, model.RecencyPoints,
+		health.Cadence*model.CadencePoints, model.CadencePoints,
+		health.Mix*model.MixPoints, model.MixPoints)
+	switch trend := contact.HealthTrend(); {
+	case trend > 0:
+		healthStr += fmt.Sprintf(" ↑%d in 30 days", trend)
+	case trend < 0:
+		healthStr += fmt.Sprintf(" ↓%d in 30 days", -trend)
+	}
+	lines = append(lines, m.renderField("Health", healthStr))
+	
 	// State
 	if contact.State != "" && contact.State != "ok" {
 		lines = append(lines, m.renderField("State", contact.State))
@@ -273,11 +392,26 @@ func (m Model) renderContactHistory(contact model.Contact) string {
 		lines = append(lines, m.renderField("Last Contacted", "Never"))
 	}
 	
+	// Next due date
+	if due, ok := contact.NextDue(); ok {
+		dueStr := due.Format("January 2, 2006")
+		days := model.DaysBetween(clock.Now(), due)
+		switch {
+		case days < 0:
+			dueStr += fmt.Sprintf(" (%d days overdue)", -days)
+		case days == 0:
+			dueStr += " (today)"
+		default:
+			dueStr += fmt.Sprintf(" (in %d days)", days)
+		}
+		lines = append(lines, m.renderField("Next Due", dueStr))
+	}
+	
 	// Bump information
 	if contact.LastBumpDate != nil {
 		bumpStr := contact.LastBumpDate.Format("January 2, 2006")
-		if contact.BumpCount > 0 {
-			bumpStr += fmt.Sprintf(" (%d bumps)", contact.BumpCount)
+		if bumps := contact.BumpsSinceContact(); bumps > 0 {
+			bumpStr += fmt.Sprintf(" (%d bumps since last contact)", bumps)
 		}
 		lines = append(lines, m.renderField("Last Reviewed", bumpStr))
 	}
@@ -293,6 +427,145 @@ func (m Model) renderContactHistory(contact model.Contact) string {
 	return strings.Join(lines, "\n")
 }
 
+// renderScheduled renders follow-up, deadline and trigger dates relative to today
+func (m Model) renderScheduled(contact model.Contact) string {
+	var lines []string
+	if contact.FollowUpDate != nil {
+		lines = append(lines, m.renderField("Follow-up", formatRelativeDate(*contact.FollowUpDate)))
+	}
+	if contact.DeadlineDate != nil {
+		lines = append(lines, m.renderField("Deadline", formatRelativeDate(*contact.DeadlineDate)))
+	}
+	for _, t := range contact.Triggers {
+		lines = append(lines, m.renderField("Trigger", t.Reason+" • due "+formatRelativeDate(t.Date)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderEvents renders yearly dates with the age or years and the next occurrence
+func (m Model) renderEvents(events []model.Event) string {
+	var lines []string
+	for _, e := range events {
+		date := e.In(2000).Format("January 2")
+		if e.Year > 0 {
+			date = e.In(e.Year).Format("January 2, 2006")
+		}
+		if years, ok := e.YearsNow(); ok {
+			if e.Name == "birthday" {
+				date += fmt.Sprintf(" (age %d)", years)
+			} else {
+				date += fmt.Sprintf(" (%d years)", years)
+			}
+		}
+		
+		next := e.Next(clock.Now())
+		date += " • next " + formatRelativeDate(next.Date)
+		lines = append(lines, m.renderField(e.Label(), date))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// detailLinks returns the contacts the number keys jump to, in the order
+// the People and Related sections list them
+func (m Model) detailLinks(contact model.Contact) []*model.Contact {
+	var links []*model.Contact
+	for _, p := range contact.People(m.contacts) {
+		if p.Contact != nil {
+			links = append(links, p.Contact)
+		}
+	}
+	for _, r := range contact.RelatedContacts(m.contacts) {
+		if r.Contact != nil {
+			links = append(links, r.Contact)
+		}
+	}
+	return links
+}
+
+// jumpKey returns the number shown beside a linked contact, counting up from
+// jump; only the first nine get one
+func jumpKey(linked bool, jump *int) string {
+	if !linked {
+		return "   "
+	}
+	*jump++
+	if *jump > 9 {
+		return "   "
+	}
+	return fmt.Sprintf("%d. ", *jump)
+}
+
+// renderPeople lists typed relationships, e.g. "Manager of  Alice Smith"
+func (m Model) renderPeople(people []model.Person, jump *int) string {
+	var lines []string
+	for _, p := range people {
+		key := jumpKey(p.Contact != nil, jump)
+		phrase := labelStyle.Render(fmt.Sprintf("%-15s", p.Phrase()))
+		if p.Contact == nil {
+			lines = append(lines, "  "+key+phrase+" "+emptyStyle.Render(p.Label+" (no contact has this label)"))
+			continue
+		}
+		line := p.Contact.Title + " " + p.Label
+		if p.Contact.Company != "" {
+			line += " • " + p.Contact.Company
+		}
+		lines = append(lines, "  "+labelStyle.Render(key)+phrase+" "+valueStyle.Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRelated lists related contacts
+func (m Model) renderRelated(related []model.Related, jump *int) string {
+	var lines []string
+	for _, r := range related {
+		key := jumpKey(r.Contact != nil, jump)
+		if r.Contact == nil {
+			lines = append(lines, "  "+key+emptyStyle.Render(r.Label+" (no contact has this label)"))
+			continue
+		}
+		line := r.Contact.Title
+		if r.Label != "" {
+			line += " " + r.Label
+		}
+		if r.Contact.Company != "" {
+			line += " • " + r.Contact.Company
+		}
+		if r.Incoming {
+			line += headerColor.Render(" (lists this contact)")
+		}
+		lines = append(lines, "  "+labelStyle.Render(key)+valueStyle.Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatRelativeDate formats a date with how far it is from today
+func formatRelativeDate(date time.Time) string {
+	str := date.Format("January 2, 2006")
+	days := model.DaysBetween(clock.Now(), date)
+	switch {
+	case days < -1:
+		str += fmt.Sprintf(" (%d days ago)", -days)
+	case days == -1:
+		str += " (yesterday)"
+	case days == 0:
+		str += " (today)"
+	case days == 1:
+		str += " (tomorrow)"
+	default:
+		str += fmt.Sprintf(" (in %d days)", days)
+	}
+	return str
+}
+
+// renderDueExplanation renders the reasoning behind the contact's due date
+func (m Model) renderDueExplanation(contact model.Contact) string {
+	var lines []string
+	for _, reason := range contact.DueExplanation() {
+		lines = append(lines, "  "+valueStyle.Render(reason))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // renderContactContent renders the markdown content
 func (m Model) renderContactContent(contact model.Contact) string {
 	content := strings.TrimSpace(contact.Content)
@@ -320,13 +593,29 @@ func (m Model) renderField(label, value string) string {
 
 // renderDetailFooter renders the footer with available actions
 func (m Model) renderDetailFooter() string {
+	if m.confirmDelete && m.selectedContact != nil {
+		prompt := fmt.Sprintf("Move %s to the trash? (y/n)", m.selectedContact.Title)
+		return "\n" + attentionColor.Render(prompt)
+	}
+	
+	archive := "a:archive"
+	if m.selectedContact != nil && m.selectedContact.IsArchived() {
+		archive = "a:unarchive"
+	}
 	keys := []string{
 		"d:mark contacted",
 		"b:bump",
 		"e:edit",
+		"1-9:open person",
+		archive,
 		"x:delete",
+		"E:export vcf",
+		"u/^r:undo/redo",
 		"esc:back",
 	}
+	if m.git != nil {
+		keys = append(keys[:len(keys)-1], "H:history", "esc:back")
+	}
 	
 	return "\n" + headerColor.Render(strings.Join(keys, " • "))
 }
\ No newline at end of file
@@ -3,9 +3,11 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
 )
 
@@ -21,6 +23,13 @@ const (
 	fieldContactStyle
 	fieldState
 	fieldTags
+	fieldFollowUp
+	fieldDeadline
+	fieldBirthday
+	fieldDates
+	fieldLabel
+	fieldRelated
+	fieldPeople
 	fieldCount
 )
 
@@ -35,6 +44,103 @@ var fieldLabels = []string{
 	"Style",
 	"State",
 	"Tags",
+	"Follow-up",
+	"Deadline",
+	"Birthday",
+	"Dates",
+	"Label",
+	"Related",
+	"People",
+}
+
+// fieldHotkeys selects each field in the edit and create views
+var fieldHotkeys = []string{"n", "e", "p", "c", "r", "l", "t", "s", "S", "T", "f", "d", "b", "a", "L", "R", "P"}
+
+// isDateField reports whether a field takes a date
+func isDateField(field int) bool {
+	return field == fieldFollowUp || field == fieldDeadline
+}
+
+// isLabelField reports whether a field takes labels
+func isLabelField(field int) bool {
+	return field == fieldLabel || field == fieldRelated || field == fieldPeople
+}
+
+// isEventField reports whether a field takes yearly dates
+func isEventField(field int) bool {
+	return field == fieldBirthday || field == fieldDates
+}
+
+// parseEditBirthday validates a birthday in MM-DD or YYYY-MM-DD form
+func parseEditBirthday(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	e, err := model.ParseEvent("birthday", value)
+	if err != nil {
+		return "", err
+	}
+	return e.String(), nil
+}
+
+// parseEditDates parses named dates written as "name: date" pairs separated
+// by commas, e.g. "anniversary: 2010-06-12, work anniversary: 09-01"
+func parseEditDates(value string) (map[string]string, error) {
+	dates := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, date, found := strings.Cut(part, ":")
+		name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid date %q: use name: MM-DD or name: YYYY-MM-DD", part)
+		}
+		e, err := model.ParseEvent(name, date)
+		if err != nil {
+			return nil, err
+		}
+		dates[name] = e.String()
+	}
+	if len(dates) == 0 {
+		return nil, nil
+	}
+	return dates, nil
+}
+
+// formatEditDates formats named dates for the edit form
+func formatEditDates(contact model.Contact) string {
+	var parts []string
+	for _, e := range contact.Events() {
+		if e.Name != "birthday" {
+			parts = append(parts, strings.ReplaceAll(e.Name, "_", " ")+": "+contact.Dates[e.Name])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseEditDate parses a date field value. Empty clears the date; otherwise
+// it takes YYYY-MM-DD or a duration from today such as 10d, 2w, 3m.
+func parseEditDate(label, value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := model.ParseDateInput(value, clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", label, err)
+	}
+	return &date, nil
+}
+
+// formatEditDate formats a date for the edit form
+func formatEditDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
 }
 
 // updateEdit handles input in edit view
@@ -82,6 +188,20 @@ func (m Model) updateEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.editField = fieldState
 		case "T":
 			m.editField = fieldTags
+		case "f":
+			m.editField = fieldFollowUp
+		case "d":
+			m.editField = fieldDeadline
+		case "b":
+			m.editField = fieldBirthday
+		case "a":
+			m.editField = fieldDates
+		case "L":
+			m.editField = fieldLabel
+		case "R":
+			m.editField = fieldRelated
+		case "P":
+			m.editField = fieldPeople
 		}
 	} else {
 		// Field editing mode
@@ -99,7 +219,7 @@ func (m Model) updateEdit(msg tea.KeyMsg) (Model, tea.Cmd) {
 		case "backspace":
 			// Delete character from current field
 			if len(m.editValues[m.editField]) > 0 {
-				m.editValues[m.editField] = m.editValues[m.editField][:len(m.editValues[m.editField])-1]
+				m.editValues[m.editField] = dropLastRune(m.editValues[m.editField])
 			}
 
 		default:
@@ -296,8 +416,6 @@ func (m Model) renderFieldSelectionView() string {
 	var b strings.Builder
 	
 	// Show all fields with their current values and hotkeys
-	fieldHotkeys := []string{"n", "e", "p", "c", "r", "l", "t", "s", "S", "T"}
-	
 	for i := 0; i < fieldCount; i++ {
 		label := fieldLabels[i]
 		value := m.editValues[i]
@@ -348,6 +466,16 @@ func (m Model) renderFieldEditingView() string {
 		displayValue := value + "█"
 		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render(displayValue))
 		b.WriteString("\n\n")
+		if isDateField(m.editField) {
+			b.WriteString(renderDateHint())
+			b.WriteString("\n\n")
+		} else if isEventField(m.editField) {
+			b.WriteString(renderEventHint(m.editField))
+			b.WriteString("\n\n")
+		} else if isLabelField(m.editField) {
+			b.WriteString(renderLabelHint(m.editField))
+			b.WriteString("\n\n")
+		}
 	}
 	
 	// Instructions
@@ -357,6 +485,34 @@ func (m Model) renderFieldEditingView() string {
 	return b.String()
 }
 
+// renderDateHint explains the accepted date formats
+func renderDateHint() string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true).
+		Render("YYYY-MM-DD or from today: 3d, 2w, 1m • empty to clear")
+}
+
+// renderEventHint explains the accepted yearly date formats
+func renderEventHint(field int) string {
+	hint := "MM-DD, or YYYY-MM-DD to show the age • empty to clear"
+	if field == fieldDates {
+		hint = "name: MM-DD or name: YYYY-MM-DD, comma separated, e.g. anniversary: 2010-06-12"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true).Render(hint)
+}
+
+// renderLabelHint explains the label fields
+func renderLabelHint(field int) string {
+	hint := "A unique handle such as @mentor • empty to clear"
+	switch field {
+	case fieldRelated:
+		hint = "Labels of related contacts, space separated, e.g. @mentor @alex"
+	case fieldPeople:
+		hint = "What this contact is to others: type @label, comma separated, e.g. manager @alex, spouse @sam\n" +
+			"Types: " + strings.Join(model.RelationshipTypes(), ", ")
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true).Render(hint)
+}
+
 // initializeEditValues populates edit form with current contact values
 func (m *Model) initializeEditValues(contact model.Contact) {
 	m.editValues = make([]string, fieldCount)
@@ -379,4 +535,12 @@ func (m *Model) initializeEditValues(contact model.Contact) {
 		}
 	}
 	m.editValues[fieldTags] = strings.Join(tags, " ")
+	
+	m.editValues[fieldFollowUp] = formatEditDate(contact.FollowUpDate)
+	m.editValues[fieldDeadline] = formatEditDate(contact.DeadlineDate)
+	m.editValues[fieldBirthday] = contact.Birthday
+	m.editValues[fieldDates] = formatEditDates(contact)
+	m.editValues[fieldLabel] = contact.Label
+	m.editValues[fieldRelated] = strings.Join(contact.RelatedContactLabels, " ")
+	m.editValues[fieldPeople] = model.FormatRelationships(contact.Relationships)
 }
\ No newline at end of file
@@ -7,6 +7,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
 )
 
 var (
@@ -22,82 +24,302 @@ var (
 		Bold(true)
 )
 
+// filterSet holds the active list filters. Categories combine with AND;
+// values within a category combine with OR, so "work, overdue, #oss" means
+// work AND overdue AND tagged oss. Archived contacts are left out unless
+// archived is set.
+type filterSet struct {
+	types    []string
+	states   []string
+	statuses []string
+	tags     []string
+	archived bool
+}
+
+// isEmpty reports whether no filters are active
+func (f filterSet) isEmpty() bool {
+	return len(f.types) == 0 && len(f.states) == 0 && len(f.statuses) == 0 && len(f.tags) == 0 && !f.archived
+}
+
+// matches reports whether a contact passes every active filter category
+func (f filterSet) matches(contact model.Contact) bool {
+	if contact.IsArchived() && !f.archived {
+		return false
+	}
+	
+	if len(f.types) > 0 && !containsString(f.types, string(contact.RelationshipType)) {
+		return false
+	}
+	
+	if len(f.states) > 0 && !containsString(f.states, contact.State) {
+		return false
+	}
+	
+	if len(f.statuses) > 0 {
+		matched := false
+		for _, status := range f.statuses {
+			if contactHasStatus(contact, status) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	
+	for _, tag := range f.tags {
+		if !containsFold(contact.Tags, tag) {
+			return false
+		}
+	}
+	
+	return true
+}
+
+// describe returns a short summary of the active filters for headers
+func (f filterSet) describe() string {
+	var parts []string
+	if len(f.types) > 0 {
+		parts = append(parts, "type: "+strings.Join(f.types, "|"))
+	}
+	if len(f.states) > 0 {
+		parts = append(parts, "state: "+strings.Join(f.states, "|"))
+	}
+	if len(f.statuses) > 0 {
+		var labels []string
+		for _, status := range f.statuses {
+			labels = append(labels, statusLabel(status))
+		}
+		parts = append(parts, "status: "+strings.Join(labels, "|"))
+	}
+	for _, tag := range f.tags {
+		parts = append(parts, "tag: "+tag)
+	}
+	if f.archived {
+		parts = append(parts, "with archived")
+	}
+	return strings.Join(parts, " • ")
+}
+
+// contactHasStatus checks a contact against a status filter value
+func contactHasStatus(contact model.Contact, status string) bool {
+	switch status {
+	case "overdue":
+		return contact.IsOverdue()
+	case "needsAttention":
+		return contact.NeedsAttention()
+	case "ok":
+		return contact.IsWithinThreshold()
+	case "snoozed":
+		return contact.IsSnoozed()
+	}
+	return false
+}
+
+// statusLabel returns the display name of a status filter value
+func statusLabel(status string) string {
+	switch status {
+	case "needsAttention":
+		return "due soon"
+	case "ok":
+		return "good timing"
+	}
+	return status
+}
+
+// toggleString adds value to list, or removes it if already present
+func toggleString(list []string, value string) []string {
+	for i, v := range list {
+		if v == value {
+			return append(list[:i:i], list[i+1:]...)
+		}
+	}
+	return append(list, value)
+}
+
+// containsString checks if a value exists in the slice
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold checks if a value exists in the slice, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
 // updateFilter handles input in filter popup
 func (m Model) updateFilter(msg tea.KeyMsg) (Model, tea.Cmd) {
+	// Typing a tag to filter on
+	if m.filterTagInput {
+		switch msg.Type {
+		case tea.KeyEscape:
+			m.filterTagInput = false
+			m.filterTagValue = ""
+		case tea.KeyEnter:
+			tag := strings.TrimPrefix(strings.TrimSpace(m.filterTagValue), "#")
+			m.filterTagInput = false
+			m.filterTagValue = ""
+			if tag != "" {
+				return m.toggleFilter("tag", tag)
+			}
+		case tea.KeyBackspace:
+			if len(m.filterTagValue) > 0 {
+				m.filterTagValue = dropLastRune(m.filterTagValue)
+			}
+		case tea.KeyRunes:
+			m.filterTagValue += string(msg.Runes)
+		}
+		return m, nil
+	}
+	
 	switch msg.String() {
-	case "esc", "q":
-		// Close filter menu without changes
+	case "esc", "q", "enter":
+		// Close filter menu, keeping the filters
 		m.showFilterPopup = false
+		if !m.filters.isEmpty() {
+			m.message = "Filtering by " + m.filters.describe()
+			return m, clearMessageAfter(3 * time.Second)
+		}
 		return m, nil
 	
 	// Clear all filters
 	case "a":
-		m.filterType = ""
-		m.filterState = ""
-		m.filterStatus = ""
+		m.filters = filterSet{}
+		m.activeView = ""
 		m.applyFilters()
 		m.showFilterPopup = false
 		m.message = "Cleared all filters"
 		return m, clearMessageAfter(3 * time.Second)
 	
+	// Tag filter
+	case "#":
+		m.filterTagInput = true
+		m.filterTagValue = ""
+	
 	// Type filters
 	case "f": // family
-		return m.applyFilterAndReturn("type", "family", "Filtered to family")
+		return m.toggleFilter("type", "family")
 	case "c": // close
-		return m.applyFilterAndReturn("type", "close", "Filtered to close")
+		return m.toggleFilter("type", "close")
 	case "n": // network
-		return m.applyFilterAndReturn("type", "network", "Filtered to network")
+		return m.toggleFilter("type", "network")
 	case "w": // work
-		return m.applyFilterAndReturn("type", "work", "Filtered to work")
+		return m.toggleFilter("type", "work")
 	case "r": // recruiters
-		return m.applyFilterAndReturn("type", "recruiters", "Filtered to recruiters")
+		return m.toggleFilter("type", "recruiters")
 	case "p": // providers
-		return m.applyFilterAndReturn("type", "providers", "Filtered to providers")
+		return m.toggleFilter("type", "providers")
 	case "s": // social
-		return m.applyFilterAndReturn("type", "social", "Filtered to social")
+		return m.toggleFilter("type", "social")
 	
 	// State filters (using uppercase to avoid conflicts)
 	case "F": // followup
-		return m.applyFilterAndReturn("state", "followup", "Filtered to follow up")
+		return m.toggleFilter("state", "followup")
 	case "P": // ping
-		return m.applyFilterAndReturn("state", "ping", "Filtered to ping")
+		return m.toggleFilter("state", "ping")
 	case "S": // scheduled
-		return m.applyFilterAndReturn("state", "scheduled", "Filtered to scheduled")
+		return m.toggleFilter("state", "scheduled")
 	case "T": // timeout
-		return m.applyFilterAndReturn("state", "timeout", "Filtered to timeout")
+		return m.toggleFilter("state", "timeout")
 	
 	// Status filters
 	case "o": // overdue
-		return m.applyFilterAndReturn("status", "overdue", "Filtered to overdue")
+		return m.toggleFilter("status", "overdue")
 	case "d": // due soon (needs attention)
-		return m.applyFilterAndReturn("status", "needsAttention", "Filtered to due soon")
+		return m.toggleFilter("status", "needsAttention")
 	case "g": // good
-		return m.applyFilterAndReturn("status", "ok", "Filtered to good timing")
+		return m.toggleFilter("status", "ok")
+	case "z": // snoozed
+		return m.toggleFilter("status", "snoozed")
+	
+	// Archived contacts are hidden until shown
+	case "A":
+		m.filters.archived = !m.filters.archived
+		m.activeView = ""
+		m.applyFilters()
+		m.cursor = 0
 	}
 	
 	return m, nil
 }
 
-// applyFilterAndReturn applies a single filter and returns to list
-func (m Model) applyFilterAndReturn(filterType, value, message string) (Model, tea.Cmd) {
-	// Clear all filters first (single filter at a time)
-	m.filterType = ""
-	m.filterState = ""
-	m.filterStatus = ""
-	
-	// Apply the selected filter
-	switch filterType {
+// toggleFilter switches one filter value on or off and keeps the popup open
+// so further filters can be stacked
+func (m Model) toggleFilter(category, value string) (Model, tea.Cmd) {
+	switch category {
 	case "type":
-		m.filterType = value
+		m.filters.types = toggleString(m.filters.types, value)
 	case "state":
-		m.filterState = value
+		m.filters.states = toggleString(m.filters.states, value)
 	case "status":
-		m.filterStatus = value
+		m.filters.statuses = toggleString(m.filters.statuses, value)
+	case "tag":
+		m.filters.tags = toggleString(m.filters.tags, strings.ToLower(value))
 	}
 	
+	m.activeView = ""
 	m.applyFilters()
-	m.showFilterPopup = false
-	m.message = message
+	m.cursor = 0
+	return m, nil
+}
+
+// applyView restores the search and filters saved in a view
+func (m Model) applyView(view config.View) (Model, tea.Cmd) {
+	m.searchQuery = view.Query
+	m.filters = filterSet{
+		types:    append([]string(nil), view.Types...),
+		states:   append([]string(nil), view.States...),
+		statuses: append([]string(nil), view.Statuses...),
+		tags:     append([]string(nil), view.Tags...),
+		archived: view.Archived,
+	}
+	m.activeView = view.Name
+	m.applyFilters()
+	m.cursor = 0
+	m.message = "View: " + view.Name
+	return m, clearMessageAfter(3 * time.Second)
+}
+
+// saveCurrentView stores the current search and filters as a named view
+func (m Model) saveCurrentView(name string) (Model, tea.Cmd) {
+	if m.cfg == nil {
+		return m, nil
+	}
+	
+	view := config.View{
+		Name:     name,
+		Query:    m.searchQuery,
+		Types:    append([]string(nil), m.filters.types...),
+		States:   append([]string(nil), m.filters.states...),
+		Statuses: append([]string(nil), m.filters.statuses...),
+		Tags:     append([]string(nil), m.filters.tags...),
+		Archived: m.filters.archived,
+	}
+	m.cfg.SetView(view)
+	if err := config.SaveViews(m.cfg); err != nil {
+		m.message = fmt.Sprintf("Failed to save view: %v", err)
+		return m, clearMessageAfter(3 * time.Second)
+	}
+	
+	m.activeView = name
+	for i, v := range m.cfg.Views {
+		if v.Name == name && i < 9 {
+			m.message = fmt.Sprintf("Saved view %q (press %d)", name, i+1)
+			return m, clearMessageAfter(3 * time.Second)
+		}
+	}
+	m.message = fmt.Sprintf("Saved view %q", name)
 	return m, clearMessageAfter(3 * time.Second)
 }
 
@@ -112,25 +334,10 @@ func (m Model) renderFilterPopup() string {
 	b.WriteString(titleStyle.Render("Filter Contacts"))
 	b.WriteString("\n\n")
 	
-	// Currently active filter
-	if m.filterType != "" || m.filterState != "" || m.filterStatus != "" {
+	// Currently active filters
+	if !m.filters.isEmpty() {
 		b.WriteString(filterLabelStyle.Render("Active: "))
-		if m.filterType != "" {
-			b.WriteString(filterActiveStyle.Render(fmt.Sprintf("Type: %s", m.filterType)))
-		} else if m.filterState != "" {
-			b.WriteString(filterActiveStyle.Render(fmt.Sprintf("State: %s", m.filterState)))
-		} else if m.filterStatus != "" {
-			statusDisplay := m.filterStatus
-			switch m.filterStatus {
-			case "overdue":
-				statusDisplay = "overdue"
-			case "needsAttention":
-				statusDisplay = "due soon"
-			case "ok":
-				statusDisplay = "good timing"
-			}
-			b.WriteString(filterActiveStyle.Render(fmt.Sprintf("Status: %s", statusDisplay)))
-		}
+		b.WriteString(filterActiveStyle.Render(m.filters.describe()))
 		b.WriteString("\n\n")
 	}
 	
@@ -139,14 +346,31 @@ func (m Model) renderFilterPopup() string {
 	// Clear option
 	b.WriteString(fmt.Sprintf("  %s Clear all filters\n\n", hotkeyStyle.Render("(a)")))
 	
-	// Type section
-	b.WriteString(filterLabelStyle.Render("By Type:"))
-	b.WriteString("\n")
-	typeOptions := []struct {
+	type option struct {
 		key   string
 		value string
 		label string
-	}{
+	}
+	renderOptions := func(options []option, active []string) {
+		for _, opt := range options {
+			if containsString(active, opt.value) {
+				b.WriteString(fmt.Sprintf("  %s %s %s\n", 
+					hotkeyStyle.Render("("+opt.key+")"),
+					filterActiveStyle.Render("●"),
+					filterActiveStyle.Render(opt.label)))
+			} else {
+				b.WriteString(fmt.Sprintf("  %s   %s\n", 
+					hotkeyStyle.Render("("+opt.key+")"),
+					opt.label))
+			}
+		}
+		b.WriteString("\n")
+	}
+	
+	// Type section
+	b.WriteString(filterLabelStyle.Render("By Type:"))
+	b.WriteString("\n")
+	renderOptions([]option{
 		{"f", "family", "Family"},
 		{"c", "close", "Close"},
 		{"n", "network", "Network"},
@@ -154,83 +378,66 @@ func (m Model) renderFilterPopup() string {
 		{"r", "recruiters", "Recruiters"},
 		{"p", "providers", "Providers"},
 		{"s", "social", "Social"},
-	}
-	
-	for _, opt := range typeOptions {
-		selected := m.filterType == opt.value
-		if selected {
-			b.WriteString(fmt.Sprintf("  %s %s %s\n", 
-				hotkeyStyle.Render("("+opt.key+")"),
-				filterActiveStyle.Render("●"),
-				filterActiveStyle.Render(opt.label)))
-		} else {
-			b.WriteString(fmt.Sprintf("  %s   %s\n", 
-				hotkeyStyle.Render("("+opt.key+")"),
-				opt.label))
-		}
-	}
-	
-	b.WriteString("\n")
+	}, m.filters.types)
 	
 	// State section
 	b.WriteString(filterLabelStyle.Render("By State:"))
 	b.WriteString("\n")
-	stateOptions := []struct {
-		key   string
-		value string
-		label string
-	}{
+	renderOptions([]option{
 		{"F", "followup", "Follow Up"},
 		{"P", "ping", "Ping"},
 		{"S", "scheduled", "Scheduled"},
 		{"T", "timeout", "Timeout"},
-	}
-	
-	for _, opt := range stateOptions {
-		selected := m.filterState == opt.value
-		if selected {
-			b.WriteString(fmt.Sprintf("  %s %s %s\n", 
-				hotkeyStyle.Render("("+opt.key+")"),
-				filterActiveStyle.Render("●"),
-				filterActiveStyle.Render(opt.label)))
-		} else {
-			b.WriteString(fmt.Sprintf("  %s   %s\n", 
-				hotkeyStyle.Render("("+opt.key+")"),
-				opt.label))
-		}
-	}
-	
-	b.WriteString("\n")
+	}, m.filters.states)
 	
 	// Status section
 	b.WriteString(filterLabelStyle.Render("By Status:"))
 	b.WriteString("\n")
-	statusOptions := []struct {
-		key   string
-		value string
-		label string
-	}{
+	renderOptions([]option{
 		{"o", "overdue", "Overdue"},
 		{"d", "needsAttention", "Due Soon"},
 		{"g", "ok", "Good Timing"},
+		{"z", "snoozed", "Snoozed"},
+	}, m.filters.statuses)
+	
+	// Archived contacts
+	b.WriteString(filterLabelStyle.Render("Archived:"))
+	b.WriteString("\n")
+	var archived []string
+	if m.filters.archived {
+		archived = []string{"show"}
+	}
+	renderOptions([]option{
+		{"A", "show", "Show archived contacts"},
+	}, archived)
+	
+	// Tag section
+	b.WriteString(filterLabelStyle.Render("By Tag:"))
+	b.WriteString("\n")
+	if m.filterTagInput {
+		b.WriteString(fmt.Sprintf("  %s #%s█\n", hotkeyStyle.Render("(#)"), m.filterTagValue))
+	} else {
+		b.WriteString(fmt.Sprintf("  %s   Add or remove a tag\n", hotkeyStyle.Render("(#)")))
 	}
+	for _, tag := range m.filters.tags {
+		b.WriteString(fmt.Sprintf("        %s %s\n", filterActiveStyle.Render("●"), filterActiveStyle.Render("#"+tag)))
+	}
+	b.WriteString("\n")
 	
-	for _, opt := range statusOptions {
-		selected := m.filterStatus == opt.value
-		if selected {
-			b.WriteString(fmt.Sprintf("  %s %s %s\n", 
-				hotkeyStyle.Render("("+opt.key+")"),
-				filterActiveStyle.Render("●"),
-				filterActiveStyle.Render(opt.label)))
-		} else {
-			b.WriteString(fmt.Sprintf("  %s   %s\n", 
-				hotkeyStyle.Render("("+opt.key+")"),
-				opt.label))
+	// Saved views
+	if m.cfg != nil && len(m.cfg.Views) > 0 {
+		b.WriteString(filterLabelStyle.Render("Saved Views (from list):"))
+		b.WriteString("\n")
+		for i, view := range m.cfg.Views {
+			if i >= 9 {
+				break
+			}
+			b.WriteString(fmt.Sprintf("  %s   %s\n", hotkeyStyle.Render(fmt.Sprintf("(%d)", i+1)), view.Name))
 		}
+		b.WriteString("\n")
 	}
 	
-	b.WriteString("\n")
-	b.WriteString(hotkeyStyle.Render("Esc to cancel"))
+	b.WriteString(hotkeyStyle.Render("Filters stack • press again to remove • Enter/Esc to close"))
 	
 	// Pad to fill screen
 	content := b.String()
@@ -241,4 +448,3 @@ func (m Model) renderFilterPopup() string {
 	
 	return strings.Join(lines, "\n")
 }
-
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/gitsync"
+)
+
+// gitStatusMsg carries the repository state for the header
+type gitStatusMsg struct {
+	status gitsync.Status
+	err    error
+}
+
+// gitLogMsg carries the commits that touched the open contact's file
+type gitLogMsg struct {
+	commits []gitsync.FileCommit
+	err     error
+}
+
+// gitDiffMsg carries what one commit changed in the file
+type gitDiffMsg struct {
+	hash string
+	diff string
+	err  error
+}
+
+// checkGit returns a command that reads the repository state, or nil when
+// git is off
+func (m Model) checkGit() tea.Cmd {
+	if m.git == nil {
+		return nil
+	}
+	repo := m.git.Repo()
+	return func() tea.Msg {
+		status, err := repo.Status()
+		return gitStatusMsg{status: status, err: err}
+	}
+}
+
+// renderGitStatus describes the repository state for the header: the
+// branch, files not yet committed and commits not yet pushed
+func (m Model) renderGitStatus() string {
+	if m.git == nil || m.gitStatus == nil {
+		return ""
+	}
+	s := *m.gitStatus
+	parts := []string{"git " + s.Branch}
+	if s.Dirty > 0 {
+		parts = append(parts, fmt.Sprintf("✎%d", s.Dirty))
+	}
+	if s.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("↑%d", s.Ahead))
+	}
+	if s.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("↓%d", s.Behind))
+	}
+	if s.Dirty == 0 && s.Ahead == 0 && s.Behind == 0 {
+		parts = append(parts, "✓")
+	}
+	return strings.Join(parts, " ")
+}
+
+// openGitHistory shows the commits of the open contact's file
+func (m Model) openGitHistory() (Model, tea.Cmd) {
+	if m.selectedContact == nil {
+		return m, nil
+	}
+	if m.git == nil {
+		m.message = "Git is off: set enabled = true under [git] in the config"
+		return m, clearMessageAfter(3 * time.Second)
+	}
+	m.gitLog = nil
+	m.gitLogCursor = 0
+	m.gitDiff = ""
+	m.gitDiffScroll = 0
+	m.currentView = ViewGitHistory
+
+	repo := m.git.Repo()
+	path := m.selectedContact.FilePath
+	return m, func() tea.Msg {
+		commits, err := repo.Log(path)
+		return gitLogMsg{commits: commits, err: err}
+	}
+}
+
+// loadGitDiff returns a command that reads the selected commit's diff
+func (m Model) loadGitDiff() tea.Cmd {
+	if m.gitLogCursor >= len(m.gitLog) || m.selectedContact == nil {
+		return nil
+	}
+	repo := m.git.Repo()
+	hash := m.gitLog[m.gitLogCursor].Hash
+	path := m.selectedContact.FilePath
+	return func() tea.Msg {
+		diff, err := repo.Diff(hash, path)
+		return gitDiffMsg{hash: hash, diff: diff, err: err}
+	}
+}
+
+// updateGitHistory handles input in a contact's commit history
+func (m Model) updateGitHistory(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.currentView = ViewDetail
+		m.gitLog = nil
+		m.gitDiff = ""
+	case "j", "down":
+		if m.gitLogCursor < len(m.gitLog)-1 {
+			m.gitLogCursor++
+			m.gitDiffScroll = 0
+			return m, m.loadGitDiff()
+		}
+	case "k", "up":
+		if m.gitLogCursor > 0 {
+			m.gitLogCursor--
+			m.gitDiffScroll = 0
+			return m, m.loadGitDiff()
+		}
+	case "ctrl+d", " ":
+		m.gitDiffScroll += 10
+	case "ctrl+u":
+		m.gitDiffScroll -= 10
+		if m.gitDiffScroll < 0 {
+			m.gitDiffScroll = 0
+		}
+	}
+	return m, nil
+}
+
+// viewGitHistory renders the commits of a contact's file above the diff of
+// the selected one
+func (m Model) viewGitHistory() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	title := "History"
+	if m.selectedContact != nil {
+		title += ": " + m.selectedContact.Title
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString(headerColor.Render(fmt.Sprintf("  %d commits", len(m.gitLog))))
+	b.WriteString("\n")
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("82")).
+			Bold(true)
+		b.WriteString(messageStyle.Render("→ " + m.message))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	// Commits, scrolled to keep the cursor in view
+	const listHeight = 8
+	var lines []string
+	if len(m.gitLog) == 0 {
+		lines = append(lines, emptyStyle.Render("  No commits for this contact"))
+	}
+	start := 0
+	if m.gitLogCursor >= listHeight {
+		start = m.gitLogCursor - listHeight + 1
+	}
+	for i := start; i < len(m.gitLog) && i < start+listHeight; i++ {
+		c := m.gitLog[i]
+		cursor, style := "  ", baseColor
+		if i == m.gitLogCursor {
+			cursor, style = "> ", selectedColor
+		}
+		lines = append(lines, cursor+style.Render(fmt.Sprintf("%s  %s  %s", c.Hash, c.Date, c.Subject)))
+	}
+	lines = append(lines, headerColor.Render(strings.Repeat("─", m.width)))
+
+	// The selected commit's diff fills the rest
+	height := m.height - len(lines) - 5
+	if m.message != "" {
+		height--
+	}
+	diff := strings.Split(strings.TrimRight(m.gitDiff, "\n"), "\n")
+	scroll := min(m.gitDiffScroll, max(len(diff)-1, 0))
+	for i := scroll; i < len(diff) && i < scroll+height; i++ {
+		lines = append(lines, renderDiffLine(diff[i]))
+	}
+	for len(lines) < m.height-5 {
+		lines = append(lines, "")
+	}
+
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n\n")
+	keys := []string{"j/k:commit", "space/ctrl+u:scroll diff", "esc:back"}
+	b.WriteString(headerColor.Render(strings.Join(keys, " • ")))
+	return b.String()
+}
+
+// renderDiffLine colours a line of a unified diff
+func renderDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		return headerColor.Render(line)
+	case strings.HasPrefix(line, "+"):
+		return goodColor.Render(line)
+	case strings.HasPrefix(line, "-"):
+		return overdueColor.Render(line)
+	case strings.HasPrefix(line, "@@"):
+		return snoozedColor.Render(line)
+	}
+	return baseColor.Render(line)
+}
@@ -111,7 +111,7 @@ func (m Model) updateInteractionType(msg tea.KeyMsg) (Model, tea.Cmd) {
 
 		case "backspace":
 			if len(m.interactionNote) > 0 {
-				m.interactionNote = m.interactionNote[:len(m.interactionNote)-1]
+				m.interactionNote = dropLastRune(m.interactionNote)
 			}
 
 		default:
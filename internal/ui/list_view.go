@@ -3,9 +3,12 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
 )
 
@@ -17,8 +20,14 @@ var (
 	overdueColor  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
 	attentionColor = lipgloss.NewStyle().Foreground(lipgloss.Color("226"))
 	goodColor     = lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	snoozedColor  = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	matchColor    = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true).Underline(true)
+	snippetColor  = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
 )
 
+// healthTrendThreshold is the change in health score that shows as a trend
+const healthTrendThreshold = 5
+
 // updateList handles input in list view
 func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.String() {
@@ -58,9 +67,35 @@ func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
 	case "enter":
 		if m.cursor < len(m.filtered) {
 			m.selectedContact = &m.filtered[m.cursor]
+			m.detailParent = m.currentView
+			m.detailHistory = nil
 			m.currentView = ViewDetail
 		}
 		
+	case "A":
+		// Open the agenda
+		m.agendaCursor = 0
+		m.currentView = ViewAgenda
+		
+	case "R":
+		// Review frequency suggestions
+		m.suggestCursor = 0
+		m.suggestMarks = make(map[string]suggestMark)
+		m.currentView = ViewSuggestions
+		
+	case "D":
+		// Review likely duplicates
+		return m.openDedupe(), nil
+		
+	case "C":
+		// Resolve contacts left in conflict by a sync
+		return m.openConflicts(), nil
+		
+	case "E":
+		// Export the contacts shown as one vCard bundle
+		filename := "contacts-" + clock.Now().Format("20060102") + ".vcf"
+		return m, exportVCards(m.filtered, filename)
+		
 	case "/":
 		m.searchMode = true
 		m.searchQuery = ""
@@ -69,6 +104,30 @@ func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
 		// Show filter popup
 		m.showFilterPopup = true
 		
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// Jump to a saved view
+		i := int(msg.String()[0] - '1')
+		if m.cfg != nil && i < len(m.cfg.Views) {
+			return m.applyView(m.cfg.Views[i])
+		}
+		
+	case "o":
+		// Cycle sort mode
+		m.sortMode = (m.sortMode + 1) % sortModeCount
+		m.applyFilters()
+		m.message = "Sorted by " + m.sortMode.label()
+		return m, clearMessageAfter(3 * time.Second)
+		
+	case "O":
+		// Reverse sort direction
+		m.sortDesc = !m.sortDesc
+		m.applyFilters()
+		
+	case "V":
+		// Save the current search and filters as a named view
+		m.viewNameMode = true
+		m.viewNameInput = m.activeView
+		
 	case "d":
 		// Show interaction type selector
 		if m.cursor < len(m.filtered) {
@@ -118,6 +177,14 @@ func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.interactionNote = ""
 		}
 		
+	case "z":
+		// Snooze contact
+		if m.cursor < len(m.filtered) {
+			m.contactToMark = &m.filtered[m.cursor]
+			m.snoozeMode = true
+			m.snoozeInput = ""
+		}
+		
 	case "T":
 		// Quick type change
 		if m.cursor < len(m.filtered) {
@@ -125,6 +192,80 @@ func (m Model) updateList(msg tea.KeyMsg) (Model, tea.Cmd) {
 			m.entryView = m.currentView  // Capture where we came from
 			m.currentView = ViewQuickType
 		}
+		
+	case "u":
+		// Undo the last change
+		return m, m.undo()
+		
+	case "ctrl+r":
+		// Redo the last undone change
+		return m, m.redo()
+	}
+	
+	return m, nil
+}
+
+// updateViewName handles typing the name of a view to save
+func (m Model) updateViewName(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.viewNameMode = false
+		m.viewNameInput = ""
+		
+	case tea.KeyEnter:
+		name := strings.TrimSpace(m.viewNameInput)
+		m.viewNameMode = false
+		m.viewNameInput = ""
+		if name != "" {
+			return m.saveCurrentView(name)
+		}
+		
+	case tea.KeyBackspace:
+		if len(m.viewNameInput) > 0 {
+			m.viewNameInput = dropLastRune(m.viewNameInput)
+		}
+		
+	case tea.KeyRunes, tea.KeySpace:
+		m.viewNameInput += string(msg.Runes)
+	}
+	
+	return m, nil
+}
+
+// updateSnooze handles typing a snooze duration or date
+func (m Model) updateSnooze(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.snoozeMode = false
+		m.snoozeInput = ""
+		m.contactToMark = nil
+		
+	case tea.KeyEnter:
+		if m.contactToMark == nil {
+			m.snoozeMode = false
+			return m, nil
+		}
+		contact := *m.contactToMark
+		
+		// An empty input clears the snooze
+		if strings.TrimSpace(m.snoozeInput) == "" {
+			return m, m.snoozeContact(contact, nil)
+		}
+		
+		until, err := model.ParseDateInput(m.snoozeInput, clock.Now())
+		if err != nil {
+			m.message = err.Error()
+			return m, clearMessageAfter(3 * time.Second)
+		}
+		return m, m.snoozeContact(contact, &until)
+		
+	case tea.KeyBackspace:
+		if len(m.snoozeInput) > 0 {
+			m.snoozeInput = dropLastRune(m.snoozeInput)
+		}
+		
+	case tea.KeyRunes:
+		m.snoozeInput += string(msg.Runes)
 	}
 	
 	return m, nil
@@ -149,9 +290,10 @@ func (m Model) viewList() string {
 	
 	// Column headers - matching the actual column spacing
 	if len(m.filtered) > 0 {
-		columnHeaders := fmt.Sprintf("      %-30s  %4s  %-10s  %-8s  %-35s  %s",
+		columnHeaders := fmt.Sprintf("      %-30s  %4s  %-6s  %-10s  %-8s  %-35s  %s",
 			"NAME",
 			"DAYS",
+			"HEALTH",
 			"TYPE",
 			"STATE",
 			"COMPANY/ROLE",
@@ -177,17 +319,28 @@ func (m Model) viewList() string {
 	if listHeight < 1 {
 		listHeight = 1
 	}
+	
+	// Notes searches show a snippet line under each contact
+	showSnippets := m.searchQuery != "" && m.searchScope != scopeFields
+	rowHeight := 1
+	if showSnippets {
+		rowHeight = 2
+	}
+	visibleRows := listHeight / rowHeight
+	if visibleRows < 1 {
+		visibleRows = 1
+	}
 	startIdx := 0
 	
 	// Ensure cursor is visible
-	if m.cursor >= startIdx+listHeight {
-		startIdx = m.cursor - listHeight + 1
+	if m.cursor >= startIdx+visibleRows {
+		startIdx = m.cursor - visibleRows + 1
 	}
 	if m.cursor < startIdx {
 		startIdx = m.cursor
 	}
 	
-	endIdx := startIdx + listHeight
+	endIdx := startIdx + visibleRows
 	if endIdx > len(m.filtered) {
 		endIdx = len(m.filtered)
 	}
@@ -197,10 +350,14 @@ func (m Model) viewList() string {
 		line := m.renderContactLine(contact, i == m.cursor)
 		b.WriteString(line)
 		b.WriteString("\n")
+		if showSnippets {
+			b.WriteString(m.renderSnippetLine(contact))
+			b.WriteString("\n")
+		}
 	}
 	
 	// Fill empty space
-	for i := endIdx - startIdx; i < listHeight; i++ {
+	for i := (endIdx - startIdx) * rowHeight; i < listHeight; i++ {
 		b.WriteString("\n")
 	}
 	
@@ -213,6 +370,9 @@ func (m Model) viewList() string {
 // renderHeader renders the header
 func (m Model) renderHeader() string {
 	title := "Denote Contacts"
+	if clock.Shifted() {
+		title += " (as of " + clock.Now().Format("Mon Jan 2, 2006") + ", read-only)"
+	}
 	status := ""
 	
 	if len(m.contacts) == 0 {
@@ -224,27 +384,30 @@ func (m Model) renderHeader() string {
 			position = fmt.Sprintf("[%d/%d]", m.cursor+1, len(m.filtered))
 		}
 		
-		// Build status based on filter state
+		// Build status from the active view, search and filters
+		var active []string
+		if m.activeView != "" {
+			active = append(active, "view: "+m.activeView)
+		}
 		if m.searchQuery != "" {
-			status = fmt.Sprintf("%s %d of %d (search: %s)", position, len(m.filtered), len(m.contacts), m.searchQuery)
-		} else if m.filterType != "" {
-			status = fmt.Sprintf("%s %d of %d (type: %s)", position, len(m.filtered), len(m.contacts), m.filterType)
-		} else if m.filterState != "" {
-			status = fmt.Sprintf("%s %d of %d (state: %s)", position, len(m.filtered), len(m.contacts), m.filterState)
-		} else if m.filterStatus != "" {
-			statusLabel := m.filterStatus
-			switch m.filterStatus {
-			case "overdue":
-				statusLabel = "overdue"
-			case "needsAttention":
-				statusLabel = "due soon"
-			case "ok":
-				statusLabel = "good timing"
-			}
-			status = fmt.Sprintf("%s %d of %d (status: %s)", position, len(m.filtered), len(m.contacts), statusLabel)
+			active = append(active, fmt.Sprintf("search %s: %s", m.searchScope, m.searchQuery))
+		}
+		if !m.filters.isEmpty() {
+			active = append(active, m.filters.describe())
+		}
+		
+		if len(active) > 0 {
+			status = fmt.Sprintf("%s %d of %d (%s)", position, len(m.filtered), len(m.contacts), strings.Join(active, " • "))
 		} else {
 			status = fmt.Sprintf("%s %d contacts", position, len(m.filtered))
 		}
+		status += " • " + m.sortLabel()
+		if git := m.renderGitStatus(); git != "" {
+			status += " • " + git
+		}
+		if n := len(m.conflicts); n > 0 {
+			status += fmt.Sprintf(" • ⚠ %d in conflict (C)", n)
+		}
 	}
 	
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
@@ -252,8 +415,8 @@ func (m Model) renderHeader() string {
 	
 	// Calculate padding
 	totalWidth := m.width
-	titleLen := len(title)
-	statusLen := len(status)
+	titleLen := lipgloss.Width(title)
+	statusLen := lipgloss.Width(status)
 	padding := totalWidth - titleLen - statusLen - 2
 	if padding < 0 {
 		padding = 0
@@ -271,12 +434,19 @@ func (m Model) renderContactLine(contact model.Contact, selected bool) string {
 	}
 	
 	// Status indicator (overdue/attention/good/ok)
+	due := contact.DueState()
 	var status string
 	var statusStyle lipgloss.Style
-	if contact.IsOverdue() {
+	if contact.IsArchived() {
+		status = "a"
+		statusStyle = headerColor
+	} else if contact.IsSnoozed() {
+		status = "z"
+		statusStyle = snoozedColor
+	} else if due.Overdue {
 		status = "●"
 		statusStyle = overdueColor
-	} else if contact.NeedsAttention() {
+	} else if due.Attention {
 		status = "!"
 		statusStyle = attentionColor
 	} else if contact.IsWithinThreshold() {
@@ -301,10 +471,18 @@ func (m Model) renderContactLine(contact model.Contact, selected bool) string {
 	
 	// Name (fixed width) - FIRST main column
 	name := contact.Title
-	if len(name) > 30 {
-		name = name[:27] + "..."
+	if lipgloss.Width(name) > 30 {
+		name = truncateString(name, 27) + "..."
+	}
+	padding := strings.Repeat(" ", max(0, 30-lipgloss.Width(name)))
+	if match, ok := m.searchMatches[contact.FilePath]; ok && len(match.nameIndexes) > 0 {
+		lineStyle := baseColor
+		if selected {
+			lineStyle = selectedColor
+		}
+		name = highlightMatches(name, match.nameIndexes, lineStyle)
 	}
-	name = fmt.Sprintf("%-30s", name)
+	name += padding
 	
 	// Days since contact
 	days := contact.DaysSinceContact()
@@ -313,6 +491,9 @@ func (m Model) renderContactLine(contact model.Contact, selected bool) string {
 		daysStr = fmt.Sprintf("%4d", days)
 	}
 	
+	// Health score with its 30-day trend
+	healthStr := renderHealth(contact)
+	
 	// Relationship type - fixed width (10 chars for "recruiters")
 	relType := "          "
 	if contact.RelationshipType != "" {
@@ -353,19 +534,26 @@ func (m Model) renderContactLine(contact model.Contact, selected bool) string {
 	tagStr := ""
 	if len(displayTags) > 0 {
 		tagStr = "#" + strings.Join(displayTags, " #")
-		// Truncate if too long
-		if len(tagStr) > 30 {
-			tagStr = tagStr[:27] + "..."
-		}
+	}
+	
+	// A trigger that has made the contact due replaces the tags with its reason
+	if t := due.Trigger; t != nil && (due.Overdue || due.Attention) {
+		tagStr = "⚑ " + t.Reason
+	}
+	
+	// Truncate if too long
+	if lipgloss.Width(tagStr) > 30 {
+		tagStr = truncateString(tagStr, 27) + "..."
 	}
 	
 	// Build columnar line matching header order with proper spacing
-	line := fmt.Sprintf("%s%s %s  %s  %s  %s  %s  %s  %s",
+	line := fmt.Sprintf("%s%s %s  %s  %s  %s  %s  %s  %s  %s",
 		cursor,
 		statusStyle.Render(status),
 		styleIcon,
 		name,
 		daysStr,
+		healthStr,
 		relType,
 		state,
 		companyRole,
@@ -379,16 +567,106 @@ func (m Model) renderContactLine(contact model.Contact, selected bool) string {
 	return baseColor.Render(line)
 }
 
+// renderHealth renders the health score, coloured by band, with an arrow
+// for the change over the last 30 days
+func renderHealth(contact model.Contact) string {
+	score := contact.Health().Score
+	trend := contact.HealthTrend()
+	
+	arrow := "→"
+	switch {
+	case trend >= healthTrendThreshold:
+		arrow = "↑"
+	case trend <= -healthTrendThreshold:
+		arrow = "↓"
+	}
+	
+	style := overdueColor
+	switch {
+	case score >= 70:
+		style = goodColor
+	case score >= 40:
+		style = attentionColor
+	}
+	return style.Render(fmt.Sprintf("%4d %s", score, arrow))
+}
+
+// dropLastRune removes the last character of s, for backspace in text input
+func dropLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+// truncateString cuts s to at most n runes
+func truncateString(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// renderSnippetLine renders the matching note line shown under a contact
+func (m Model) renderSnippetLine(contact model.Contact) string {
+	snippet := m.searchMatches[contact.FilePath].snippet
+	if snippet == "" {
+		return ""
+	}
+	
+	prefix := "      ↳ "
+	maxLen := m.width - lipgloss.Width(prefix)
+	if maxLen > 3 && lipgloss.Width(snippet) > maxLen {
+		snippet = truncateString(snippet, maxLen-3) + "..."
+	}
+	
+	return snippetColor.Render(prefix + snippet)
+}
+
+// highlightMatches renders the matched byte offsets of s in matchColor and the
+// rest in base, so the surrounding line style survives the highlight resets
+func highlightMatches(s string, indexes []int, base lipgloss.Style) string {
+	matched := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		matched[i] = true
+	}
+	
+	var b strings.Builder
+	var run strings.Builder
+	runMatched := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(matchColor.Render(run.String()))
+		} else {
+			b.WriteString(base.Render(run.String()))
+		}
+		run.Reset()
+	}
+	
+	for i, r := range s {
+		if matched[i] != runMatched {
+			flush()
+			runMatched = matched[i]
+		}
+		run.WriteRune(r)
+	}
+	flush()
+	
+	return b.String()
+}
+
 // renderFooter renders the footer with hotkeys
 func (m Model) renderFooter() string {
 	// If in search mode, show search input at the bottom
 	if m.searchMode {
 		searchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
-		prompt := searchStyle.Render("Search: ")
+		prompt := searchStyle.Render(fmt.Sprintf("Search [%s]: ", m.searchScope))
 		query := m.searchQuery
 		cursor := searchStyle.Render("█")
 		
-		searchLine := prompt + query + cursor + " " + headerColor.Render("(fuzzy match, #tag for tags, Esc to clear)")
+		searchLine := prompt + query + cursor + " " + headerColor.Render("(fuzzy match, #tag for tags, @label for labels, Tab: scope, Esc to clear)")
 		
 		// Pad to full width
 		padding := m.width - lipgloss.Width(searchLine)
@@ -399,6 +677,22 @@ func (m Model) renderFooter() string {
 		return searchLine + "\n" + strings.Repeat(" ", m.width)
 	}
 	
+	// Snoozing a contact
+	if m.snoozeMode && m.contactToMark != nil {
+		promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		line := promptStyle.Render(fmt.Sprintf("Snooze %s until: ", m.contactToMark.Title)) + m.snoozeInput + promptStyle.Render("█") + " " +
+			headerColor.Render("(YYYY-MM-DD or 10d/2w/3m, empty to unsnooze, Esc to cancel)")
+		return line + "\n" + strings.Repeat(" ", m.width)
+	}
+	
+	// Naming a view to save
+	if m.viewNameMode {
+		promptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		line := promptStyle.Render("Save view as: ") + m.viewNameInput + promptStyle.Render("█") + " " +
+			headerColor.Render("(Enter to save, Esc to cancel)")
+		return line + "\n" + strings.Repeat(" ", m.width)
+	}
+	
 	// Normal footer
 	keys := []string{
 		"j/k:navigate",
@@ -407,10 +701,18 @@ func (m Model) renderFooter() string {
 		"s:state",
 		"T:type",
 		"b:bump",
+		"z:snooze",
+		"u/^r:undo/redo",
 		"e:edit",
 		"c:create",
 		"/:search",
 		"f:filter",
+		"A:agenda",
+		"R:review freq",
+		"D:duplicates",
+		"E:export vcf",
+		"o/O:sort",
+		"1-9/V:views",
 		"q:quit",
 	}
 	
@@ -424,6 +726,8 @@ func (m Model) renderFooter() string {
 	legendParts = append(legendParts, attentionColor.Render("!:soon"))
 	legendParts = append(legendParts, goodColor.Render("●:good"))
 	legendParts = append(legendParts, headerColor.Render("○:ok"))
+	legendParts = append(legendParts, snoozedColor.Render("z:snoozed"))
+	legendParts = append(legendParts, headerColor.Render("a:archived"))
 	
 	return headerColor.Render(strings.Join(keys, " • ")) + "\n" +
 		   strings.Join(legendParts, headerColor.Render(" • "))
@@ -5,7 +5,14 @@ import (
 	
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/conflict"
+	"github.com/mph-llm-experiments/denote-contacts/internal/dedupe"
+	"github.com/mph-llm-experiments/denote-contacts/internal/gitsync"
+	"github.com/mph-llm-experiments/denote-contacts/internal/index"
+	"github.com/mph-llm-experiments/denote-contacts/internal/journal"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/trigger"
 )
 
 // ViewMode represents the current view
@@ -19,6 +26,11 @@ const (
 	ViewFilter
 	ViewInteractionType
 	ViewQuickType
+	ViewAgenda
+	ViewSuggestions
+	ViewDedupe
+	ViewGitHistory
+	ViewConflicts
 )
 
 // Model represents the application state
@@ -26,6 +38,9 @@ type Model struct {
 	// Core state
 	contacts     []model.Contact
 	contactsDir  string
+	cfg          *config.Config
+	journal      *journal.Journal // Undo history; nil records nothing
+	git          *gitsync.Syncer  // Commits changes; nil when git is off
 	currentView  ViewMode
 	
 	// List view state
@@ -35,6 +50,37 @@ type Model struct {
 	
 	// Detail view state
 	selectedContact *model.Contact
+	detailParent    ViewMode         // The view to return to when leaving detail
+	detailHistory   []*model.Contact // Contacts left by jumping to a related one
+	confirmDelete   bool             // Waiting for y/n before trashing the contact
+	
+	// Agenda view state
+	agendaCursor int
+	
+	// Frequency suggestion review state
+	suggestCursor int
+	suggestMarks  map[string]suggestMark // Keyed by file path
+	
+	// Duplicate review state
+	dedupeCandidates []dedupe.Candidate
+	dedupeCursor     int
+	dedupePair       int             // Index of the open pair, -1 for the list
+	dedupeField      int             // Cursor in the open pair's field diff
+	dedupeTake       map[string]bool // Fields to take from the newer contact
+	dedupeConfirm    bool
+	
+	// Sync conflicts
+	conflicts      []conflict.File  // Found on the last load
+	conflictCursor int
+	conflictMerge  *conflict.Result // The open file's merge; nil in the file list
+	conflictField  int
+	
+	// Git state
+	gitStatus     *gitsync.Status // For the header, once read
+	gitLog        []gitsync.FileCommit
+	gitLogCursor  int
+	gitDiff       string
+	gitDiffScroll int
 	
 	// Contact logging state
 	contactToMark      *model.Contact
@@ -43,6 +89,10 @@ type Model struct {
 	interactionNote    string
 	contactLogStep     int // 0=type, 1=state, 2=note
 	
+	// Snooze prompt state
+	snoozeMode  bool   // true when typing a snooze duration or date
+	snoozeInput string
+	
 	// Edit view state
 	editingContact *model.Contact
 	editField      int
@@ -51,11 +101,19 @@ type Model struct {
 	// Search/filter state
 	searchQuery     string
 	searchMode      bool              // true when typing search
+	searchMatches   map[string]searchMatch // Fuzzy match details keyed by file path
+	searchScope     searchScope       // Fields, notes or both
+	bodyIndex       *index.Index      // Inverted index over contact bodies
 	filtered        []model.Contact
-	filterType      string            // Filter by relationship type
-	filterState     string            // Filter by state
-	filterStatus    string            // Filter by status (overdue, needsAttention, ok)
+	filters         filterSet         // Stacked type/state/status/tag filters
 	showFilterPopup bool              // Show filter dialog
+	filterTagInput  bool              // true when typing a tag in the filter dialog
+	filterTagValue  string
+	activeView      string            // Name of the saved view last applied
+	viewNameMode    bool              // true when typing a name to save the current view
+	viewNameInput   string
+	sortMode        sortMode          // Current list ordering
+	sortDesc        bool              // Reverse the ordering
 	
 	// UI state
 	width        int
@@ -67,9 +125,18 @@ type Model struct {
 }
 
 // NewModel creates a new application model
-func NewModel(contactsDir string) Model {
+func NewModel(contactsDir string, cfg *config.Config, j *journal.Journal, git *gitsync.Syncer) Model {
+	sortMode, sortDesc := sortName, false
+	if cfg != nil {
+		sortMode = parseSortMode(cfg.DefaultSort)
+		sortDesc = cfg.SortDescending
+	}
+	
 	return Model{
 		contactsDir:  contactsDir,
+		cfg:          cfg,
+		journal:      j,
+		git:          git,
 		currentView:  ViewList,
 		entryView:    ViewList, // Default to list view
 		selected:     make(map[string]bool),
@@ -78,9 +145,8 @@ func NewModel(contactsDir string) Model {
 		width:        80,  // Default width
 		height:       24,  // Default height
 		ready:        true, // Start ready
-		filterType:   "",  // Initialize as empty
-		filterState:  "",  // Initialize as empty
-		filterStatus: "",  // Initialize as empty
+		sortMode:     sortMode,
+		sortDesc:     sortDesc,
 	}
 }
 
@@ -88,6 +154,7 @@ func NewModel(contactsDir string) Model {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadContacts(),
+		m.checkGit(),
 	)
 }
 
@@ -112,6 +179,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.searchMode {
 				return m.updateSearch(msg)
 			}
+			if m.viewNameMode {
+				return m.updateViewName(msg)
+			}
+			if m.snoozeMode {
+				return m.updateSnooze(msg)
+			}
 			if m.showFilterPopup {
 				return m.updateFilter(msg)
 			}
@@ -126,18 +199,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateInteractionType(msg)
 		case ViewQuickType:
 			return m.updateQuickType(msg)
+		case ViewAgenda:
+			return m.updateAgenda(msg)
+		case ViewSuggestions:
+			return m.updateSuggestions(msg)
+		case ViewDedupe:
+			return m.updateDedupe(msg)
+		case ViewGitHistory:
+			return m.updateGitHistory(msg)
+		case ViewConflicts:
+			return m.updateConflicts(msg)
 		}
 		
 	case contactsLoadedMsg:
 		m.contacts = msg.contacts
-		m.filtered = m.contacts
+		m.bodyIndex = msg.bodyIndex
+		m.conflicts = msg.conflicts
+		trigger.Apply(m.contacts)
+		m.applyFilters()
+		
+		// Keep the open contact current, leaving it if its file is gone
+		if m.selectedContact != nil {
+			path := m.selectedContact.FilePath
+			m.selectedContact = nil
+			for i := range m.contacts {
+				if m.contacts[i].FilePath == path {
+					contact := m.contacts[i]
+					m.selectedContact = &contact
+					break
+				}
+			}
+			if m.selectedContact == nil && m.currentView == ViewDetail {
+				m.currentView = m.detailParent
+				m.detailHistory = nil
+			}
+		}
+		
+		if m.currentView == ViewDedupe {
+			// Pairs index into the contacts, so find them again
+			m = m.openDedupe()
+		}
+		if m.currentView == ViewConflicts {
+			m.conflictCursor = min(m.conflictCursor, max(len(m.conflicts)-1, 0))
+			m.conflictMerge = nil
+		}
 		return m, nil
 		
 	case contactUpdatedMsg:
-		// Update the contact in our lists
-		for i, c := range m.contacts {
+		// Update the contact, and any saved along with it, in our lists and
+		// keep the body index in step with the saved content
+		for _, updated := range append([]model.Contact{msg.contact}, msg.others...) {
+			for i, c := range m.contacts {
+				if c.FilePath == updated.FilePath {
+					m.contacts[i] = updated
+					if m.bodyIndex != nil {
+						m.bodyIndex.Update(updated)
+					}
+					break
+				}
+			}
+		}
+		
+		// Logging one contact can trigger others
+		trigger.Apply(m.contacts)
+		for _, c := range m.contacts {
 			if c.FilePath == msg.contact.FilePath {
-				m.contacts[i] = msg.contact
+				msg.contact = c
 				break
 			}
 		}
@@ -178,15 +305,72 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Return to entry view after quick type change
 			m.currentView = m.entryView  // Return to where we came from
 			m.contactToMark = nil
+		} else if m.snoozeMode {
+			m.snoozeMode = false
+			m.snoozeInput = ""
+			m.contactToMark = nil
 		}
 		
 		// Clear message after 3 seconds
 		return m, clearMessageAfter(3 * time.Second)
 		
+	case contactsSavedMsg:
+		// Several contacts changed on disk, so reload them all
+		m.message = msg.message
+		if m.currentView == ViewSuggestions {
+			m.currentView = ViewList
+			m.suggestMarks = nil
+		}
+		if m.currentView == ViewDedupe {
+			m.dedupePair = -1
+		}
+		return m, tea.Batch(m.loadContacts(), clearMessageAfter(3*time.Second))
+		
+	case contactDeletedMsg:
+		// Leave the deleted contact and reload without it
+		m.message = msg.message
+		if m.currentView == ViewDetail {
+			m.currentView = m.detailParent
+		}
+		m.selectedContact = nil
+		m.detailHistory = nil
+		return m, tea.Batch(m.loadContacts(), clearMessageAfter(3*time.Second))
+		
+	case gitStatusMsg:
+		if msg.err == nil {
+			m.gitStatus = &msg.status
+		}
+		return m, nil
+		
+	case gitLogMsg:
+		if msg.err != nil {
+			m.message = msg.err.Error()
+			return m, clearMessageAfter(5 * time.Second)
+		}
+		m.gitLog = msg.commits
+		return m, m.loadGitDiff()
+		
+	case gitDiffMsg:
+		if msg.err != nil {
+			m.gitDiff = msg.err.Error()
+		} else {
+			m.gitDiff = msg.diff
+		}
+		return m, nil
+		
+	case exportedMsg:
+		m.message = msg.message
+		return m, clearMessageAfter(5 * time.Second)
+		
 	case clearMessageMsg:
 		m.message = ""
 		return m, nil
 		
+	case errorMsg:
+		// Show command failures without leaving the current view
+		m.message = msg.err.Error()
+		return m, clearMessageAfter(5 * time.Second)
+		
 	case error:
 		m.err = msg
 		return m, nil
@@ -219,6 +403,16 @@ func (m Model) View() string {
 		view = m.viewInteractionType()
 	case ViewQuickType:
 		view = m.viewQuickType()
+	case ViewAgenda:
+		view = m.viewAgenda()
+	case ViewSuggestions:
+		view = m.viewSuggestions()
+	case ViewDedupe:
+		view = m.viewDedupe()
+	case ViewGitHistory:
+		view = m.viewGitHistory()
+	case ViewConflicts:
+		view = m.viewConflicts()
 	default:
 		view = m.viewList()
 	}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/index"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want searchQuery
+	}{
+		{"", searchQuery{}},
+		{"sarah chen", searchQuery{text: "sarah chen"}},
+		{"#Work acme", searchQuery{text: "acme", tags: []string{"work"}}},
+		{"@Mentor #oss #book-club", searchQuery{tags: []string{"oss", "book-club"}, labels: []string{"mentor"}}},
+		{"related:@Ann Type:Reports_To", searchQuery{related: []string{"@ann"}, relTypes: []string{"reports-to"}}},
+		{"RELATED:ann type:mentor bob", searchQuery{text: "bob", related: []string{"@ann"}, relTypes: []string{"mentor"}}},
+		{"# @ related: type:", searchQuery{text: "# @ related: type:"}}, // Bare prefixes are text
+	}
+	for _, tt := range tests {
+		if got := parseSearchQuery(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestContactMatchesSearch(t *testing.T) {
+	ann := model.Contact{
+		Title:   "Ann Lee",
+		Company: "Globex",
+		Email:   "ann@globex.example",
+		Label:   "@annie",
+		Tags:    []string{"contact", "Work"},
+		Relationships: []model.Relationship{
+			{Label: "@bob", Type: "reports-to"},
+		},
+		RelatedContactLabels: []string{"@cal"},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+		field string // Field of the best fuzzy match, when there is text
+	}{
+		{"", true, ""},
+		{"#work", true, ""},
+		{"#work #oss", false, ""},
+		{"@annie", true, ""},
+		{"@ann", false, ""}, // Labels match exactly
+		{"related:@bob", true, ""},
+		{"related:@cal", true, ""},
+		{"related:@cal type:reports-to", false, ""}, // Plain related labels have no type
+		{"type:reports-to", true, ""},
+		{"type:mentor", false, ""},
+		{"ann lee", true, "name"},
+		{"globx", true, "company"},
+		{"#work globex", true, "company"},
+		{"zzz", false, ""},
+	}
+	m := &Model{}
+	for _, tt := range tests {
+		match, ok := m.contactMatchesSearch(ann, parseSearchQuery(tt.query))
+		if ok != tt.want {
+			t.Errorf("contactMatchesSearch(%q) = %v, want %v", tt.query, ok, tt.want)
+			continue
+		}
+		if match.field != tt.field {
+			t.Errorf("contactMatchesSearch(%q) field = %q, want %q", tt.query, match.field, tt.field)
+		}
+	}
+}
+
+func TestDropLastRune(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"ab", "a"},
+		{"José", "Jos"},
+		{"Zürich 张", "Zürich "},
+	}
+	for _, tt := range tests {
+		got := dropLastRune(tt.in)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("dropLastRune(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSearchNotes(t *testing.T) {
+	contacts := []model.Contact{
+		{Title: "Ann", FilePath: "ann.md", Tags: []string{"work"}, Content: "## 2025-03-01 - meeting\n\nTalked about the garden"},
+		{Title: "Bob", FilePath: "bob.md", Content: "Plays chess"},
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"garden", "Ann"},
+		{"gard", "Ann"},
+		{"chess garden", ""},
+		{"g", "Ann, Bob"}, // Too short to search the notes, so nothing is filtered
+		{"g #work", "Ann"},
+	}
+	for _, tt := range tests {
+		m := &Model{contacts: contacts, bodyIndex: index.Build(contacts, nil), searchScope: scopeBody, searchQuery: tt.query}
+		m.applyFilters()
+		var got []string
+		for _, c := range m.filtered {
+			got = append(got, c.Title)
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("notes search %q = %v, want %s", tt.query, got, tt.want)
+		}
+	}
+}
@@ -1,19 +1,61 @@
 package ui
 
 import (
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mph-llm-experiments/denote-contacts/internal/index"
 	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/sahilm/fuzzy"
 )
 
+// searchScope selects which parts of a contact the search text looks at
+type searchScope int
+
+const (
+	scopeFields searchScope = iota // Frontmatter fields only
+	scopeBody                      // Body content (interaction notes) only
+	scopeAll                       // Both
+)
+
+// String returns the display name of the scope
+func (s searchScope) String() string {
+	switch s {
+	case scopeBody:
+		return "notes"
+	case scopeAll:
+		return "all"
+	default:
+		return "fields"
+	}
+}
+
+// searchQuery is a parsed search string: free text for fuzzy matching plus
+// exact #tag, @label, related:@label and type:relationship terms
+type searchQuery struct {
+	text     string
+	tags     []string
+	labels   []string
+	related  []string // Labels the contact must link to
+	relTypes []string // Relationship types the links must have
+}
+
+// searchMatch records how a contact matched the fuzzy part of a search
+type searchMatch struct {
+	score       int
+	field       string // Field with the best score (name, company, email, label, role)
+	nameIndexes []int  // Matched byte offsets in the title, for highlighting
+	snippet     string // Matching body line, when the body was searched
+}
+
 // updateSearch handles input in search mode
 func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEscape:
 		m.searchMode = false
 		m.searchQuery = ""
-		m.filtered = m.contacts
+		m.applyFilters()
 		m.cursor = 0
 		return m, nil
 		
@@ -22,14 +64,20 @@ func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
 		// Keep the search results
 		return m, nil
 		
+	case tea.KeyTab:
+		// Cycle search scope: fields → notes → all
+		m.searchScope = (m.searchScope + 1) % 3
+		m.applyFilters()
+		m.cursor = 0
+		
 	case tea.KeyBackspace:
 		if len(m.searchQuery) > 0 {
-			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+			m.searchQuery = dropLastRune(m.searchQuery)
 			m.applyFilters()
 			m.cursor = 0
 		}
 		
-	case tea.KeyRunes:
+	case tea.KeyRunes, tea.KeySpace:
 		m.searchQuery += string(msg.Runes)
 		m.applyFilters()
 		m.cursor = 0
@@ -38,81 +86,224 @@ func (m Model) updateSearch(msg tea.KeyMsg) (Model, tea.Cmd) {
 	return m, nil
 }
 
-// contactMatchesSearch checks if a contact matches the search query
-func (m *Model) contactMatchesSearch(contact model.Contact, query string) bool {
-	query = strings.ToLower(query)
+// parseSearchQuery splits a raw query into fuzzy text, #tags, @labels and
+// relationship terms
+func parseSearchQuery(raw string) searchQuery {
+	var q searchQuery
+	var words []string
 	
-	// Search in name
-	if strings.Contains(strings.ToLower(contact.Title), query) {
-		return true
+	for _, word := range strings.Fields(raw) {
+		lower := strings.ToLower(word)
+		switch {
+		case strings.HasPrefix(lower, "related:") && len(word) > len("related:"):
+			q.related = append(q.related, model.NormalizeLabel(word[len("related:"):]))
+		case strings.HasPrefix(lower, "type:") && len(word) > len("type:"):
+			q.relTypes = append(q.relTypes, model.NormalizeRelationshipType(word[len("type:"):]))
+		case strings.HasPrefix(word, "#") && len(word) > 1:
+			q.tags = append(q.tags, strings.ToLower(word[1:]))
+		case strings.HasPrefix(word, "@") && len(word) > 1:
+			q.labels = append(q.labels, strings.ToLower(word[1:]))
+		default:
+			words = append(words, word)
+		}
 	}
-	// Search in company
-	if strings.Contains(strings.ToLower(contact.Company), query) {
-		return true
+	q.text = strings.Join(words, " ")
+	
+	return q
+}
+
+// contactMatchesSearch checks if a contact matches the search query.
+// Tags and labels must match exactly; the remaining text is fuzzy matched
+// against name, company, email, label and role, keeping the best score.
+func (m *Model) contactMatchesSearch(contact model.Contact, query searchQuery) (searchMatch, bool) {
+	// Exact tag filtering
+	for _, want := range query.tags {
+		found := false
+		for _, tag := range contact.Tags {
+			if strings.ToLower(tag) == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return searchMatch{}, false
+		}
 	}
-	// Search in email
-	if strings.Contains(strings.ToLower(contact.Email), query) {
-		return true
+	
+	// Exact label lookup (labels are stored with or without the leading @)
+	for _, want := range query.labels {
+		if strings.ToLower(strings.TrimPrefix(contact.Label, "@")) != want {
+			return searchMatch{}, false
+		}
+	}
+	
+	// Relationship terms
+	if !matchesRelationships(contact, query.related, query.relTypes) {
+		return searchMatch{}, false
+	}
+	
+	if query.text == "" {
+		return searchMatch{}, true
+	}
+	
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"name", contact.Title},
+		{"company", contact.Company},
+		{"email", contact.Email},
+		{"label", contact.Label},
+		{"role", contact.Role},
 	}
-	// Search in tags
-	for _, tag := range contact.Tags {
-		if strings.Contains(strings.ToLower(tag), query) {
-			return true
+	
+	best := searchMatch{}
+	matched := false
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		matches := fuzzy.Find(query.text, []string{f.value})
+		if len(matches) == 0 {
+			continue
+		}
+		if !matched || matches[0].Score > best.score {
+			best.score = matches[0].Score
+			best.field = f.name
+			matched = true
+		}
+		if f.name == "name" {
+			best.nameIndexes = matches[0].MatchedIndexes
 		}
 	}
-	// Search in label
-	if strings.Contains(strings.ToLower(contact.Label), query) {
+	
+	return best, matched
+}
+
+// matchesRelationships checks related:@label and type: terms. With types,
+// the contact needs a relationship of one of those types to each label, or
+// to anyone when no label is given. Without types, related_contact_labels
+// count as links too.
+func matchesRelationships(contact model.Contact, related, types []string) bool {
+	if len(related) == 0 && len(types) == 0 {
 		return true
 	}
-	// Search in role
-	if strings.Contains(strings.ToLower(contact.Role), query) {
-		return true
+	links := func(label string) bool {
+		for _, r := range contact.Relationships {
+			if label != "" && model.NormalizeLabel(r.Label) != label {
+				continue
+			}
+			if len(types) == 0 {
+				return true
+			}
+			for _, t := range types {
+				if model.NormalizeRelationshipType(r.Type) == t {
+					return true
+				}
+			}
+		}
+		if len(types) == 0 {
+			for _, l := range contact.RelatedContactLabels {
+				if model.NormalizeLabel(l) == label {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	
+	if len(related) == 0 {
+		return links("")
+	}
+	for _, label := range related {
+		if !links(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSearch applies the query within the current search scope. Tag and
+// label terms always apply; the text goes to fields, notes or both.
+func (m *Model) matchSearch(contact model.Contact, query searchQuery, bodyHits map[string]index.Hit) (searchMatch, bool) {
+	terms := query
+	terms.text = ""
+	if _, ok := m.contactMatchesSearch(contact, terms); !ok {
+		return searchMatch{}, false
+	}
+	if query.text == "" {
+		return searchMatch{}, true
+	}
+	
+	var match searchMatch
+	fieldOK := false
+	if m.searchScope != scopeBody {
+		match, fieldOK = m.contactMatchesSearch(contact, query)
+	}
+	
+	hit, bodyOK := bodyHits[contact.FilePath]
+	if bodyOK {
+		match.snippet = hit.Snippet
+		if !fieldOK {
+			match.field = "body"
+		}
 	}
 	
-	return false
+	return match, fieldOK || bodyOK
 }
 
 // applyFilters applies search and filter criteria
 func (m *Model) applyFilters() {
 	m.filtered = []model.Contact{}
+	m.searchMatches = make(map[string]searchMatch)
+	
+	query := parseSearchQuery(m.searchQuery)
+	
+	// Body hits come from the inverted index rather than scanning content.
+	// Text too short to index, such as a single letter, doesn't filter the
+	// notes until more is typed.
+	if m.searchScope == scopeBody && len(index.Tokenize(query.text)) == 0 {
+		query.text = ""
+	}
+	var bodyHits map[string]index.Hit
+	if query.text != "" && m.searchScope != scopeFields && m.bodyIndex != nil {
+		bodyHits = m.bodyIndex.Search(query.text)
+	}
 	
 	for _, contact := range m.contacts {
 		// Apply search query
-		if m.searchQuery != "" && !m.contactMatchesSearch(contact, m.searchQuery) {
-			continue
-		}
-		
-		// Apply type filter
-		if m.filterType != "" && string(contact.RelationshipType) != m.filterType {
-			continue
+		if m.searchQuery != "" {
+			match, ok := m.matchSearch(contact, query, bodyHits)
+			if !ok {
+				continue
+			}
+			m.searchMatches[contact.FilePath] = match
 		}
 		
-		// Apply state filter
-		if m.filterState != "" && contact.State != m.filterState {
+		// Apply stacked filters
+		if !m.filters.matches(contact) {
 			continue
 		}
 		
-		// Apply status filter
-		if m.filterStatus != "" {
-			switch m.filterStatus {
-			case "overdue":
-				if !contact.IsOverdue() {
-					continue
-				}
-			case "needsAttention":
-				if !contact.NeedsAttention() {
-					continue
-				}
-			case "ok":
-				if !contact.IsWithinThreshold() {
-					continue
-				}
-			}
-		}
-		
 		m.filtered = append(m.filtered, contact)
 	}
 	
+	// Apply the configured sort order
+	m.sortContacts(m.filtered)
+	
+	// Rank fuzzy results by score, keeping the sort order for ties.
+	// Contacts that only matched in their notes sort after field matches.
+	if query.text != "" && m.searchScope != scopeBody {
+		sort.SliceStable(m.filtered, func(i, j int) bool {
+			a := m.searchMatches[m.filtered[i].FilePath]
+			b := m.searchMatches[m.filtered[j].FilePath]
+			if (a.field == "body") != (b.field == "body") {
+				return b.field == "body"
+			}
+			return a.score > b.score
+		})
+	}
+	
 	// Reset cursor if it's out of bounds
 	if m.cursor >= len(m.filtered) {
 		m.cursor = len(m.filtered) - 1
@@ -120,4 +311,4 @@ func (m *Model) applyFilters() {
 			m.cursor = 0
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// sortMode selects the list ordering
+type sortMode int
+
+const (
+	sortName sortMode = iota
+	sortDaysSince
+	sortDue
+	sortType
+	sortCompany
+	sortUpdated
+	sortBumps
+	sortCreated
+	sortHealth
+	sortModeCount
+)
+
+// sortModeNames are the config and header names of each sort mode
+var sortModeNames = []string{
+	"name",
+	"contacted",
+	"due",
+	"type",
+	"company",
+	"updated",
+	"bumps",
+	"created",
+	"health",
+}
+
+// String returns the name of the sort mode
+func (s sortMode) String() string {
+	return sortModeNames[s]
+}
+
+// label returns how the sort mode is shown in the list. Bumps sort by the
+// bumps since the last contact, as the detail view counts them, not the
+// stored bump_count.
+func (s sortMode) label() string {
+	if s == sortBumps {
+		return "bumps since contact"
+	}
+	return s.String()
+}
+
+// parseSortMode returns the sort mode with the given name, defaulting to name
+func parseSortMode(name string) sortMode {
+	for i, n := range sortModeNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return sortMode(i)
+		}
+	}
+	return sortName
+}
+
+// sortLabel describes the current sort for the header
+func (m Model) sortLabel() string {
+	arrow := "↑"
+	if m.sortDesc {
+		arrow = "↓"
+	}
+	return "sort: " + m.sortMode.label() + " " + arrow
+}
+
+// sortContacts orders contacts by the current sort mode and direction.
+// Contacts without a value for the sort key (no due date, no company, ...)
+// always go last, and names break ties.
+func (m Model) sortContacts(contacts []model.Contact) {
+	// Keys such as due dates and health are worked out once per contact,
+	// not on both sides of every comparison
+	type keyed struct {
+		contact model.Contact
+		key     sortValue
+		ok      bool
+		name    string
+	}
+	rows := make([]keyed, len(contacts))
+	for i, c := range contacts {
+		key, ok := sortKey(c, m.sortMode)
+		rows[i] = keyed{c, key, ok, strings.ToLower(c.Title)}
+	}
+	
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.ok != b.ok {
+			return a.ok
+		}
+		if cmp := a.key.compare(b.key); cmp != 0 {
+			if m.sortDesc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		
+		return a.name < b.name
+	})
+	
+	for i, r := range rows {
+		contacts[i] = r.contact
+	}
+}
+
+// sortValue is a sort key: text for string modes, num for everything else
+type sortValue struct {
+	text string
+	num  float64
+}
+
+// compare returns -1, 0 or 1
+func (v sortValue) compare(o sortValue) int {
+	switch {
+	case v.text != o.text:
+		return strings.Compare(v.text, o.text)
+	case v.num < o.num:
+		return -1
+	case v.num > o.num:
+		return 1
+	}
+	return 0
+}
+
+// sortKey returns the key for the mode, and false when the contact has no
+// value for it
+func sortKey(c model.Contact, mode sortMode) (sortValue, bool) {
+	switch mode {
+	case sortDaysSince:
+		days := c.DaysSinceContact()
+		if days == -1 {
+			return sortValue{num: math.MaxInt32}, true // Never contacted is the stalest
+		}
+		return sortValue{num: float64(days)}, true
+	case sortDue:
+		days, ok := c.DaysUntilDue()
+		return sortValue{num: float64(days)}, ok
+	case sortType:
+		return sortValue{text: string(c.RelationshipType)}, c.RelationshipType != ""
+	case sortCompany:
+		return sortValue{text: strings.ToLower(c.Company)}, c.Company != ""
+	case sortUpdated:
+		return sortValue{num: float64(c.UpdatedAt.Unix())}, !c.UpdatedAt.IsZero()
+	case sortBumps:
+		return sortValue{num: float64(c.BumpsSinceContact())}, true
+	case sortCreated:
+		return sortValue{num: float64(c.Date.Unix())}, !c.Date.IsZero()
+	case sortHealth:
+		return sortValue{num: float64(c.Health().Score)}, true
+	default:
+		return sortValue{text: strings.ToLower(c.Title)}, true
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// march returns noon on the given day of March 2025
+func march(d int) *time.Time {
+	t := time.Date(2025, time.March, d, 12, 0, 0, 0, time.Local)
+	return &t
+}
+
+func TestSortContacts(t *testing.T) {
+	previous := clock.Set(clock.Fixed(*march(15)))
+	t.Cleanup(func() { clock.Set(previous) })
+
+	jan := func(d int) time.Time { return time.Date(2025, time.January, d, 9, 0, 0, 0, time.Local) }
+	contacts := []model.Contact{
+		{Title: "Dee", LastContacted: march(5), CustomFrequencyDays: 30, Company: "Globex",
+			UpdatedAt: *march(10), Date: jan(3), BumpCount: 3, LastBumpDate: march(1)}, // Bumped before the last contact
+		{Title: "Cal", ContactStyle: model.StyleAmbient, RelationshipType: model.RelationshipWork,
+			Company: "acme", Date: jan(2), BumpCount: 5},
+		{Title: "bob", LastContacted: march(13), CustomFrequencyDays: 30, RelationshipType: model.RelationshipClose,
+			UpdatedAt: *march(12), Date: jan(1), BumpCount: 2, LastBumpDate: march(14)},
+		{Title: "Ann", LastContacted: march(5), CustomFrequencyDays: 30, RelationshipType: model.RelationshipWork,
+			Company: "Globex", UpdatedAt: *march(10), Date: jan(3)},
+	}
+
+	tests := []struct {
+		mode sortMode
+		desc bool
+		want string
+	}{
+		{sortName, false, "Ann bob Cal Dee"},
+		{sortName, true, "Dee Cal bob Ann"},
+		{sortDaysSince, false, "bob Ann Dee Cal"}, // Never contacted is the stalest
+		{sortDaysSince, true, "Cal Ann Dee bob"},
+		{sortDue, false, "Ann Dee bob Cal"}, // No due date goes last
+		{sortDue, true, "bob Ann Dee Cal"},
+		{sortType, false, "bob Ann Cal Dee"},
+		{sortCompany, false, "Cal Ann Dee bob"},
+		{sortCompany, true, "Ann Dee Cal bob"},
+		{sortUpdated, false, "Ann Dee bob Cal"},
+		{sortBumps, false, "Ann Cal Dee bob"}, // Only bumps since the last contact count
+		{sortBumps, true, "bob Ann Cal Dee"},
+		{sortCreated, false, "bob Cal Ann Dee"},
+	}
+	for _, tt := range tests {
+		m := Model{sortMode: tt.mode, sortDesc: tt.desc}
+		sorted := append([]model.Contact(nil), contacts...)
+		m.sortContacts(sorted)
+		var got []string
+		for _, c := range sorted {
+			got = append(got, c.Title)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("sort by %s (desc %v) = %s, want %s", tt.mode, tt.desc, strings.Join(got, " "), tt.want)
+		}
+	}
+
+	// Health scores are worked out by the model; check the order follows them
+	for _, desc := range []bool{false, true} {
+		m := Model{sortMode: sortHealth, sortDesc: desc}
+		sorted := append([]model.Contact(nil), contacts...)
+		m.sortContacts(sorted)
+		for i := 1; i < len(sorted); i++ {
+			a, b := sorted[i-1].Health().Score, sorted[i].Health().Score
+			if desc {
+				a, b = b, a
+			}
+			if a > b || (a == b && strings.ToLower(sorted[i-1].Title) > strings.ToLower(sorted[i].Title)) {
+				t.Errorf("sort by health (desc %v) puts %s before %s", desc, sorted[i-1].Title, sorted[i].Title)
+			}
+		}
+	}
+}
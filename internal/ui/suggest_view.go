@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// suggestMark is the decision on a frequency suggestion
+type suggestMark int
+
+const (
+	markNone suggestMark = iota
+	markAccept
+	markReject
+)
+
+// suggestItem is a contact with its frequency suggestion
+type suggestItem struct {
+	contact    model.Contact
+	suggestion model.FrequencySuggestion
+}
+
+// contactsSavedMsg reports that several contacts were saved
+type contactsSavedMsg struct {
+	message string
+	action  string // Names the change in the history; message if empty
+}
+
+// suggestItems returns the contacts with a frequency suggestion, biggest
+// change first
+func (m Model) suggestItems() []suggestItem {
+	var items []suggestItem
+	for _, c := range m.contacts {
+		if s, ok := c.SuggestFrequency(); ok {
+			items = append(items, suggestItem{contact: c, suggestion: s})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		di := abs(items[i].suggestion.Suggested - items[i].suggestion.Current)
+		dj := abs(items[j].suggestion.Suggested - items[j].suggestion.Current)
+		if di != dj {
+			return di > dj
+		}
+		return strings.ToLower(items[i].contact.Title) < strings.ToLower(items[j].contact.Title)
+	})
+	return items
+}
+
+// updateSuggestions handles input in the frequency suggestion review
+func (m Model) updateSuggestions(msg tea.KeyMsg) (Model, tea.Cmd) {
+	items := m.suggestItems()
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q":
+		// Leave without applying
+		m.currentView = ViewList
+		m.suggestMarks = nil
+
+	case "j", "down":
+		if m.suggestCursor < len(items)-1 {
+			m.suggestCursor++
+		}
+
+	case "k", "up":
+		if m.suggestCursor > 0 {
+			m.suggestCursor--
+		}
+
+	case "a", "r", " ":
+		if m.suggestCursor < len(items) {
+			path := items[m.suggestCursor].contact.FilePath
+			switch msg.String() {
+			case "a":
+				m.suggestMarks[path] = markAccept
+			case "r":
+				m.suggestMarks[path] = markReject
+			default:
+				m.suggestMarks[path] = (m.suggestMarks[path] + 1) % 3
+			}
+			if m.suggestCursor < len(items)-1 {
+				m.suggestCursor++
+			}
+		}
+
+	case "A", "R", "u":
+		// Mark every suggestion at once
+		mark := map[string]suggestMark{"A": markAccept, "R": markReject, "u": markNone}[msg.String()]
+		for _, item := range items {
+			m.suggestMarks[item.contact.FilePath] = mark
+		}
+
+	case "enter":
+		return m, m.applySuggestions(items)
+	}
+
+	if m.suggestCursor >= len(items) {
+		m.suggestCursor = len(items) - 1
+	}
+	if m.suggestCursor < 0 {
+		m.suggestCursor = 0
+	}
+
+	return m, nil
+}
+
+// applySuggestions returns a command that writes accepted suggestions to
+// custom_frequency_days and remembers rejected ones so they aren't offered again
+func (m Model) applySuggestions(items []suggestItem) tea.Cmd {
+	marks := m.suggestMarks
+	return m.journaled(func() tea.Msg {
+		accepted, rejected := 0, 0
+		for _, item := range items {
+			contact := item.contact
+			switch marks[contact.FilePath] {
+			case markAccept:
+				contact.CustomFrequencyDays = item.suggestion.Suggested
+				contact.RejectedFrequencyDays = 0
+				accepted++
+			case markReject:
+				contact.RejectedFrequencyDays = item.suggestion.Suggested
+				rejected++
+			default:
+				continue
+			}
+			if err := parser.SaveContactFile(contact); err != nil {
+				return errorMsg{err: fmt.Errorf("failed to save frequency for '%s': %v", contact.Title, err)}
+			}
+		}
+
+		return contactsSavedMsg{
+			message: fmt.Sprintf("Accepted %d and rejected %d frequency suggestions", accepted, rejected),
+		}
+	})
+}
+
+// viewSuggestions renders the frequency suggestion review
+func (m Model) viewSuggestions() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	b.WriteString(titleStyle.Render("Frequency Suggestions"))
+	b.WriteString(headerColor.Render("  based on the median gap between logged contacts"))
+	b.WriteString("\n")
+
+	// Show message if present
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("82")).
+			Bold(true)
+		b.WriteString(messageStyle.Render("→ " + m.message))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	items := m.suggestItems()
+	var lines []string
+	if len(items) == 0 {
+		lines = append(lines, emptyStyle.Render("  No suggestions: every contact's frequency matches its history"))
+	} else {
+		lines = append(lines, headerColor.Render(fmt.Sprintf("      %-30s  %-10s  %9s  %9s  %s",
+			"NAME", "TYPE", "CURRENT", "SUGGESTED", "MEDIAN GAP")))
+	}
+	for i, item := range items {
+		lines = append(lines, m.renderSuggestLine(item, i == m.suggestCursor))
+	}
+
+	// Header (3 lines) and footer (2 lines)
+	listHeight := m.height - 5
+	if m.message != "" {
+		listHeight--
+	}
+	if listHeight < 1 {
+		listHeight = 1
+	}
+	start := 0
+	if m.suggestCursor+1 >= listHeight {
+		start = m.suggestCursor + 2 - listHeight
+	}
+	end := start + listHeight
+	if end > len(lines) {
+		end = len(lines)
+	}
+	visible := lines[start:end]
+	for len(visible) < listHeight {
+		visible = append(visible, "")
+	}
+	b.WriteString(strings.Join(visible, "\n"))
+	b.WriteString("\n\n")
+
+	keys := []string{
+		"j/k:navigate",
+		"a:accept",
+		"r:reject",
+		"space:cycle",
+		"A/R/u:all accept/reject/unmark",
+		"enter:apply",
+		"esc:cancel",
+	}
+	b.WriteString(headerColor.Render(strings.Join(keys, " • ")))
+
+	return b.String()
+}
+
+// renderSuggestLine renders one suggestion with its mark
+func (m Model) renderSuggestLine(item suggestItem, selected bool) string {
+	cursor := "  "
+	if selected {
+		cursor = "> "
+	}
+
+	mark := baseColor.Render("[ ]")
+	switch m.suggestMarks[item.contact.FilePath] {
+	case markAccept:
+		mark = goodColor.Render("[✓]")
+	case markReject:
+		mark = overdueColor.Render("[✗]")
+	}
+
+	current := "none"
+	if item.suggestion.Current > 0 {
+		current = fmt.Sprintf("%d days", item.suggestion.Current)
+	}
+
+	name := item.contact.Title
+	if lipgloss.Width(name) > 30 {
+		name = truncateString(name, 27) + "..."
+	}
+
+	line := fmt.Sprintf(" %-30s  %-10s  %9s  %9s  %d days over %d gaps",
+		name,
+		item.contact.RelationshipType,
+		current,
+		fmt.Sprintf("%d days", item.suggestion.Suggested),
+		item.suggestion.MedianGap,
+		item.suggestion.Gaps,
+	)
+
+	style := baseColor
+	if selected {
+		style = selectedColor
+	}
+	return cursor + mark + style.Render(line)
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
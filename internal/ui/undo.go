@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+)
+
+// journaled wraps a command that changes files so everything it writes is
+// recorded as one operation that can be undone. The operation is named by
+// describeMsg from what the command reports. With git on, the files are
+// committed under the same name.
+func (m Model) journaled(cmd func() tea.Msg) tea.Cmd {
+	j, git := m.journal, m.git
+	if j == nil {
+		return cmd
+	}
+	wrapped := func() tea.Msg {
+		j.Begin()
+		msg := cmd()
+		op, err := j.Commit(describeMsg(msg))
+		if err != nil {
+			if errMsg, ok := msg.(errorMsg); ok {
+				return errMsg
+			}
+			return errorMsg{err: fmt.Errorf("saved, but the change can't be undone: %v", err)}
+		}
+		if op != nil {
+			if err := git.Record(op.Action, op.Paths()); err != nil {
+				return errorMsg{err: fmt.Errorf("saved, but git commit failed: %v", err)}
+			}
+		}
+		return msg
+	}
+	return m.thenCheckGit(wrapped)
+}
+
+// thenCheckGit runs cmd and then rereads the repository state for the
+// header, when git is on
+func (m Model) thenCheckGit(cmd tea.Cmd) tea.Cmd {
+	if m.git == nil {
+		return cmd
+	}
+	return tea.Sequence(cmd, m.checkGit())
+}
+
+// describeMsg names a change for the history and git log: by its action,
+// which leaves out the hints shown with the message, or else the message
+func describeMsg(msg tea.Msg) string {
+	switch msg := msg.(type) {
+	case contactUpdatedMsg:
+		return actionOr(msg.action, msg.message)
+	case contactsSavedMsg:
+		return actionOr(msg.action, msg.message)
+	case contactDeletedMsg:
+		return actionOr(msg.action, msg.message)
+	case errorMsg:
+		return "Failed: " + msg.err.Error()
+	}
+	return "Change"
+}
+
+// actionOr returns action, or message when there's none
+func actionOr(action, message string) string {
+	if action != "" {
+		return action
+	}
+	return message
+}
+
+// undo returns a command that reverts the most recent change, from this
+// session or an earlier one
+func (m Model) undo() tea.Cmd {
+	j, git := m.journal, m.git
+	return m.thenCheckGit(func() tea.Msg {
+		if err := parser.Writable(); err != nil {
+			return errorMsg{err: err}
+		}
+		op, err := j.Undo()
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		if err := git.Record("Undo: "+op.Action, op.Paths()); err != nil {
+			return errorMsg{err: fmt.Errorf("undone, but git commit failed: %v", err)}
+		}
+		return contactsSavedMsg{message: "Undid: " + op.Action}
+	})
+}
+
+// redo returns a command that applies the most recently undone change again
+func (m Model) redo() tea.Cmd {
+	j, git := m.journal, m.git
+	return m.thenCheckGit(func() tea.Msg {
+		if err := parser.Writable(); err != nil {
+			return errorMsg{err: err}
+		}
+		op, err := j.Redo()
+		if err != nil {
+			return errorMsg{err: err}
+		}
+		if err := git.Record("Redo: "+op.Action, op.Paths()); err != nil {
+			return errorMsg{err: fmt.Errorf("redone, but git commit failed: %v", err)}
+		}
+		return contactsSavedMsg{message: "Redid: " + op.Action}
+	})
+}
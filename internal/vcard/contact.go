@@ -0,0 +1,159 @@
+package vcard
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// bdayFormats match the birthday forms vCard allows, with or without the
+// year: "1990-05-01", "19900501", "--05-01" and "--0501", any time dropped
+var bdayFormats = []*regexp.Regexp{
+	regexp.MustCompile(`^(\d{4})-?(\d{2})-?(\d{2})`),
+	regexp.MustCompile(`^--(\d{2})-?(\d{2})`),
+}
+
+// Contact maps a card onto a new contact. The preferred email and phone
+// number are used, and any others are listed in the notes. CATEGORIES
+// become tags. The X-DENOTE properties Write adds are read back, with the
+// identifier kept only to match the contact it came from; the date and
+// file are left for the caller to set.
+func (c Card) Contact() model.Contact {
+	contact := model.Contact{
+		Title:    c.name(),
+		Role:     c.First("TITLE"),
+		Notes:    c.First("NOTE"),
+		Tags:     []string{"contact"},
+		Birthday: birthday(c.First("BDAY")),
+	}
+
+	emails := c.Preferred("EMAIL")
+	if len(emails) > 0 {
+		contact.Email = emails[0]
+	}
+	phones := c.Preferred("TEL")
+	if len(phones) > 0 {
+		contact.Phone = strings.TrimPrefix(phones[0], "tel:")
+	}
+	if orgs := c.Get("ORG"); len(orgs) > 0 {
+		contact.Company = orgs[0].Fields()[0]
+	}
+	if addrs := c.Get("ADR"); len(addrs) > 0 {
+		contact.Location = location(addrs[0].Fields())
+	}
+
+	// Profiles go to their own fields, the first other URL to the website
+	for _, url := range c.Preferred("URL") {
+		lower := strings.ToLower(url)
+		switch {
+		case strings.Contains(lower, "linkedin.com") && contact.LinkedIn == "":
+			contact.LinkedIn = url
+		case (strings.Contains(lower, "twitter.com") || strings.Contains(lower, "x.com/")) && contact.Twitter == "":
+			contact.Twitter = url
+		case contact.Website == "":
+			contact.Website = url
+		}
+	}
+
+	for _, p := range c.Get("CATEGORIES") {
+		for _, category := range p.List() {
+			tag := strings.ToLower(strings.Join(strings.Fields(category), "-"))
+			if !containsString(contact.Tags, tag) {
+				contact.Tags = append(contact.Tags, tag)
+			}
+		}
+	}
+
+	// Written by Write for a round trip
+	contact.Identifier = c.First(propIdentifier)
+	// Unknown values are left for the importer's defaults
+	contact.RelationshipType, _ = model.ParseRelationshipType(c.First(propRelationship))
+	contact.ContactStyle, _ = model.ParseContactStyle(c.First(propStyle))
+	if days, err := strconv.Atoi(c.First(propFrequency)); err == nil {
+		// Only a frequency other than the type's default is custom
+		def := model.Contact{RelationshipType: contact.RelationshipType}
+		if days != def.GetFrequencyDays() {
+			contact.CustomFrequencyDays = days
+		}
+	}
+
+	// Keep what doesn't fit a field where it can be seen
+	var extra []string
+	if len(emails) > 1 {
+		extra = append(extra, "Other emails: "+strings.Join(emails[1:], ", "))
+	}
+	if len(phones) > 1 {
+		extra = append(extra, "Other phones: "+strings.Join(phones[1:], ", "))
+	}
+	if len(extra) > 0 {
+		contact.Notes = strings.TrimSpace(strings.Join(append([]string{contact.Notes}, extra...), "\n"))
+	}
+	return contact
+}
+
+// name returns FN, or the given and family names from N when FN is empty
+func (c Card) name() string {
+	if fn := c.First("FN"); fn != "" {
+		return fn
+	}
+	if ns := c.Get("N"); len(ns) > 0 {
+		// Family;Given;Additional;Prefix;Suffix
+		f := append(ns[0].Fields(), "", "", "")
+		return strings.Join(strings.Fields(f[1]+" "+f[2]+" "+f[0]), " ")
+	}
+	if orgs := c.Get("ORG"); len(orgs) > 0 {
+		return orgs[0].Fields()[0]
+	}
+	return ""
+}
+
+// location joins the locality, region and country of an address, falling
+// back to the street when those are empty
+func location(f []string) string {
+	// PO box;Extended;Street;Locality;Region;Postal code;Country
+	f = append(f, make([]string, 7)...)
+	var parts []string
+	for _, part := range []string{f[3], f[4], f[6]} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 && f[2] != "" {
+		parts = append(parts, f[2])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// birthday converts a vCard birthday to YYYY-MM-DD, or MM-DD without a
+// year. Apple writes a missing year as 1604. Anything else is dropped.
+func birthday(bday string) string {
+	value := ""
+	if m := bdayFormats[1].FindStringSubmatch(bday); m != nil {
+		value = m[1] + "-" + m[2]
+	} else if m := bdayFormats[0].FindStringSubmatch(bday); m != nil {
+		value = m[2] + "-" + m[3]
+		if m[1] != "1604" {
+			value = m[1] + "-" + value
+		}
+	}
+	if value == "" {
+		return ""
+	}
+	e, err := model.ParseEvent("birthday", value)
+	if err != nil {
+		return ""
+	}
+	return e.String()
+}
+
+// containsString reports whether values has s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
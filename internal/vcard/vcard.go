@@ -0,0 +1,235 @@
+// Package vcard reads vCard 3.0 and 4.0 files and maps cards onto contacts.
+package vcard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Property is one content line of a card, such as
+// "EMAIL;TYPE=work:ann@example.com"
+type Property struct {
+	Name   string              // Upper case, without any group prefix
+	Params map[string][]string // Parameter names upper case, values as given
+	Value  string              // Still escaped
+}
+
+// Card is one BEGIN:VCARD ... END:VCARD block
+type Card struct {
+	Props []Property
+}
+
+// Get returns the card's properties with the name, in order
+func (c Card) Get(name string) []Property {
+	var props []Property
+	for _, p := range c.Props {
+		if p.Name == name {
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// First returns the text of the first property with the name, or ""
+func (c Card) First(name string) string {
+	if props := c.Get(name); len(props) > 0 {
+		return props[0].Text()
+	}
+	return ""
+}
+
+// Preferred returns the text of the preferred property with the name:
+// PREF=1 in 4.0 or TYPE=pref in 3.0, else the first. The rest follow.
+func (c Card) Preferred(name string) []string {
+	props := c.Get(name)
+	best := 0
+	for i, p := range props {
+		if p.IsPreferred() {
+			best = i
+			break
+		}
+	}
+	var values []string
+	for i, p := range props {
+		if v := p.Text(); v != "" {
+			if i == best {
+				values = append([]string{v}, values...)
+			} else {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// IsPreferred reports whether the property is marked as the one to use
+func (p Property) IsPreferred() bool {
+	if len(p.Params["PREF"]) > 0 && p.Params["PREF"][0] == "1" {
+		return true
+	}
+	for _, t := range p.Types() {
+		if t == "pref" {
+			return true
+		}
+	}
+	return false
+}
+
+// Types returns the TYPE parameter values, lower case
+func (p Property) Types() []string {
+	var types []string
+	for _, v := range p.Params["TYPE"] {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.ToLower(strings.Trim(t, `"`)); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+	return types
+}
+
+// Text returns the value unescaped
+func (p Property) Text() string {
+	return strings.TrimSpace(unescape(p.Value))
+}
+
+// Fields returns the components of a structured value such as N or ADR,
+// which are separated by semicolons
+func (p Property) Fields() []string {
+	var fields []string
+	for _, f := range splitEscaped(p.Value, ';') {
+		fields = append(fields, strings.TrimSpace(unescape(f)))
+	}
+	return fields
+}
+
+// List returns the items of a comma-separated value such as CATEGORIES
+func (p Property) List() []string {
+	var items []string
+	for _, item := range splitEscaped(p.Value, ',') {
+		if item = strings.TrimSpace(unescape(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Parse reads every card in r. Lines folded onto the next with a leading
+// space or tab are joined first.
+func Parse(r io.Reader) ([]Card, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var cards []Card
+	var card *Card
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n+1, err)
+		}
+		switch {
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, "VCARD"):
+			card = &Card{}
+		case p.Name == "END" && strings.EqualFold(p.Value, "VCARD"):
+			if card != nil {
+				cards = append(cards, *card)
+			}
+			card = nil
+		case card != nil:
+			card.Props = append(card.Props, p)
+		}
+	}
+	if card != nil {
+		return nil, fmt.Errorf("card not closed with END:VCARD")
+	}
+	return cards, nil
+}
+
+// parseLine splits "group.NAME;PARAM=a,b;PARAM2=c:value"
+func parseLine(line string) (Property, error) {
+	// The name and parameters end at the first colon outside quotes
+	colon, quoted := -1, false
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return Property{}, fmt.Errorf("no colon in %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	name := head[0]
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	p := Property{Name: strings.ToUpper(name), Params: make(map[string][]string), Value: line[colon+1:]}
+	for _, param := range head[1:] {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			// vCard 2.1 style bare type, such as ";WORK"
+			key, value = "TYPE", param
+		}
+		key = strings.ToUpper(key)
+		p.Params[key] = append(p.Params[key], strings.Trim(value, `"`))
+	}
+	return p, nil
+}
+
+// splitEscaped splits s at sep where it isn't escaped with a backslash
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescape undoes the backslash escapes of a text value
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package vcard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+const sample = "BEGIN:VCARD\r\n" +
+	"VERSION:3.0\r\n" +
+	"N:Chen;Sarah;;;\r\n" +
+	"FN:Sarah Chen\r\n" +
+	"ORG:Acme\\, Inc.;Research\r\n" +
+	"TITLE:Director\r\n" +
+	"EMAIL;TYPE=INTERNET,HOME:sarah@home.example\r\n" +
+	"EMAIL;TYPE=INTERNET,WORK,pref:sarah@acme.example\r\n" +
+	"item1.TEL;TYPE=CELL:+1 555 010 2000\r\n" +
+	"ADR;TYPE=HOME:;;1 Main St;Portland;OR;97201;USA\r\n" +
+	"BDAY:1604-05-01\r\n" +
+	"URL:https://www.linkedin.com/in/sarahchen\r\n" +
+	"URL:https://sarah.example\r\n" +
+	"NOTE:Met at the conference.\\nLikes tea.\r\n" +
+	"CATEGORIES:Work,Book Club\r\n" +
+	"END:VCARD\r\n" +
+	"BEGIN:VCARD\r\n" +
+	"VERSION:4.0\r\n" +
+	"N:Lee;Ann;;;\r\n" +
+	"EMAIL;PREF=1:ann@example.com\r\n" +
+	"BDAY:19880312\r\n" +
+	"NOTE:A long note that was folded\r\n" +
+	"  across two lines\r\n" +
+	"END:VCARD\r\n"
+
+func TestParse(t *testing.T) {
+	cards, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("Parse() = %d cards, want 2", len(cards))
+	}
+	if got := cards[1].First("NOTE"); got != "A long note that was folded across two lines" {
+		t.Errorf("folded NOTE = %q", got)
+	}
+	if got := cards[0].Get("ORG")[0].Fields(); !reflect.DeepEqual(got, []string{"Acme, Inc.", "Research"}) {
+		t.Errorf("ORG fields = %q", got)
+	}
+
+	if _, err := Parse(strings.NewReader("BEGIN:VCARD\nFN:Open\n")); err == nil {
+		t.Error("Parse() of an unclosed card succeeded")
+	}
+}
+
+func TestContact(t *testing.T) {
+	cards, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+		{"title", cards[0].Contact().Title, "Sarah Chen"},
+		{"email", cards[0].Contact().Email, "sarah@acme.example"},
+		{"phone", cards[0].Contact().Phone, "+1 555 010 2000"},
+		{"company", cards[0].Contact().Company, "Acme, Inc."},
+		{"role", cards[0].Contact().Role, "Director"},
+		{"location", cards[0].Contact().Location, "Portland, OR, USA"},
+		{"birthday without year", cards[0].Contact().Birthday, "05-01"},
+		{"linkedin", cards[0].Contact().LinkedIn, "https://www.linkedin.com/in/sarahchen"},
+		{"website", cards[0].Contact().Website, "https://sarah.example"},
+		{"tags", cards[0].Contact().Tags, []string{"contact", "work", "book-club"}},
+		{"notes", cards[0].Contact().Notes, "Met at the conference.\nLikes tea.\nOther emails: sarah@home.example"},
+		{"title from N", cards[1].Contact().Title, "Ann Lee"},
+		{"birthday", cards[1].Contact().Birthday, "1988-03-12"},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	contact := model.Contact{
+		Title:               "Sarah Chen",
+		Identifier:          "20240101T100000",
+		Tags:                []string{"contact", "work", "book-club"},
+		Email:               "sarah@acme.example",
+		Phone:               "+1 555 010 2000",
+		Company:             "Acme, Inc.",
+		Role:                "Director; Research",
+		Location:            "Portland, OR, USA",
+		Birthday:            "05-01",
+		Website:             "https://sarah.example",
+		LinkedIn:            "https://www.linkedin.com/in/sarahchen",
+		Notes:               "Met at the conference.\nLikes tea, and a very long note that has to be folded across more than one line.",
+		RelationshipType:    model.RelationshipWork,
+		ContactStyle:        model.StylePeriodic,
+		CustomFrequencyDays: 14,
+	}
+
+	var b strings.Builder
+	if err := Write(&b, []model.Contact{contact}); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	if !strings.Contains(out, "UID:urn:uuid:"+UID(contact.Identifier)+"\r\n") {
+		t.Errorf("no stable UID in %q", out)
+	}
+
+	cards, err := Parse(strings.NewReader(out))
+	if err != nil || len(cards) != 1 {
+		t.Fatalf("Parse() = %d cards, %v", len(cards), err)
+	}
+	if got := cards[0].Contact(); !reflect.DeepEqual(got, contact) {
+		t.Errorf("round trip =\n%+v\nwant\n%+v", got, contact)
+	}
+
+	// The default frequency for the type isn't made custom
+	contact.CustomFrequencyDays = 0
+	b.Reset()
+	Write(&b, []model.Contact{contact})
+	cards, _ = Parse(strings.NewReader(b.String()))
+	if got := cards[0].Contact().CustomFrequencyDays; got != 0 {
+		t.Errorf("CustomFrequencyDays = %d, want 0", got)
+	}
+}
+
+func TestUID(t *testing.T) {
+	a, b := UID("20240101T100000"), UID("20240101T100001")
+	if a != UID("20240101T100000") || a == b {
+		t.Errorf("UID() not stable and distinct: %s, %s", a, b)
+	}
+	if len(a) != 36 || a[14] != '5' {
+		t.Errorf("UID() = %s, want a version 5 UUID", a)
+	}
+}
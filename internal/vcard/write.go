@@ -0,0 +1,138 @@
+package vcard
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mph-llm-experiments/denote-contacts/internal/contentline"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+)
+
+// Properties written for fields vCard has no place for, read back on import
+const (
+	propIdentifier   = "X-DENOTE-IDENTIFIER"
+	propRelationship = "X-DENOTE-RELATIONSHIP-TYPE"
+	propFrequency    = "X-DENOTE-FREQUENCY-DAYS"
+	propStyle        = "X-DENOTE-CONTACT-STYLE"
+)
+
+// uidNamespace makes the UIDs of these contacts differ from any other
+// name-based UUIDs for the same identifiers
+const uidNamespace = "denote-contacts:"
+
+// UID returns a stable UUID for a contact, derived from its Denote
+// identifier so the same contact always gets the same one
+func UID(identifier string) string {
+	sum := sha1.Sum([]byte(uidNamespace + identifier))
+	sum[6] = sum[6]&0x0f | 0x50 // Version 5, name-based with SHA-1
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// Write writes the contacts as vCard 3.0 cards, one after another
+func Write(w io.Writer, contacts []model.Contact) error {
+	for _, c := range contacts {
+		if err := writeCard(w, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes the contacts to a .vcf file at path
+func Save(path string, contacts []model.Contact) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, contacts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// FileName names the .vcf file of one contact after its Denote file
+func FileName(c model.Contact) string {
+	return strings.TrimSuffix(filepath.Base(c.FilePath), ".md") + ".vcf"
+}
+
+// writeCard writes one contact
+func writeCard(w io.Writer, c model.Contact) error {
+	var lines []string
+	add := func(name, value string) {
+		if value != "" {
+			lines = append(lines, name+":"+value)
+		}
+	}
+
+	add("BEGIN", "VCARD")
+	add("VERSION", "3.0")
+	add("UID", "urn:uuid:"+UID(c.Identifier))
+	add("FN", contentline.Escape(c.Title))
+	add("N", nameField(c.Title))
+	add("EMAIL;TYPE=INTERNET", contentline.Escape(c.Email))
+	add("TEL", contentline.Escape(c.Phone))
+	add("ORG", contentline.Escape(c.Company))
+	add("TITLE", contentline.Escape(c.Role))
+	if c.Location != "" {
+		// Locality, where import reads it back from
+		add("ADR", ";;;"+contentline.Escape(c.Location)+";;;")
+	}
+	if e, err := model.ParseEvent("birthday", c.Birthday); err == nil && c.Birthday != "" {
+		if e.Year > 0 {
+			add("BDAY", fmt.Sprintf("%04d-%02d-%02d", e.Year, e.Month, e.Day))
+		} else {
+			add("BDAY", fmt.Sprintf("--%02d-%02d", e.Month, e.Day))
+		}
+	}
+	add("URL", contentline.Escape(c.Website))
+	add("URL", contentline.Escape(c.LinkedIn))
+	add("URL", contentline.Escape(c.Twitter))
+	add("NOTE", contentline.Escape(c.Notes))
+
+	var categories []string
+	for _, tag := range c.Tags {
+		if tag != "contact" {
+			categories = append(categories, contentline.Escape(tag))
+		}
+	}
+	add("CATEGORIES", strings.Join(categories, ","))
+
+	add(propIdentifier, contentline.Escape(c.Identifier))
+	add(propRelationship, contentline.Escape(string(c.RelationshipType)))
+	if days := c.GetFrequencyDays(); days > 0 {
+		add(propFrequency, strconv.Itoa(days))
+	}
+	add(propStyle, contentline.Escape(string(c.ContactStyle)))
+	if !c.UpdatedAt.IsZero() {
+		add("REV", c.UpdatedAt.UTC().Format("20060102T150405Z"))
+	}
+	add("END", "VCARD")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, contentline.Fold(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nameField splits a name into N's family and given names, taking the last
+// word as the family name
+func nameField(title string) string {
+	words := strings.Fields(title)
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) == 1 {
+		return ";" + contentline.Escape(words[0]) + ";;;"
+	}
+	last := len(words) - 1
+	return contentline.Escape(words[last]) + ";" + contentline.Escape(strings.Join(words[:last], " ")) + ";;;"
+}
@@ -1,20 +1,49 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mph-llm-experiments/denote-contacts/internal/cli"
+	"github.com/mph-llm-experiments/denote-contacts/internal/clock"
 	"github.com/mph-llm-experiments/denote-contacts/internal/config"
+	"github.com/mph-llm-experiments/denote-contacts/internal/gitsync"
+	"github.com/mph-llm-experiments/denote-contacts/internal/journal"
+	"github.com/mph-llm-experiments/denote-contacts/internal/model"
+	"github.com/mph-llm-experiments/denote-contacts/internal/parser"
+	"github.com/mph-llm-experiments/denote-contacts/internal/trigger"
 	"github.com/mph-llm-experiments/denote-contacts/internal/ui"
 )
 
 func main() {
-	if len(os.Args) > 1 && os.Args[1] == "--version" {
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	asOf := flag.String("as-of", "", "run as if today were `YYYY-MM-DD`")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: denote-contacts [flags] [command | directory]\n\nFlags:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\n%s", cli.Usage())
+	}
+	flag.Parse()
+	
+	if *showVersion {
 		fmt.Println("denote-contacts v0.1.0")
 		os.Exit(0)
 	}
+	
+	// Shift the clock to preview due dates on another day. Nothing can be
+	// changed meanwhile, so no dates or identifiers are written from it.
+	if *asOf != "" {
+		date, err := time.ParseInLocation("2006-01-02", *asOf, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid --as-of date %q: use YYYY-MM-DD", *asOf)
+		}
+		clock.Set(clock.AsOf(date))
+		parser.SetReadOnly(true)
+	}
 
 	// Load config
 	cfg, err := config.Load()
@@ -22,16 +51,95 @@ func main() {
 		log.Fatal("Failed to load config:", err)
 	}
 	
+	// Apply scheduling rules from config
+	if err := configureModel(cfg); err != nil {
+		log.Fatal("Invalid config: ", err)
+	}
+	
 	// Allow environment variable to override config
 	contactsDir := os.Getenv("DENOTE_CONTACTS_DIR")
 	if contactsDir == "" {
 		contactsDir = cfg.NotesDirectory
 	}
+	
+	// A single argument that names a directory rather than a command is the
+	// contacts directory, ahead of both
+	args := flag.Args()
+	if len(args) == 1 && !cli.IsCommand(args[0]) {
+		if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+			contactsDir = args[0]
+			args = nil
+		}
+	}
 
-	m := ui.NewModel(contactsDir)
+	// Record every change to contact and task files so it can be undone
+	historyPath, err := journal.Path()
+	if err != nil {
+		log.Fatal("Failed to find history: ", err)
+	}
+	j, err := journal.Open(historyPath)
+	if err != nil {
+		log.Fatal("Failed to load history: ", err)
+	}
+	j.SetLimit(cfg.HistoryLimit)
+	parser.SetBeforeWrite(j.Touch)
+
+	// Commit changes to git when the contacts directory is in a repository
+	var git *gitsync.Syncer
+	if cfg.Git.Enabled {
+		repo, err := gitsync.Open(contactsDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: git is enabled but", err)
+		} else if git, err = gitsync.NewSyncer(repo, cfg.Git.Commit); err != nil {
+			log.Fatal("Invalid config: ", err)
+		}
+	}
+
+	// Run a subcommand instead of the TUI when one is given
+	if len(args) > 0 {
+		env := cli.Env{ContactsDir: contactsDir, Config: cfg, Journal: j, Git: git, Out: os.Stdout}
+		err := cli.Run(env, args)
+		if ferr := git.Flush(); ferr != nil && err == nil {
+			err = fmt.Errorf("git commit failed: %v", ferr)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	m := ui.NewModel(contactsDir, cfg, j, git)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
 		log.Fatal("Error running program:", err)
 	}
-}
\ No newline at end of file
+	if err := git.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: git commit failed:", err)
+		os.Exit(1)
+	}
+}
+
+// configureModel passes the scheduling settings in the config to the model
+// and the trigger rules
+func configureModel(cfg *config.Config) error {
+	def := model.BumpPolicy{DeferDays: 7}
+	byType := make(map[model.RelationshipType]model.BumpPolicy)
+	for name, rule := range cfg.Bumps {
+		policy := model.BumpPolicy{DeferDays: rule.DeferDays, MaxBumps: rule.MaxBumps}
+		if name == "default" {
+			def = policy
+		} else {
+			byType[model.RelationshipType(name)] = policy
+		}
+	}
+	model.SetBumpPolicies(def, byType)
+	
+	rules, err := trigger.Compile(cfg)
+	if err != nil {
+		return err
+	}
+	trigger.SetRules(rules)
+	return nil
+}